@@ -0,0 +1,58 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// capabilityDescriptions describe the capabilities of clusterCapabilities
+// resources can require.
+var capabilityDescriptions = map[string]string{
+	"supports_mvs":                   "materialized views, which Cassandra 4.0+ only supports once materialized_views_enabled is set",
+	"supports_sai":                   "storage-attached indexes (Cassandra 5.0+, DSE 6.8+)",
+	"supports_vectors":               "the vector type (Cassandra 5.0+, DSE 6.9+)",
+	"supports_masking":               "dynamic data masking (Cassandra 5.0+)",
+	"supports_transient_replication": "transient replication (Cassandra 4.0+ with transient_replication_enabled set in cassandra.yaml)",
+}
+
+// clusterProbe returns what was found out about the cluster when the provider
+// first needed it. The cluster is probed once per provider, so resources can
+// check the features they use at plan time.
+func (c *ProviderConfig) clusterProbe(ctx context.Context) (*ClusterProbe, error) {
+	c.probeMu.Lock()
+	defer c.probeMu.Unlock()
+
+	if c.probe != nil {
+		return c.probe, nil
+	}
+	executor, err := c.sharedExecutor()
+	if err != nil {
+		return nil, err
+	}
+	defer executor.Close()
+
+	probe, err := probeCluster(ctx, executor, c.Mode, c.authKeyspace(ctx, executor))
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Cluster runs release_version %s", probe.ReleaseVersion)
+	c.probe = probe
+	return probe, nil
+}
+
+// requireCapability fails unless the cluster has capability, one of the keys
+// of capabilityDescriptions, for feature. When the cluster cannot be probed,
+// e.g. because it is created in the same apply, nothing is checked and the
+// cluster reports unsupported features itself.
+func (c *ProviderConfig) requireCapability(ctx context.Context, capability string, feature string) error {
+	probe, err := c.clusterProbe(ctx)
+	if err != nil {
+		log.Printf("[WARN] Probing the cluster failed (%s), not checking that it supports %s", err, feature)
+		return nil
+	}
+	if !clusterCapabilities(probe)[capability] {
+		return fmt.Errorf("%s requires %s, which the cluster does not support (release_version %s)", feature, capabilityDescriptions[capability], probe.ReleaseVersion)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package cassandra
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRequireCapability(t *testing.T) {
+	providerConfig := &ProviderConfig{
+		Mode:  modeCassandra,
+		probe: &ClusterProbe{Mode: modeCassandra, ReleaseVersion: "4.1.5", HasRolesTable: true},
+	}
+
+	if err := providerConfig.requireCapability(context.Background(), "supports_mvs", "cassandra_materialized_view"); err != nil {
+		t.Fatal(err)
+	}
+	err := providerConfig.requireCapability(context.Background(), "supports_vectors", "attribute embedding of type vector<float, 3>")
+	if err == nil {
+		t.Fatal("expected vectors to be rejected on Cassandra 4.1")
+	}
+	expected := "attribute embedding of type vector<float, 3> requires the vector type (Cassandra 5.0+, DSE 6.9+), which the cluster does not support (release_version 4.1.5)"
+	if err.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, err)
+	}
+}
+
+func TestRequireColumnCapabilities(t *testing.T) {
+	providerConfig := &ProviderConfig{
+		Mode:  modeCassandra,
+		probe: &ClusterProbe{Mode: modeCassandra, ReleaseVersion: "4.1.5"},
+	}
+	table := &Table{Columns: []TableColumn{{Name: "id", Type: "int"}, {Name: "email", Type: "text", Mask: "mask_inner(1, null)"}}}
+
+	err := requireColumnCapabilities(context.Background(), providerConfig, table)
+	if err == nil || !strings.HasPrefix(err.Error(), "mask of attribute email requires dynamic data masking") {
+		t.Fatalf("expected the mask to be rejected, got %v", err)
+	}
+
+	providerConfig.probe.ReleaseVersion = "5.0.2"
+	if err := requireColumnCapabilities(context.Background(), providerConfig, table); err != nil {
+		t.Fatal(err)
+	}
+}
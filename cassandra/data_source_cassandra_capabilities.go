@@ -0,0 +1,201 @@
+package cassandra
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCassandraCapabilities() *schema.Resource {
+	return &schema.Resource{
+		Description: "Probe the features supported by the connected cluster",
+		ReadContext: dataSourceCapabilitiesRead,
+		Schema: map[string]*schema.Schema{
+			"release_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Cassandra release version reported by the coordinator",
+			},
+			"supports_roles": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether roles can be managed with CQL, i.e. the roles table exists in the system keyspace of the provider",
+			},
+			"supports_mvs": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether materialized views can be created",
+			},
+			"supports_sai": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether storage-attached indexes can be created (Cassandra 5.0+, DSE 6.8+)",
+			},
+			"supports_vectors": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the vector type can be used (Cassandra 5.0+, DSE 6.9+)",
+			},
+			"supports_masking": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether columns can be masked with dynamic data masking (Cassandra 5.0+)",
+			},
+			"supports_transient_replication": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether replication factors can declare transient replicas, e.g. 3/1 (Cassandra 4.0+ with transient replication enabled in cassandra.yaml)",
+			},
+			"is_scylla": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the cluster runs ScyllaDB",
+			},
+			"is_dse": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the cluster runs DataStax Enterprise",
+			},
+			"is_keyspaces": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the cluster is Amazon Keyspaces",
+			},
+		},
+	}
+}
+
+// ClusterProbe holds what was found out about the connected cluster.
+type ClusterProbe struct {
+	Mode           string
+	ReleaseVersion string
+	DSEVersion     string
+	ScyllaVersion  string
+	IsKeyspaces    bool
+	HasRolesTable  bool
+	// Settings are nil before Cassandra 4.0, which has no system_views, and
+	// hold system.config in scylla mode.
+	Settings map[string]string
+}
+
+// probeCluster gathers the facts capabilities are derived from. Queries that
+// fail because the cluster does not know the table or column are not errors.
+func probeCluster(ctx context.Context, executor CQLExecutor, mode string, systemKeyspace string) (*ClusterProbe, error) {
+	probe := &ClusterProbe{Mode: mode}
+	var err error
+	if probe.ReleaseVersion, err = selectString(ctx, executor, "release_version", `SELECT release_version FROM system.local`); err != nil {
+		return nil, err
+	}
+
+	if probe.DSEVersion, err = selectString(ctx, executor, "dse_version", `SELECT dse_version FROM system.local`); err != nil {
+		log.Printf("Reading dse_version failed (%s), assuming the cluster does not run DSE", err)
+	}
+	if probe.ScyllaVersion, err = selectString(ctx, executor, "version", `SELECT version FROM system.versions WHERE key = 'local'`); err != nil {
+		log.Printf("Reading system.versions failed (%s), assuming the cluster does not run ScyllaDB", err)
+	}
+	_, err = selectString(ctx, executor, "keyspace_name", `SELECT keyspace_name FROM system_schema_mcs.keyspaces LIMIT 1`)
+	probe.IsKeyspaces = mode == modeAWSKeyspaces || err == nil
+
+	_, err = selectString(ctx, executor, "table_name", `SELECT table_name FROM system_schema.tables WHERE keyspace_name = ? AND table_name = 'roles'`, systemKeyspace)
+	if err != nil && err != gocql.ErrNotFound {
+		return nil, err
+	}
+	probe.HasRolesTable = err == nil
+
+	if settings, err := querySettings(ctx, executor, mode); err == nil {
+		probe.Settings = settings
+	} else {
+		log.Printf("Reading %s failed (%s)", systemView(mode, "settings"), err)
+	}
+	return probe, nil
+}
+
+// selectString returns column of the first row of a query, or
+// gocql.ErrNotFound when it returns no rows.
+func selectString(ctx context.Context, executor CQLExecutor, column string, query string, values ...interface{}) (string, error) {
+	rows, err := executor.Select(ctx, query, values...)
+	if err != nil {
+		return "", err
+	}
+	if len(rows) == 0 {
+		return "", gocql.ErrNotFound
+	}
+	value, _ := rows[0][column].(string)
+	return value, nil
+}
+
+// versionAtLeast tells whether a version such as 6.8.25 is at least major.minor.
+func versionAtLeast(version string, major int, minor int) bool {
+	parts := strings.SplitN(version, ".", 3)
+	versionMajor, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false
+	}
+	versionMinor := 0
+	if len(parts) > 1 {
+		if versionMinor, err = strconv.Atoi(parts[1]); err != nil {
+			return false
+		}
+	}
+	return versionMajor > major || versionMajor == major && versionMinor >= minor
+}
+
+// clusterCapabilities derives the feature flags of a cluster from a probe.
+func clusterCapabilities(probe *ClusterProbe) map[string]bool {
+	isScylla := probe.Mode == modeScylla || probe.ScyllaVersion != ""
+	isDSE := probe.DSEVersion != ""
+	isKeyspaces := probe.IsKeyspaces
+	isCassandra := !isScylla && !isDSE && !isKeyspaces
+
+	// Cassandra 4.0+ ships with materialized views disabled unless enabled
+	// in cassandra.yaml; the setting was renamed in 4.1.
+	mvsEnabled := true
+	for _, name := range []string{"materialized_views_enabled", "enable_materialized_views"} {
+		if value, ok := probe.Settings[name]; ok {
+			mvsEnabled = value == "true"
+		}
+	}
+
+	// Transient replication is experimental and disabled by default. When
+	// the settings could not be read, the cluster refuses it itself.
+	transientReplicationEnabled := probe.Settings == nil
+	for _, name := range []string{"transient_replication_enabled", "enable_transient_replication"} {
+		if value, ok := probe.Settings[name]; ok {
+			transientReplicationEnabled = value == "true"
+		}
+	}
+
+	return map[string]bool{
+		"supports_roles":                 probe.HasRolesTable,
+		"supports_mvs":                   !isKeyspaces && (isScylla || versionAtLeast(probe.ReleaseVersion, 3, 0)) && mvsEnabled,
+		"supports_sai":                   isCassandra && versionAtLeast(probe.ReleaseVersion, 5, 0) || isDSE && versionAtLeast(probe.DSEVersion, 6, 8),
+		"supports_vectors":               isCassandra && versionAtLeast(probe.ReleaseVersion, 5, 0) || isDSE && versionAtLeast(probe.DSEVersion, 6, 9),
+		"supports_masking":               isCassandra && versionAtLeast(probe.ReleaseVersion, 5, 0),
+		"supports_transient_replication": isCassandra && versionAtLeast(probe.ReleaseVersion, 4, 0) && transientReplicationEnabled,
+		"is_scylla":                      isScylla,
+		"is_dse":                         isDSE,
+		"is_keyspaces":                   isKeyspaces,
+	}
+}
+
+func dataSourceCapabilitiesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	probe, err := providerConfig.clusterProbe(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("capabilities")
+	d.Set("release_version", probe.ReleaseVersion)
+	for key, value := range clusterCapabilities(probe) {
+		d.Set(key, value)
+	}
+	return diags
+}
@@ -0,0 +1,117 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestClusterCapabilities(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		probe    *ClusterProbe
+		expected map[string]bool
+	}{
+		{
+			name:  "cassandra 3.11",
+			probe: &ClusterProbe{Mode: modeCassandra, ReleaseVersion: "3.11.16", HasRolesTable: true},
+			expected: map[string]bool{
+				"supports_roles": true, "supports_mvs": true, "supports_sai": false, "supports_vectors": false, "supports_masking": false,
+				"supports_transient_replication": false, "is_scylla": false, "is_dse": false, "is_keyspaces": false,
+			},
+		},
+		{
+			name:  "cassandra 5.0 without materialized views",
+			probe: &ClusterProbe{Mode: modeCassandra, ReleaseVersion: "5.0.2", HasRolesTable: true, Settings: map[string]string{"materialized_views_enabled": "false"}},
+			expected: map[string]bool{
+				"supports_roles": true, "supports_mvs": false, "supports_sai": true, "supports_vectors": true, "supports_masking": true,
+				"supports_transient_replication": false, "is_scylla": false, "is_dse": false, "is_keyspaces": false,
+			},
+		},
+		{
+			name:  "cassandra 4.1 with transient replication",
+			probe: &ClusterProbe{Mode: modeCassandra, ReleaseVersion: "4.1.5", HasRolesTable: true, Settings: map[string]string{"transient_replication_enabled": "true"}},
+			expected: map[string]bool{
+				"supports_roles": true, "supports_masking": false, "supports_transient_replication": true,
+				"is_scylla": false, "is_dse": false, "is_keyspaces": false,
+			},
+		},
+		{
+			name:  "dse 6.8",
+			probe: &ClusterProbe{Mode: modeCassandra, ReleaseVersion: "4.0.0.6851", DSEVersion: "6.8.51", HasRolesTable: true},
+			expected: map[string]bool{
+				"supports_roles": true, "supports_mvs": true, "supports_sai": true, "supports_vectors": false, "supports_masking": false,
+				"is_scylla": false, "is_dse": true, "is_keyspaces": false,
+			},
+		},
+		{
+			name:  "scylla",
+			probe: &ClusterProbe{Mode: modeCassandra, ReleaseVersion: "3.0.8", ScyllaVersion: "6.2.0", HasRolesTable: true},
+			expected: map[string]bool{
+				"supports_roles": true, "supports_mvs": true, "supports_sai": false, "supports_vectors": false,
+				"is_scylla": true, "is_dse": false, "is_keyspaces": false,
+			},
+		},
+		{
+			name:  "amazon keyspaces",
+			probe: &ClusterProbe{Mode: modeAWSKeyspaces, ReleaseVersion: "3.11.2", IsKeyspaces: true},
+			expected: map[string]bool{
+				"supports_roles": false, "supports_mvs": false, "supports_sai": false, "supports_vectors": false,
+				"is_scylla": false, "is_dse": false, "is_keyspaces": true,
+			},
+		},
+	} {
+		capabilities := clusterCapabilities(test.probe)
+		for key, expected := range test.expected {
+			if capabilities[key] != expected {
+				t.Fatalf("%s: expected %s to be %t", test.name, key, expected)
+			}
+		}
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	for _, test := range []struct {
+		version  string
+		major    int
+		minor    int
+		expected bool
+	}{
+		{"6.8.25", 6, 8, true},
+		{"6.7.9", 6, 8, false},
+		{"7.0", 6, 9, true},
+		{"5", 5, 0, true},
+		{"", 3, 0, false},
+	} {
+		if result := versionAtLeast(test.version, test.major, test.minor); result != test.expected {
+			t.Fatalf("expected versionAtLeast(%q, %d, %d) to be %t", test.version, test.major, test.minor, test.expected)
+		}
+	}
+}
+
+func TestProbeCluster(t *testing.T) {
+	executor := newMockCQLExecutor()
+	executor.rows["SELECT release_version FROM system.local"] = []map[string]interface{}{{"release_version": "5.0.2"}}
+	executor.errors["SELECT dse_version FROM system.local"] = errors.New("Undefined column name dse_version")
+	executor.rows["SELECT table_name FROM system_schema.tables WHERE keyspace_name = ? AND table_name = 'roles' [system_auth]"] = []map[string]interface{}{{"table_name": "roles"}}
+	executor.rows["SELECT name, value FROM system_views.settings"] = []map[string]interface{}{
+		{"name": "materialized_views_enabled", "value": "false"},
+		{"name": "data_file_directories", "value": nil},
+	}
+
+	probe, err := probeCluster(context.Background(), executor, modeCassandra, "system_auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if probe.ReleaseVersion != "5.0.2" || probe.DSEVersion != "" || probe.ScyllaVersion != "" || probe.IsKeyspaces || !probe.HasRolesTable {
+		t.Fatalf("unexpected probe %+v", probe)
+	}
+	if len(probe.Settings) != 1 || probe.Settings["materialized_views_enabled"] != "false" {
+		t.Fatalf("expected settings without null values, got %v", probe.Settings)
+	}
+
+	executor.errors["SELECT release_version FROM system.local"] = errors.New("unavailable")
+	if _, err := probeCluster(context.Background(), executor, modeCassandra, "system_auth"); err == nil {
+		t.Fatal("expected the probe to fail without release_version")
+	}
+}
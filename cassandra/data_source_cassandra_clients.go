@@ -0,0 +1,179 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCassandraClients() *schema.Resource {
+	return &schema.Resource{
+		Description: "List the clients connected to the coordinator from the system_views.clients virtual table (Cassandra 4.0+), or from system.clients in scylla mode",
+		ReadContext: dataSourceClientsRead,
+		Schema: map[string]*schema.Schema{
+			"clients": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Connected clients, sorted by address and port",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"address": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Address of the client",
+						},
+						"port": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Port of the client",
+						},
+						"hostname": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Hostname of the client",
+						},
+						"username": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Role the client is authenticated as, empty before authentication",
+						},
+						"connection_stage": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Stage of the connection, e.g. ready",
+						},
+						"driver_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the driver reported by the client",
+						},
+						"driver_version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Version of the driver reported by the client",
+						},
+						"protocol_version": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Native protocol version of the connection",
+						},
+						"ssl_enabled": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the connection is encrypted",
+						},
+						"ssl_protocol": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "TLS protocol of an encrypted connection",
+						},
+					},
+				},
+			},
+			"unencrypted_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of connected clients not using TLS",
+			},
+		},
+	}
+}
+
+// Client is a connection to the coordinator as listed in system_views.clients.
+type Client struct {
+	Address         string
+	Port            int
+	Hostname        string
+	Username        string
+	ConnectionStage string
+	DriverName      string
+	DriverVersion   string
+	ProtocolVersion int
+	SSLEnabled      bool
+	SSLProtocol     string
+}
+
+func queryClients(ctx context.Context, executor CQLExecutor, mode string) ([]*Client, error) {
+	query := fmt.Sprintf(`SELECT address, port, hostname, username, connection_stage, driver_name, driver_version, protocol_version, ssl_enabled, ssl_protocol FROM %s`, systemView(mode, "clients"))
+	rows, err := executor.Select(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	clients := make([]*Client, 0, len(rows))
+	for _, row := range rows {
+		client := &Client{}
+		client.Address, _ = row["address"].(string)
+		client.Port, _ = row["port"].(int)
+		client.Hostname, _ = row["hostname"].(string)
+		client.Username, _ = row["username"].(string)
+		client.ConnectionStage, _ = row["connection_stage"].(string)
+		client.DriverName, _ = row["driver_name"].(string)
+		client.DriverVersion, _ = row["driver_version"].(string)
+		client.ProtocolVersion, _ = row["protocol_version"].(int)
+		client.SSLEnabled, _ = row["ssl_enabled"].(bool)
+		client.SSLProtocol, _ = row["ssl_protocol"].(string)
+		clients = append(clients, client)
+	}
+	return clients, nil
+}
+
+// flattenClients lists the clients sorted by address and port and counts the
+// unencrypted ones.
+func flattenClients(clients []*Client) ([]interface{}, int) {
+	sort.Slice(clients, func(i, j int) bool {
+		if clients[i].Address != clients[j].Address {
+			return clients[i].Address < clients[j].Address
+		}
+		return clients[i].Port < clients[j].Port
+	})
+
+	flattened := make([]interface{}, 0, len(clients))
+	unencrypted := 0
+	for _, client := range clients {
+		if !client.SSLEnabled {
+			unencrypted++
+		}
+		flattened = append(flattened, map[string]interface{}{
+			"address":          client.Address,
+			"port":             client.Port,
+			"hostname":         client.Hostname,
+			"username":         client.Username,
+			"connection_stage": client.ConnectionStage,
+			"driver_name":      client.DriverName,
+			"driver_version":   client.DriverVersion,
+			"protocol_version": client.ProtocolVersion,
+			"ssl_enabled":      client.SSLEnabled,
+			"ssl_protocol":     client.SSLProtocol,
+		})
+	}
+	return flattened, unencrypted
+}
+
+func dataSourceClientsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	clients, err := queryClients(ctx, executor, providerConfig.Mode)
+	if err != nil {
+		if providerConfig.Mode == modeScylla {
+			return diag.Errorf("cannot read %s: %s", systemView(providerConfig.Mode, "clients"), err)
+		}
+		return diag.Errorf("cannot read system_views.clients, which requires Cassandra 4.0 or later: %s", err)
+	}
+
+	flattened, unencrypted := flattenClients(clients)
+	d.SetId("clients")
+	d.Set("clients", flattened)
+	d.Set("unencrypted_count", unencrypted)
+	return diags
+}
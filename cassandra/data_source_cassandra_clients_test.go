@@ -0,0 +1,19 @@
+package cassandra
+
+import "testing"
+
+func TestFlattenClients(t *testing.T) {
+	flattened, unencrypted := flattenClients([]*Client{
+		{Address: "10.0.0.2", Port: 50000, Username: "app", SSLEnabled: true, SSLProtocol: "TLSv1.3"},
+		{Address: "10.0.0.1", Port: 50001, Username: "legacy"},
+		{Address: "10.0.0.1", Port: 40000, Username: "app", SSLEnabled: true},
+	})
+
+	if unencrypted != 1 {
+		t.Fatalf("expected 1 unencrypted client, got %d", unencrypted)
+	}
+	first := flattened[0].(map[string]interface{})
+	if first["address"] != "10.0.0.1" || first["port"] != 40000 {
+		t.Fatalf("expected clients sorted by address and port, got %v", flattened)
+	}
+}
@@ -0,0 +1,125 @@
+package cassandra
+
+import (
+	"context"
+	"sort"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCassandraClusterInfo() *schema.Resource {
+	return &schema.Resource{
+		Description: "Read the name, versions, partitioner and datacenters of the cluster",
+		ReadContext: dataSourceClusterInfoRead,
+		Schema: map[string]*schema.Schema{
+			"cluster_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the cluster",
+			},
+			"release_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Cassandra release version of the coordinator, e.g. 4.1.3. ScyllaDB reports the Cassandra version it is compatible with",
+			},
+			"major_version": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Major version of release_version, for gating version-specific features",
+			},
+			"cql_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "CQL version of the coordinator",
+			},
+			"partitioner": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Partitioner class of the cluster",
+			},
+			"local_datacenter": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Datacenter of the coordinator",
+			},
+			"datacenters": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Datacenters of the nodes known to the coordinator, sorted",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// ClusterInfo is the description of the cluster stored in system.local.
+type ClusterInfo struct {
+	ClusterName    string
+	ReleaseVersion string
+	CQLVersion     string
+	Partitioner    string
+	Datacenter     string
+}
+
+func queryClusterInfo(ctx context.Context, executor CQLExecutor) (*ClusterInfo, error) {
+	rows, err := executor.Select(ctx, `SELECT cluster_name, release_version, cql_version, partitioner, data_center FROM system.local`)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, gocql.ErrNotFound
+	}
+	info := &ClusterInfo{}
+	info.ClusterName, _ = rows[0]["cluster_name"].(string)
+	info.ReleaseVersion, _ = rows[0]["release_version"].(string)
+	info.CQLVersion, _ = rows[0]["cql_version"].(string)
+	info.Partitioner, _ = rows[0]["partitioner"].(string)
+	info.Datacenter, _ = rows[0]["data_center"].(string)
+	return info, nil
+}
+
+// sortedDatacenters returns the datacenters of a node count per datacenter, sorted.
+func sortedDatacenters(nodeCounts map[string]int) []string {
+	datacenters := make([]string, 0, len(nodeCounts))
+	for datacenter := range nodeCounts {
+		datacenters = append(datacenters, datacenter)
+	}
+	sort.Strings(datacenters)
+	return datacenters
+}
+
+func dataSourceClusterInfoRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	info, err := queryClusterInfo(ctx, executor)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	majorVersion, err := parseMajorVersion(info.ReleaseVersion)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	nodeCounts, err := clusterDatacenterNodeCounts(ctx, executor)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(info.ClusterName)
+	d.Set("cluster_name", info.ClusterName)
+	d.Set("release_version", info.ReleaseVersion)
+	d.Set("major_version", majorVersion)
+	d.Set("cql_version", info.CQLVersion)
+	d.Set("partitioner", info.Partitioner)
+	d.Set("local_datacenter", info.Datacenter)
+	d.Set("datacenters", sortedDatacenters(nodeCounts))
+	return diags
+}
@@ -0,0 +1,22 @@
+package cassandra
+
+import "testing"
+
+func TestSortedDatacenters(t *testing.T) {
+	datacenters := sortedDatacenters(map[string]int{"us-east": 3, "eu-west": 3, "ap-south": 1})
+	if len(datacenters) != 3 || datacenters[0] != "ap-south" || datacenters[1] != "eu-west" || datacenters[2] != "us-east" {
+		t.Fatalf("expected sorted datacenters, got %v", datacenters)
+	}
+}
+
+func TestParseMajorVersion(t *testing.T) {
+	for version, expected := range map[string]int{"4.1.3": 4, "5.0-beta1": 5, "3.11.16": 3} {
+		major, err := parseMajorVersion(version)
+		if err != nil || major != expected {
+			t.Fatalf("expected major version %d of %s, got %d (%v)", expected, version, major, err)
+		}
+	}
+	if _, err := parseMajorVersion("unknown"); err == nil {
+		t.Fatal("expected an error for an unparseable version")
+	}
+}
@@ -0,0 +1,156 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+)
+
+func dataSourceCassandraEffectivePermissions() *schema.Resource {
+	return &schema.Resource{
+		Description: "List the permissions of a role, including those inherited through the roles granted to it",
+		ReadContext: dataSourceEffectivePermissionsRead,
+		Schema: map[string]*schema.Schema{
+			"role": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Role to list the permissions of",
+			},
+			"include_inherited": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Include the permissions of the roles granted to the role. Set to false to only list the permissions granted to the role directly",
+			},
+			"permissions": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Permissions sorted by resource, permission and role",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"resource": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Resource the permission applies to as listed by Cassandra, e.g. <table app.events>",
+						},
+						"permission": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Permission, e.g. SELECT",
+						},
+						"granted_to": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Role the permission is granted to, either the role itself or a role granted to it",
+						},
+						"inherited": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the permission is inherited from another role",
+						},
+					},
+				},
+			},
+			"resources": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Resources the role has any permission on, sorted",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// Permission is a row of LIST PERMISSIONS.
+type Permission struct {
+	Role       string
+	Resource   string
+	Permission string
+}
+
+func generateListPermissionsQueryString(role string, includeInherited bool) string {
+	query := fmt.Sprintf(`LIST ALL PERMISSIONS OF %s`, cql.QuoteIdentifier(role))
+	if !includeInherited {
+		query += " NORECURSIVE"
+	}
+	return query
+}
+
+func queryPermissions(ctx context.Context, executor CQLExecutor, role string, includeInherited bool) ([]Permission, error) {
+	query := generateListPermissionsQueryString(role, includeInherited)
+	log.Printf("Executing query: %s", query)
+	rows, err := executor.Select(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := make([]Permission, 0, len(rows))
+	for _, row := range rows {
+		permission := Permission{}
+		permission.Role, _ = row["role"].(string)
+		permission.Resource, _ = row["resource"].(string)
+		permission.Permission, _ = row["permission"].(string)
+		permissions = append(permissions, permission)
+	}
+	return permissions, nil
+}
+
+// flattenPermissions lists the permissions of role sorted by resource,
+// permission and role, together with the distinct resources.
+func flattenPermissions(role string, permissions []Permission) ([]interface{}, []string) {
+	sort.Slice(permissions, func(i, j int) bool {
+		a, b := permissions[i], permissions[j]
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		if a.Permission != b.Permission {
+			return a.Permission < b.Permission
+		}
+		return a.Role < b.Role
+	})
+
+	flattened := make([]interface{}, 0, len(permissions))
+	resources := []string{}
+	for i, permission := range permissions {
+		flattened = append(flattened, map[string]interface{}{
+			"resource":   permission.Resource,
+			"permission": permission.Permission,
+			"granted_to": permission.Role,
+			"inherited":  permission.Role != role,
+		})
+		if i == 0 || permission.Resource != permissions[i-1].Resource {
+			resources = append(resources, permission.Resource)
+		}
+	}
+	return flattened, resources
+}
+
+func dataSourceEffectivePermissionsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	role := d.Get("role").(string)
+	includeInherited := d.Get("include_inherited").(bool)
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	permissions, err := queryPermissions(ctx, executor, role, includeInherited)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	flattened, resources := flattenPermissions(role, permissions)
+	d.SetId(fmt.Sprintf("%s:%t", role, includeInherited))
+	d.Set("permissions", flattened)
+	d.Set("resources", resources)
+	return diags
+}
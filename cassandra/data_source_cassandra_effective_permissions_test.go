@@ -0,0 +1,39 @@
+package cassandra
+
+import "testing"
+
+func TestGenerateListPermissionsQueryString(t *testing.T) {
+	if query := generateListPermissionsQueryString(`app"ro`, true); query != `LIST ALL PERMISSIONS OF "app""ro"` {
+		t.Fatalf("unexpected query %s", query)
+	}
+	if query := generateListPermissionsQueryString("app", false); query != `LIST ALL PERMISSIONS OF "app" NORECURSIVE` {
+		t.Fatalf("unexpected query %s", query)
+	}
+}
+
+func TestFlattenPermissions(t *testing.T) {
+	flattened, resources := flattenPermissions("app", []Permission{
+		{Role: "readers", Resource: "<table app.events>", Permission: "SELECT"},
+		{Role: "app", Resource: "<keyspace app>", Permission: "MODIFY"},
+		{Role: "app", Resource: "<table app.events>", Permission: "SELECT"},
+	})
+
+	expected := []struct {
+		resource  string
+		grantedTo string
+		inherited bool
+	}{
+		{"<keyspace app>", "app", false},
+		{"<table app.events>", "app", false},
+		{"<table app.events>", "readers", true},
+	}
+	for i, e := range expected {
+		permission := flattened[i].(map[string]interface{})
+		if permission["resource"] != e.resource || permission["granted_to"] != e.grantedTo || permission["inherited"] != e.inherited {
+			t.Fatalf("unexpected permission %d: %v", i, permission)
+		}
+	}
+	if len(resources) != 2 || resources[0] != "<keyspace app>" || resources[1] != "<table app.events>" {
+		t.Fatalf("expected distinct sorted resources, got %v", resources)
+	}
+}
@@ -0,0 +1,168 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCassandraFunctions() *schema.Resource {
+	return &schema.Resource{
+		Description: "List the user-defined functions and aggregates of a keyspace",
+		ReadContext: dataSourceFunctionsRead,
+		Schema: map[string]*schema.Schema{
+			"keyspace": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Keyspace to list the functions of",
+			},
+			"functions": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "User-defined functions, sorted by signature",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the function",
+						},
+						"signature": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name and argument types identifying the function among its overloads, e.g. fn(int, text)",
+						},
+						"argument_types": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "CQL types of the arguments, in order",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"return_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "CQL type of the returned value",
+						},
+						"language": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Language of the body",
+						},
+					},
+				},
+			},
+			"aggregates": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "User-defined aggregates, sorted by signature",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the aggregate",
+						},
+						"signature": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name and argument types identifying the aggregate among its overloads, e.g. agg(int)",
+						},
+						"argument_types": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "CQL types of the arguments, in order",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"return_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "CQL type of the returned value",
+						},
+						"state_function": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the state function",
+						},
+						"final_function": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the final function, empty if none",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// unqualifiedSignature renders a name and argument types, e.g. fn(int, text).
+func unqualifiedSignature(name string, argumentTypes []string) string {
+	return fmt.Sprintf("%s(%s)", name, strings.Join(argumentTypes, ", "))
+}
+
+func flattenFunctions(functions []*Function) []interface{} {
+	flattened := make([]interface{}, 0, len(functions))
+	for _, function := range functions {
+		flattened = append(flattened, map[string]interface{}{
+			"name":           function.Name,
+			"signature":      unqualifiedSignature(function.Name, function.argumentTypes()),
+			"argument_types": function.argumentTypes(),
+			"return_type":    function.ReturnType,
+			"language":       function.Language,
+		})
+	}
+	sortBySignature(flattened)
+	return flattened
+}
+
+func flattenAggregates(aggregates []*Aggregate) []interface{} {
+	flattened := make([]interface{}, 0, len(aggregates))
+	for _, aggregate := range aggregates {
+		flattened = append(flattened, map[string]interface{}{
+			"name":           aggregate.Name,
+			"signature":      unqualifiedSignature(aggregate.Name, aggregate.ArgumentTypes),
+			"argument_types": aggregate.ArgumentTypes,
+			"return_type":    aggregate.ReturnType,
+			"state_function": aggregate.StateFunction,
+			"final_function": aggregate.FinalFunction,
+		})
+	}
+	sortBySignature(flattened)
+	return flattened
+}
+
+func sortBySignature(flattened []interface{}) {
+	sort.Slice(flattened, func(i, j int) bool {
+		return flattened[i].(map[string]interface{})["signature"].(string) < flattened[j].(map[string]interface{})["signature"].(string)
+	})
+}
+
+func dataSourceFunctionsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	keyspaceName := d.Get("keyspace").(string)
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	functions, err := queryFunctions(ctx, executor, keyspaceName, "")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	aggregates, err := queryAggregates(ctx, executor, keyspaceName, "")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(keyspaceName)
+	d.Set("functions", flattenFunctions(functions))
+	d.Set("aggregates", flattenAggregates(aggregates))
+	return diags
+}
@@ -0,0 +1,20 @@
+package cassandra
+
+import "testing"
+
+func TestFlattenFunctions(t *testing.T) {
+	functions := flattenFunctions([]*Function{
+		{Name: "to_cents", Arguments: []TableColumn{{Name: "amount", Type: "decimal"}}, ReturnType: "bigint", Language: "java"},
+		{Name: "avg_state", Arguments: []TableColumn{{Name: "state", Type: "tuple<int, bigint>"}, {Name: "value", Type: "int"}}, ReturnType: "tuple<int, bigint>", Language: "java"},
+	})
+
+	first := functions[0].(map[string]interface{})
+	if first["signature"] != "avg_state(tuple<int, bigint>, int)" {
+		t.Fatalf("expected functions sorted by signature, got %v", functions)
+	}
+
+	aggregates := flattenAggregates([]*Aggregate{{Name: "average", ArgumentTypes: []string{"int"}, StateFunction: "avg_state"}})
+	if aggregates[0].(map[string]interface{})["signature"] != "average(int)" {
+		t.Fatalf("unexpected aggregates %v", aggregates)
+	}
+}
@@ -0,0 +1,143 @@
+package cassandra
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/konradotto/terraform-provider-cassandra/cqlschema"
+)
+
+func dataSourceCassandraIndexes() *schema.Resource {
+	return &schema.Resource{
+		Description: "List the secondary indexes of a keyspace or table",
+		ReadContext: dataSourceIndexesRead,
+		Schema: map[string]*schema.Schema{
+			"keyspace": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Keyspace to list the indexes of",
+			},
+			"table": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list the indexes of this table, by its stored name",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Names of the indexes, sorted",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"indexes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Definitions of the indexes, sorted by table and name",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the index",
+						},
+						"table": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the indexed table",
+						},
+						"column": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the indexed column",
+						},
+						"target_kind": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Part of a collection column that is indexed - KEYS, VALUES, ENTRIES or FULL, empty for the column itself",
+						},
+						"kind": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Kind of the index as stored by the cluster, e.g. COMPOSITES or CUSTOM",
+						},
+						"index_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Implementation of the index - secondary, sai, sasi or custom",
+						},
+						"class": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Class implementing a custom index, empty for built-in secondary indexes",
+						},
+						"options": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Description: "Options of the index, without the target and class",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// flattenIndexes lists indexes sorted by table and name.
+func flattenIndexes(indexes []*cqlschema.Index) ([]string, []interface{}) {
+	sort.Slice(indexes, func(i, j int) bool {
+		if indexes[i].Table != indexes[j].Table {
+			return indexes[i].Table < indexes[j].Table
+		}
+		return indexes[i].Name < indexes[j].Name
+	})
+
+	names := make([]string, 0, len(indexes))
+	flattened := make([]interface{}, 0, len(indexes))
+	for _, index := range indexes {
+		kind, column := parseIndexTarget(index.Options["target"])
+		names = append(names, index.Name)
+		flattened = append(flattened, map[string]interface{}{
+			"name":        index.Name,
+			"table":       index.Table,
+			"column":      column,
+			"target_kind": kind,
+			"kind":        index.Kind,
+			"index_type":  indexTypeFromClass(index.Options["class_name"]),
+			"class":       index.Options["class_name"],
+			"options":     cqlschema.CustomIndexOptions(index.Options),
+		})
+	}
+	sort.Strings(names)
+	return names, flattened
+}
+
+func dataSourceIndexesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	keyspaceName := d.Get("keyspace").(string)
+	tableName := d.Get("table").(string)
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	indexes, err := cqlschema.ReadIndexes(ctx, executor, keyspaceName, tableName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	names, flattened := flattenIndexes(indexes)
+	if tableName != "" {
+		d.SetId(keyspaceName + "." + tableName)
+	} else {
+		d.SetId(keyspaceName)
+	}
+	d.Set("names", names)
+	d.Set("indexes", flattened)
+	return diags
+}
@@ -0,0 +1,32 @@
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/konradotto/terraform-provider-cassandra/cqlschema"
+)
+
+func TestFlattenIndexes(t *testing.T) {
+	names, indexes := flattenIndexes([]*cqlschema.Index{
+		{Name: "users_by_email", Table: "users", Kind: "COMPOSITES", Options: map[string]string{"target": "email"}},
+		{Name: "events_by_tag", Table: "events", Kind: "CUSTOM", Options: map[string]string{
+			"target":         "values(tags)",
+			"class_name":     "org.apache.cassandra.index.sai.StorageAttachedIndex",
+			"case_sensitive": "false",
+		}},
+	})
+
+	if len(names) != 2 || names[0] != "events_by_tag" || names[1] != "users_by_email" {
+		t.Fatalf("expected sorted names, got %v", names)
+	}
+	events := indexes[0].(map[string]interface{})
+	if events["column"] != "tags" || events["target_kind"] != "VALUES" || events["index_type"] != indexTypeSAI {
+		t.Fatalf("unexpected index %v", events)
+	}
+	if options := events["options"].(map[string]string); len(options) != 1 || options["case_sensitive"] != "false" {
+		t.Fatalf("expected only the custom options, got %v", options)
+	}
+	if users := indexes[1].(map[string]interface{}); users["index_type"] != indexTypeSecondary || users["column"] != "email" {
+		t.Fatalf("unexpected index %v", users)
+	}
+}
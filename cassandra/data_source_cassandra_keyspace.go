@@ -0,0 +1,72 @@
+package cassandra
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/konradotto/terraform-provider-cassandra/cqlschema"
+)
+
+func dataSourceCassandraKeyspace() *schema.Resource {
+	return &schema.Resource{
+		Description: "Read the replication settings and tables of an existing keyspace",
+		ReadContext: dataSourceKeyspaceRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of keyspace",
+			},
+			"replication_strategy": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Keyspace replication strategy",
+			},
+			"strategy_options": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "strategy options used with replication strategy",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"durable_writes": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether durable writes are enabled",
+			},
+			"tables": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Names of the tables in the keyspace, sorted",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceKeyspaceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	keyspaceMetadata, err := providerConfig.keyspaceMetadata(executor, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	strategyClass, strategyOptions := cqlschema.Replication(keyspaceMetadata)
+	tables, _ := flattenKeyspaceTables(keyspaceMetadata.Tables)
+	d.SetId(name)
+	d.Set("replication_strategy", strategyClass)
+	d.Set("strategy_options", strategyOptions)
+	d.Set("durable_writes", keyspaceMetadata.DurableWrites)
+	d.Set("tables", tables)
+	return diags
+}
@@ -0,0 +1,59 @@
+package cassandra
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/konradotto/terraform-provider-cassandra/cqlschema"
+)
+
+func dataSourceCassandraKeyspaceDDL() *schema.Resource {
+	return &schema.Resource{
+		Description: "Reconstruct the CQL creating a keyspace and its types, tables, indexes and materialized views",
+		ReadContext: dataSourceKeyspaceDDLRead,
+		Schema: map[string]*schema.Schema{
+			"keyspace": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Keyspace to export the schema of",
+			},
+			"statements": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "CREATE statements in dependency order: the keyspace, its types, each table followed by its indexes, then the materialized views",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"ddl": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "All statements terminated by semicolons, separated by blank lines",
+			},
+		},
+	}
+}
+
+func dataSourceKeyspaceDDLRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	keyspaceName := d.Get("keyspace").(string)
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	keyspaceSchema, err := cqlschema.ReadKeyspace(ctx, executor, providerConfig.schemaKeyspace(), keyspaceName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	statements := cqlschema.KeyspaceStatements(keyspaceSchema)
+	d.SetId(keyspaceName)
+	d.Set("statements", statements)
+	d.Set("ddl", strings.Join(statements, ";\n\n")+";\n")
+	return diags
+}
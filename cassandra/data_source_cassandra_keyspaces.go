@@ -0,0 +1,124 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCassandraKeyspaces() *schema.Resource {
+	return &schema.Resource{
+		Description: "List the keyspaces of the cluster with their replication settings",
+		ReadContext: dataSourceKeyspacesRead,
+		Schema: map[string]*schema.Schema{
+			"exclude_system": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Leave out keyspaces internal to the cluster, e.g. system and system_auth",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Names of the keyspaces, sorted",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"keyspaces": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Replication settings of the keyspaces, sorted by name",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of keyspace",
+						},
+						"replication_strategy": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Keyspace replication strategy",
+						},
+						"strategy_options": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Description: "strategy options used with replication strategy",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"durable_writes": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether durable writes are enabled",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// flattenReplicationMap splits a replication map as stored in
+// system_schema.keyspaces into the strategy and its options.
+func flattenReplicationMap(replication map[string]string) (string, map[string]string) {
+	strategyOptions := make(map[string]string, len(replication))
+	for key, value := range replication {
+		if key != "class" {
+			strategyOptions[key] = value
+		}
+	}
+	return strings.TrimPrefix(replication["class"], "org.apache.cassandra.locator."), strategyOptions
+}
+
+func dataSourceKeyspacesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	excludeSystem := d.Get("exclude_system").(bool)
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := fmt.Sprintf(`SELECT keyspace_name, durable_writes, replication FROM %s.keyspaces`, providerConfig.schemaKeyspace())
+	rows, err := executor.Select(ctx, query)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	keyspaces := []map[string]interface{}{}
+	for _, row := range rows {
+		name, _ := row["keyspace_name"].(string)
+		durableWrites, _ := row["durable_writes"].(bool)
+		replication, _ := row["replication"].(map[string]string)
+		if excludeSystem && isSystemKeyspace(name) {
+			continue
+		}
+		strategyClass, strategyOptions := flattenReplicationMap(replication)
+		keyspaces = append(keyspaces, map[string]interface{}{
+			"name":                 name,
+			"replication_strategy": strategyClass,
+			"strategy_options":     strategyOptions,
+			"durable_writes":       durableWrites,
+		})
+	}
+	sort.Slice(keyspaces, func(i, j int) bool {
+		return keyspaces[i]["name"].(string) < keyspaces[j]["name"].(string)
+	})
+
+	names := make([]string, 0, len(keyspaces))
+	flattened := make([]interface{}, 0, len(keyspaces))
+	for _, keyspace := range keyspaces {
+		names = append(names, keyspace["name"].(string))
+		flattened = append(flattened, keyspace)
+	}
+
+	d.SetId(hash(strings.Join(names, ",")))
+	d.Set("names", names)
+	d.Set("keyspaces", flattened)
+	return diags
+}
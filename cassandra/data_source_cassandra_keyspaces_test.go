@@ -0,0 +1,16 @@
+package cassandra
+
+import "testing"
+
+func TestFlattenReplicationMap(t *testing.T) {
+	strategyClass, strategyOptions := flattenReplicationMap(map[string]string{
+		"class":              "org.apache.cassandra.locator.SimpleStrategy",
+		"replication_factor": "3",
+	})
+	if strategyClass != "SimpleStrategy" {
+		t.Fatalf("unexpected strategy class %s", strategyClass)
+	}
+	if len(strategyOptions) != 1 || strategyOptions["replication_factor"] != "3" {
+		t.Fatalf("unexpected strategy options %v", strategyOptions)
+	}
+}
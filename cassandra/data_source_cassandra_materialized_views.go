@@ -0,0 +1,129 @@
+package cassandra
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/konradotto/terraform-provider-cassandra/cqlschema"
+)
+
+func dataSourceCassandraMaterializedViews() *schema.Resource {
+	return &schema.Resource{
+		Description: "List the materialized views of a keyspace or base table with their definitions",
+		ReadContext: dataSourceMaterializedViewsRead,
+		Schema: map[string]*schema.Schema{
+			"keyspace": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Keyspace to list the views of",
+			},
+			"base_table": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list the views of this table, by its stored name",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Names of the views, sorted",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"views": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Definitions of the views, sorted by name",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the view",
+						},
+						"base_table": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the table the view is built from",
+						},
+						"partition_keys": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "Columns of the partition key of the view, in order",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"clustering_keys": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "Clustering columns of the view, in order",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"columns": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "Other columns of the view, sorted",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"include_all_columns": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the view selects all columns of the base table",
+						},
+						"where_clause": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "WHERE clause filtering the rows of the view",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func flattenMaterializedView(name string, view *cqlschema.MaterializedView) map[string]interface{} {
+	return map[string]interface{}{
+		"name":                name,
+		"base_table":          view.BaseTable,
+		"partition_keys":      view.PartitionKeys,
+		"clustering_keys":     view.ClusteringKeys,
+		"columns":             view.Columns,
+		"include_all_columns": view.IncludeAllColumns,
+		"where_clause":        view.WhereClause,
+	}
+}
+
+func dataSourceMaterializedViewsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	keyspaceName := d.Get("keyspace").(string)
+	baseTable := d.Get("base_table").(string)
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	names, err := cqlschema.ReadMaterializedViewNames(ctx, executor, keyspaceName, baseTable)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	views := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		view, err := cqlschema.ReadMaterializedView(ctx, executor, keyspaceName, name)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		views = append(views, flattenMaterializedView(name, view))
+	}
+
+	if baseTable != "" {
+		d.SetId(keyspaceName + "." + baseTable)
+	} else {
+		d.SetId(keyspaceName)
+	}
+	d.Set("names", names)
+	d.Set("views", views)
+	return diags
+}
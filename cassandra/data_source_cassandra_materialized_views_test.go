@@ -0,0 +1,24 @@
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/konradotto/terraform-provider-cassandra/cqlschema"
+)
+
+func TestFlattenMaterializedView(t *testing.T) {
+	view := flattenMaterializedView("users_by_email", &cqlschema.MaterializedView{
+		BaseTable:      "users",
+		WhereClause:    "email IS NOT NULL AND id IS NOT NULL",
+		PartitionKeys:  []string{"email"},
+		ClusteringKeys: []string{"id"},
+		Columns:        []string{"name"},
+	})
+
+	if view["name"] != "users_by_email" || view["base_table"] != "users" || view["include_all_columns"] != false {
+		t.Fatalf("unexpected view %v", view)
+	}
+	if keys := view["partition_keys"].([]string); len(keys) != 1 || keys[0] != "email" {
+		t.Fatalf("unexpected partition keys %v", keys)
+	}
+}
@@ -0,0 +1,195 @@
+package cassandra
+
+import (
+	"context"
+	"log"
+	"net"
+	"sort"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCassandraNodes() *schema.Resource {
+	return &schema.Resource{
+		Description: "List the nodes of the cluster with their topology and versions",
+		ReadContext: dataSourceNodesRead,
+		Schema: map[string]*schema.Schema{
+			"datacenter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list the nodes of this datacenter",
+			},
+			"nodes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Nodes known to the coordinator, sorted by datacenter, rack and address",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"address": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Broadcast address of the node",
+						},
+						"datacenter": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Datacenter of the node",
+						},
+						"rack": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Rack of the node",
+						},
+						"host_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Host ID of the node",
+						},
+						"release_version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Cassandra release version of the node",
+						},
+						"schema_version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Version of the schema the node has applied",
+						},
+						"local": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the node is the coordinator the session is connected to",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Node is a node of the cluster as stored in system.local or system.peers.
+type Node struct {
+	Address        string
+	Datacenter     string
+	Rack           string
+	HostID         gocql.UUID
+	ReleaseVersion string
+	SchemaVersion  gocql.UUID
+	Tokens         []string
+	Local          bool
+}
+
+// queryNodes reads the coordinator from system.local and the other nodes from
+// system.peers_v2, falling back to system.peers before Cassandra 4.0.
+func queryNodes(ctx context.Context, executor CQLExecutor) ([]*Node, error) {
+	rows, err := executor.Select(ctx, `SELECT broadcast_address, data_center, rack, host_id, release_version, schema_version, tokens FROM system.local`)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, gocql.ErrNotFound
+	}
+	local := nodeOf(rows[0], "broadcast_address")
+	local.Local = true
+	nodes := []*Node{local}
+
+	peers, err := queryPeers(ctx, executor, `SELECT peer, data_center, rack, host_id, release_version, schema_version, tokens FROM system.peers_v2`)
+	if err != nil {
+		log.Printf("Reading system.peers_v2 failed (%s), reading system.peers instead", err)
+		peers, err = queryPeers(ctx, executor, `SELECT peer, data_center, rack, host_id, release_version, schema_version, tokens FROM system.peers`)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(nodes, peers...), nil
+}
+
+func queryPeers(ctx context.Context, executor CQLExecutor, query string) ([]*Node, error) {
+	rows, err := executor.Select(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	peers := []*Node{}
+	for _, row := range rows {
+		peers = append(peers, nodeOf(row, "peer"))
+	}
+	return peers, nil
+}
+
+// nodeOf converts a row of system.local or system.peers to a Node, reading
+// its address from addressColumn.
+func nodeOf(row map[string]interface{}, addressColumn string) *Node {
+	node := &Node{}
+	switch address := row[addressColumn].(type) {
+	case net.IP:
+		node.Address = address.String()
+	case string:
+		node.Address = address
+	}
+	node.Datacenter, _ = row["data_center"].(string)
+	node.Rack, _ = row["rack"].(string)
+	node.HostID, _ = row["host_id"].(gocql.UUID)
+	node.ReleaseVersion, _ = row["release_version"].(string)
+	node.SchemaVersion, _ = row["schema_version"].(gocql.UUID)
+	node.Tokens, _ = row["tokens"].([]string)
+	return node
+}
+
+// flattenNodes lists the nodes of a datacenter, or all nodes if it is empty,
+// sorted by datacenter, rack and address.
+func flattenNodes(nodes []*Node, datacenter string) []interface{} {
+	filtered := []*Node{}
+	for _, node := range nodes {
+		if datacenter == "" || node.Datacenter == datacenter {
+			filtered = append(filtered, node)
+		}
+	}
+	sort.Slice(filtered, func(i, j int) bool {
+		a, b := filtered[i], filtered[j]
+		if a.Datacenter != b.Datacenter {
+			return a.Datacenter < b.Datacenter
+		}
+		if a.Rack != b.Rack {
+			return a.Rack < b.Rack
+		}
+		return a.Address < b.Address
+	})
+
+	flattened := make([]interface{}, 0, len(filtered))
+	for _, node := range filtered {
+		flattened = append(flattened, map[string]interface{}{
+			"address":         node.Address,
+			"datacenter":      node.Datacenter,
+			"rack":            node.Rack,
+			"host_id":         node.HostID.String(),
+			"release_version": node.ReleaseVersion,
+			"schema_version":  node.SchemaVersion.String(),
+			"local":           node.Local,
+		})
+	}
+	return flattened
+}
+
+func dataSourceNodesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	datacenter := d.Get("datacenter").(string)
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	nodes, err := queryNodes(ctx, executor)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(hash("nodes:" + datacenter))
+	d.Set("nodes", flattenNodes(nodes, datacenter))
+	return diags
+}
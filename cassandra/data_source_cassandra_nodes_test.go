@@ -0,0 +1,48 @@
+package cassandra
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/gocql/gocql"
+)
+
+func TestFlattenNodes(t *testing.T) {
+	nodes := []*Node{
+		{Address: "10.0.1.2", Datacenter: "eu-west", Rack: "rack1", Local: true},
+		{Address: "10.0.0.2", Datacenter: "us-east", Rack: "rack1"},
+		{Address: "10.0.1.1", Datacenter: "eu-west", Rack: "rack1"},
+		{Address: "10.0.1.3", Datacenter: "eu-west", Rack: "rack0"},
+	}
+
+	flattened := flattenNodes(nodes, "")
+	expected := []string{"10.0.1.3", "10.0.1.1", "10.0.1.2", "10.0.0.2"}
+	for i, address := range expected {
+		if node := flattened[i].(map[string]interface{}); node["address"] != address {
+			t.Fatalf("expected node %d to be %s, got %v", i, address, node)
+		}
+	}
+
+	flattened = flattenNodes(nodes, "us-east")
+	if len(flattened) != 1 || flattened[0].(map[string]interface{})["address"] != "10.0.0.2" {
+		t.Fatalf("expected only the us-east node, got %v", flattened)
+	}
+}
+
+func TestNodeOf(t *testing.T) {
+	hostID := gocql.TimeUUID()
+	node := nodeOf(map[string]interface{}{
+		"peer":            net.ParseIP("10.0.1.2"),
+		"data_center":     "eu-west",
+		"rack":            "rack1",
+		"host_id":         hostID,
+		"release_version": "4.1.3",
+		"tokens":          []string{"-42", "42"},
+	}, "peer")
+
+	expected := &Node{Address: "10.0.1.2", Datacenter: "eu-west", Rack: "rack1", HostID: hostID, ReleaseVersion: "4.1.3", Tokens: []string{"-42", "42"}}
+	if !reflect.DeepEqual(node, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, node)
+	}
+}
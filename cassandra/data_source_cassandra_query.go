@@ -0,0 +1,150 @@
+package cassandra
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var selectStatementRegex = regexp.MustCompile(`(?i)^\s*SELECT\s`)
+
+func dataSourceCassandraQuery() *schema.Resource {
+	return &schema.Resource{
+		Description: "Run a read-only SELECT statement and expose the returned rows",
+		ReadContext: dataSourceQueryRead,
+		Schema: map[string]*schema.Schema{
+			"query": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "SELECT statement to run, with ? placeholders for the parameters",
+				ValidateFunc: validation.StringMatch(selectStatementRegex, "must be a SELECT statement"),
+			},
+			"parameters": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Values bound to the ? placeholders of the query, in order. They are converted to the type of the column they are compared with, e.g. int or uuid",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"columns": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Names of the returned columns, in order",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"rows": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Returned rows as maps of column name to value. Values are rendered as strings, collections as JSON and blobs as hex literals. Null collections and blobs are omitted, other null values are rendered as the zero value of their type",
+				Elem: &schema.Schema{
+					Type: schema.TypeMap,
+					Elem: &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		},
+	}
+}
+
+// formatCQLValue renders a value scanned by gocql as a string.
+func formatCQLValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return v.String()
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if rendered, err := json.Marshal(value); err == nil {
+			return string(rendered)
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// isNullCQLValue tells whether a value scanned by gocql stands for null. Only
+// nil pointers, collections and blobs can be told apart from zero values.
+func isNullCQLValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return v.IsNil()
+	}
+	return false
+}
+
+// flattenQueryRow renders a row returned by MapScan, omitting null values.
+func flattenQueryRow(row map[string]interface{}) map[string]interface{} {
+	flattened := map[string]interface{}{}
+	for column, value := range row {
+		if !isNullCQLValue(value) {
+			flattened[column] = formatCQLValue(value)
+		}
+	}
+	return flattened
+}
+
+// runQuery runs a SELECT statement and returns the names of the returned
+// columns and the rendered rows.
+func runQuery(ctx context.Context, executor CQLExecutor, query string, parameters []string) ([]string, []interface{}, error) {
+	values := make([]interface{}, 0, len(parameters))
+	for _, parameter := range parameters {
+		values = append(values, parameter)
+	}
+	columns, rows, err := executor.SelectColumns(ctx, query, values...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	flattened := make([]interface{}, 0, len(rows))
+	for _, row := range rows {
+		flattened = append(flattened, flattenQueryRow(row))
+	}
+	return columns, flattened, nil
+}
+
+// selectQuery runs a SELECT statement of the cassandra_query data source or
+// ephemeral resource in a session of its own.
+func (c *ProviderConfig) selectQuery(ctx context.Context, query string, parameters []string) ([]string, []interface{}, error) {
+	executor, err := c.newExecutor()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer executor.Close()
+
+	log.Printf("Executing query: %s", query)
+	return runQuery(ctx, executor, query, parameters)
+}
+
+func dataSourceQueryRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	query := d.Get("query").(string)
+	parameters := listToArray(d.Get("parameters"))
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	columns, rows, err := providerConfig.selectQuery(ctx, query, parameters)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(hash(query + "\x00" + strings.Join(parameters, "\x00")))
+	d.Set("columns", columns)
+	d.Set("rows", rows)
+	return diags
+}
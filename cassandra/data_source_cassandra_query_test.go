@@ -0,0 +1,77 @@
+package cassandra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+func TestFormatCQLValue(t *testing.T) {
+	id, _ := gocql.ParseUUID("5d9c5e40-2d1b-4d4a-9a62-1f0b7a6f3c11")
+	for _, test := range []struct {
+		value    interface{}
+		expected string
+	}{
+		{"eu-west-1", "eu-west-1"},
+		{42, "42"},
+		{true, "true"},
+		{[]byte{0xca, 0xfe}, "0xcafe"},
+		{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), "2024-01-02T03:04:05Z"},
+		{id, "5d9c5e40-2d1b-4d4a-9a62-1f0b7a6f3c11"},
+		{[]string{"a", "b"}, `["a","b"]`},
+		{map[string]int{"a": 1}, `{"a":1}`},
+	} {
+		if rendered := formatCQLValue(test.value); rendered != test.expected {
+			t.Fatalf("expected %q, got %q", test.expected, rendered)
+		}
+	}
+}
+
+func TestFlattenQueryRow(t *testing.T) {
+	row := flattenQueryRow(map[string]interface{}{
+		"name":  "feature_x",
+		"tags":  []string(nil),
+		"value": 3,
+	})
+
+	if len(row) != 2 || row["name"] != "feature_x" || row["value"] != "3" {
+		t.Fatalf("unexpected row %v", row)
+	}
+}
+
+func TestSelectStatementRegex(t *testing.T) {
+	for query, expected := range map[string]bool{
+		"SELECT * FROM ks.settings":             true,
+		"  select value FROM ks.settings":       true,
+		"DELETE FROM ks.settings WHERE key = ?": false,
+		"SELECTION":                             false,
+	} {
+		if selectStatementRegex.MatchString(query) != expected {
+			t.Fatalf("expected match of %q to be %t", query, expected)
+		}
+	}
+}
+
+func TestRunQuery(t *testing.T) {
+	executor := newMockCQLExecutor()
+	query := "SELECT id, name, tags FROM app.users WHERE id = ?"
+	executor.columns[query+" [42]"] = []string{"id", "name", "tags"}
+	executor.rows[query+" [42]"] = []map[string]interface{}{{"id": 42, "name": "alice", "tags": []string(nil)}}
+
+	columns, rows, err := runQuery(context.Background(), executor, query, []string{"42"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(columns) != 3 || columns[0] != "id" || columns[1] != "name" || columns[2] != "tags" {
+		t.Fatalf("expected the columns in order, got %v", columns)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("expected one row, got %v", rows)
+	}
+	row := rows[0].(map[string]interface{})
+	if len(row) != 2 || row["id"] != "42" || row["name"] != "alice" {
+		t.Fatalf("unexpected row %v", row)
+	}
+}
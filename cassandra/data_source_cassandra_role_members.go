@@ -0,0 +1,110 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceCassandraRoleMembers() *schema.Resource {
+	return &schema.Resource{
+		Description: "List the roles granted a role, directly and through other roles",
+		ReadContext: dataSourceRoleMembersRead,
+		Schema: map[string]*schema.Schema{
+			"role": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Name of the role to list the members of",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"members": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Roles the role is granted to directly, sorted",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"all_members": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Roles the role is granted to directly or through other roles, sorted",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func queryRoleMembers(ctx context.Context, executor CQLExecutor, systemKeyspace string, role string) ([]string, error) {
+	query := fmt.Sprintf("SELECT member FROM %s.role_members WHERE role = ?", systemKeyspace)
+	rows, err := executor.Select(ctx, query, role)
+	if err != nil {
+		return nil, err
+	}
+
+	members := []string{}
+	for _, row := range rows {
+		member, _ := row["member"].(string)
+		members = append(members, member)
+	}
+	sort.Strings(members)
+	return members, nil
+}
+
+// transitiveRoleMembers walks the members of role breadth-first and returns
+// all of them sorted, ignoring cycles.
+func transitiveRoleMembers(role string, members func(string) ([]string, error)) ([]string, error) {
+	seen := map[string]bool{role: true}
+	all := []string{}
+	queue := []string{role}
+	for len(queue) > 0 {
+		direct, err := members(queue[0])
+		if err != nil {
+			return nil, err
+		}
+		queue = queue[1:]
+		for _, member := range direct {
+			if !seen[member] {
+				seen[member] = true
+				all = append(all, member)
+				queue = append(queue, member)
+			}
+		}
+	}
+	sort.Strings(all)
+	return all, nil
+}
+
+func dataSourceRoleMembersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	role := d.Get("role").(string)
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	if _, _, _, _, err := readRole(ctx, executor, role, providerConfig.authKeyspace(ctx, executor)); err != nil {
+		return diag.FromErr(err)
+	}
+	lookup := func(name string) ([]string, error) {
+		return queryRoleMembers(ctx, executor, providerConfig.authKeyspace(ctx, executor), name)
+	}
+	members, err := lookup(role)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	allMembers, err := transitiveRoleMembers(role, lookup)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(role)
+	d.Set("members", members)
+	d.Set("all_members", allMembers)
+	return diags
+}
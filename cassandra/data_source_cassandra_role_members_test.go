@@ -0,0 +1,27 @@
+package cassandra
+
+import "testing"
+
+func TestTransitiveRoleMembers(t *testing.T) {
+	graph := map[string][]string{
+		"reader":    {"analyst", "developer"},
+		"developer": {"alice", "lead"},
+		"lead":      {"bob", "developer"},
+	}
+	members, err := transitiveRoleMembers("reader", func(role string) ([]string, error) {
+		return graph[role], nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"alice", "analyst", "bob", "developer", "lead"}
+	if len(members) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, members)
+	}
+	for i := range expected {
+		if members[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, members)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package cassandra
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCassandraSchemaAgreement() *schema.Resource {
+	return &schema.Resource{
+		Description: "Report whether all nodes of the cluster agree on the schema",
+		ReadContext: dataSourceSchemaAgreementRead,
+		Schema: map[string]*schema.Schema{
+			"agreement": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether all nodes known to the coordinator report the same schema version",
+			},
+			"schema_versions": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Schema version per node address",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"distinct_versions": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Distinct schema versions reported by the nodes, sorted",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// schemaVersions returns the schema version per node address and the
+// distinct versions, sorted.
+func schemaVersions(nodes []*Node) (map[string]string, []string) {
+	versions := map[string]string{}
+	seen := map[string]bool{}
+	distinct := []string{}
+	for _, node := range nodes {
+		version := node.SchemaVersion.String()
+		versions[node.Address] = version
+		if !seen[version] {
+			seen[version] = true
+			distinct = append(distinct, version)
+		}
+	}
+	sort.Strings(distinct)
+	return versions, distinct
+}
+
+func dataSourceSchemaAgreementRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	nodes, err := queryNodes(ctx, executor)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	versions, distinct := schemaVersions(nodes)
+
+	d.SetId(hash(strings.Join(distinct, ",")))
+	d.Set("agreement", len(distinct) == 1)
+	d.Set("schema_versions", versions)
+	d.Set("distinct_versions", distinct)
+	return diags
+}
@@ -0,0 +1,25 @@
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/gocql/gocql"
+)
+
+func TestSchemaVersions(t *testing.T) {
+	current, _ := gocql.ParseUUID("8e2b1a4c-5f6d-3e7a-9b0c-1d2e3f4a5b6c")
+	stale, _ := gocql.ParseUUID("1a2b3c4d-5e6f-3a7b-8c9d-0e1f2a3b4c5d")
+
+	versions, distinct := schemaVersions([]*Node{
+		{Address: "10.0.0.1", SchemaVersion: current},
+		{Address: "10.0.0.2", SchemaVersion: current},
+		{Address: "10.0.0.3", SchemaVersion: stale},
+	})
+
+	if len(versions) != 3 || versions["10.0.0.3"] != stale.String() {
+		t.Fatalf("unexpected schema versions %v", versions)
+	}
+	if len(distinct) != 2 || distinct[0] != stale.String() || distinct[1] != current.String() {
+		t.Fatalf("expected sorted distinct versions, got %v", distinct)
+	}
+}
@@ -0,0 +1,89 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCassandraSettings() *schema.Resource {
+	return &schema.Resource{
+		Description: "Read the live configuration of the coordinator from the system_views.settings virtual table (Cassandra 4.0+), or from system.config in scylla mode",
+		ReadContext: dataSourceSettingsRead,
+		Schema: map[string]*schema.Schema{
+			"names": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Only read these settings, e.g. authenticator and authorizer. All settings are read when omitted",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"settings": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Values of the settings by name. Settings without a value are omitted",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// querySettings reads the settings of the coordinator. Settings without a
+// value are left out.
+func querySettings(ctx context.Context, executor CQLExecutor, mode string) (map[string]string, error) {
+	rows, err := executor.Select(ctx, fmt.Sprintf(`SELECT name, value FROM %s`, systemView(mode, "settings")))
+	if err != nil {
+		return nil, err
+	}
+
+	settings := map[string]string{}
+	for _, row := range rows {
+		name, _ := row["name"].(string)
+		if value, ok := row["value"].(string); ok {
+			settings[name] = value
+		}
+	}
+	return settings, nil
+}
+
+// filterSettings narrows settings down to names, unless names is empty.
+func filterSettings(settings map[string]string, names []string) map[string]string {
+	if len(names) == 0 {
+		return settings
+	}
+	filtered := map[string]string{}
+	for _, name := range names {
+		if value, ok := settings[name]; ok {
+			filtered[name] = value
+		}
+	}
+	return filtered
+}
+
+func dataSourceSettingsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	names := setToArray(d.Get("names"))
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	settings, err := querySettings(ctx, executor, providerConfig.Mode)
+	if err != nil {
+		if providerConfig.Mode == modeScylla {
+			return diag.Errorf("cannot read %s: %s", systemView(providerConfig.Mode, "settings"), err)
+		}
+		return diag.Errorf("cannot read system_views.settings, which requires Cassandra 4.0 or later: %s", err)
+	}
+	sort.Strings(names)
+
+	d.SetId(hash("settings:" + strings.Join(names, ",")))
+	d.Set("settings", filterSettings(settings, names))
+	return diags
+}
@@ -0,0 +1,23 @@
+package cassandra
+
+import "testing"
+
+func TestFilterSettings(t *testing.T) {
+	settings := map[string]string{
+		"authenticator":  "PasswordAuthenticator",
+		"authorizer":     "CassandraAuthorizer",
+		"cluster_name":   "prod",
+		"num_tokens":     "16",
+		"role_manager":   "CassandraRoleManager",
+		"start_rpc":      "false",
+		"listen_address": "10.0.0.1",
+	}
+
+	if filtered := filterSettings(settings, nil); len(filtered) != len(settings) {
+		t.Fatalf("expected all settings without names, got %v", filtered)
+	}
+	filtered := filterSettings(settings, []string{"authenticator", "authorizer", "unknown"})
+	if len(filtered) != 2 || filtered["authenticator"] != "PasswordAuthenticator" || filtered["authorizer"] != "CassandraAuthorizer" {
+		t.Fatalf("unexpected settings %v", filtered)
+	}
+}
@@ -0,0 +1,232 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/konradotto/terraform-provider-cassandra/cqlschema"
+)
+
+func dataSourceCassandraTable() *schema.Resource {
+	return &schema.Resource{
+		Description: "Read the schema of an existing table, including its columns, keys, options and indexes",
+		ReadContext: dataSourceTableRead,
+		Schema: map[string]*schema.Schema{
+			"keyspace": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Keyspace of the table",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the table",
+			},
+			"quote_identifiers": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Match the table name case-sensitively. Set to false to look up an unquoted, case-insensitive name",
+			},
+			"columns": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Columns of the table - partition key first, then clustering columns, then the remaining columns by name",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the column",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "CQL type of the column",
+						},
+						"kind": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "One of partition_key, clustering_key, regular or static",
+						},
+						"clustering_order": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "asc or desc for clustering columns, empty otherwise",
+						},
+					},
+				},
+			},
+			"partition_keys": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Columns of the partition key, in order",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"clustering_keys": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Clustering columns, in order",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"comment": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Comment of the table",
+			},
+			"compact_storage": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the table was created WITH COMPACT STORAGE, which Cassandra 4.0 and later cannot create",
+			},
+			"default_time_to_live": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Default TTL of inserted rows in seconds",
+			},
+			"gc_grace_seconds": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Seconds to keep tombstones before they are garbage collected",
+			},
+			"speculative_retry": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Speculative retry policy",
+			},
+			"caching": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Caching options",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"compaction": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Compaction options",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"compression": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Compression options",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"indexes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Secondary indexes of the table",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the index",
+						},
+						"kind": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "One of COMPOSITES, KEYS or CUSTOM",
+						},
+						"options": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Description: "Options of the index, e.g. target and class_name",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// flattenTableColumns lists the columns of a table in primary key order,
+// followed by the remaining columns sorted by name.
+func flattenTableColumns(metadata *gocql.TableMetadata) []interface{} {
+	ordered := cqlschema.Columns(metadata)
+	columns := make([]interface{}, 0, len(ordered))
+	for _, column := range ordered {
+		clusteringOrder := ""
+		if column.Kind == gocql.ColumnClusteringKey {
+			clusteringOrder = column.ClusteringOrder
+		}
+		columns = append(columns, map[string]interface{}{
+			"name":             column.Name,
+			"type":             cqlschema.ColumnType(column),
+			"kind":             column.Kind.String(),
+			"clustering_order": clusteringOrder,
+		})
+	}
+	return columns
+}
+
+func flattenTableIndexes(indexes []*cqlschema.Index) []interface{} {
+	ret := make([]interface{}, 0, len(indexes))
+	for _, index := range indexes {
+		ret = append(ret, map[string]interface{}{
+			"name":    index.Name,
+			"kind":    index.Kind,
+			"options": index.Options,
+		})
+	}
+	return ret
+}
+
+func dataSourceTableRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	table := &Table{
+		Keyspace:         d.Get("keyspace").(string),
+		Name:             d.Get("name").(string),
+		QuoteIdentifiers: d.Get("quote_identifiers").(bool),
+	}
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	keyspaceMetadata, err := providerConfig.keyspaceMetadata(executor, table.metadataName(table.Keyspace))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	metadata, ok := keyspaceMetadata.Tables[table.metadataName(table.Name)]
+	if !ok {
+		return diag.Errorf("table %s.%s does not exist", table.Keyspace, table.Name)
+	}
+
+	options, err := cqlschema.ReadTableOptions(ctx, executor, providerConfig.schemaKeyspace(), keyspaceMetadata.Name, metadata.Name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	indexes, err := cqlschema.ReadIndexes(ctx, executor, keyspaceMetadata.Name, metadata.Name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	compactStorage, err := readCompactStorage(ctx, executor, providerConfig, table.Keyspace, metadata.Name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", table.Keyspace, metadata.Name))
+	d.Set("columns", flattenTableColumns(metadata))
+	d.Set("partition_keys", columnNames(metadata.PartitionKey))
+	d.Set("clustering_keys", columnNames(metadata.ClusteringColumns))
+	d.Set("comment", options.Comment)
+	d.Set("compact_storage", compactStorage)
+	d.Set("default_time_to_live", options.DefaultTimeToLive)
+	d.Set("gc_grace_seconds", options.GCGraceSeconds)
+	d.Set("speculative_retry", options.SpeculativeRetry)
+	d.Set("caching", options.Caching)
+	d.Set("compaction", options.Compaction)
+	d.Set("compression", options.Compression)
+	d.Set("indexes", flattenTableIndexes(indexes))
+	return diags
+}
@@ -0,0 +1,109 @@
+package cassandra
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCassandraTableSizeEstimates() *schema.Resource {
+	return &schema.Resource{
+		Description: "Read the partition count and size estimates of a table from system.size_estimates",
+		ReadContext: dataSourceTableSizeEstimatesRead,
+		Schema: map[string]*schema.Schema{
+			"keyspace": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Keyspace of the table",
+			},
+			"table": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the table, by its stored name",
+			},
+			"range_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of token ranges with estimates, 0 if the estimates have not been computed yet",
+			},
+			"partitions_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Estimated number of partitions in the token ranges of the coordinator",
+			},
+			"mean_partition_size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Estimated mean size of a partition in bytes, weighted by the partitions of each range",
+			},
+			"estimated_size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Estimated size of the partitions in the token ranges of the coordinator in bytes",
+			},
+		},
+	}
+}
+
+// SizeEstimate is the estimate of one token range of a table.
+type SizeEstimate struct {
+	PartitionsCount   int64
+	MeanPartitionSize int64
+}
+
+func querySizeEstimates(ctx context.Context, executor CQLExecutor, keyspace string, table string) ([]SizeEstimate, error) {
+	rows, err := executor.Select(ctx, `SELECT partitions_count, mean_partition_size FROM system.size_estimates WHERE keyspace_name = ? AND table_name = ?`, keyspace, table)
+	if err != nil {
+		return nil, err
+	}
+
+	estimates := make([]SizeEstimate, 0, len(rows))
+	for _, row := range rows {
+		var estimate SizeEstimate
+		estimate.PartitionsCount, _ = row["partitions_count"].(int64)
+		estimate.MeanPartitionSize, _ = row["mean_partition_size"].(int64)
+		estimates = append(estimates, estimate)
+	}
+	return estimates, nil
+}
+
+// sumSizeEstimates returns the partition count, mean partition size and size
+// of a table over the token ranges of its estimates.
+func sumSizeEstimates(estimates []SizeEstimate) (int64, int64, int64) {
+	var partitions, size int64
+	for _, estimate := range estimates {
+		partitions += estimate.PartitionsCount
+		size += estimate.PartitionsCount * estimate.MeanPartitionSize
+	}
+	if partitions == 0 {
+		return 0, 0, 0
+	}
+	return partitions, size / partitions, size
+}
+
+func dataSourceTableSizeEstimatesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	keyspaceName := d.Get("keyspace").(string)
+	tableName := d.Get("table").(string)
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	estimates, err := querySizeEstimates(ctx, executor, keyspaceName, tableName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	partitions, meanSize, size := sumSizeEstimates(estimates)
+	d.SetId(keyspaceName + "." + tableName)
+	d.Set("range_count", len(estimates))
+	d.Set("partitions_count", partitions)
+	d.Set("mean_partition_size", meanSize)
+	d.Set("estimated_size", size)
+	return diags
+}
@@ -0,0 +1,18 @@
+package cassandra
+
+import "testing"
+
+func TestSumSizeEstimates(t *testing.T) {
+	partitions, meanSize, size := sumSizeEstimates([]SizeEstimate{
+		{PartitionsCount: 300, MeanPartitionSize: 100},
+		{PartitionsCount: 100, MeanPartitionSize: 500},
+		{PartitionsCount: 0, MeanPartitionSize: 0},
+	})
+	if partitions != 400 || meanSize != 200 || size != 80000 {
+		t.Fatalf("unexpected estimates %d partitions of %d bytes, %d bytes in total", partitions, meanSize, size)
+	}
+
+	if partitions, meanSize, size := sumSizeEstimates(nil); partitions != 0 || meanSize != 0 || size != 0 {
+		t.Fatal("expected no estimates to sum up to 0")
+	}
+}
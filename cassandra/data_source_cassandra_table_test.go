@@ -0,0 +1,41 @@
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/gocql/gocql"
+)
+
+func TestFlattenTableColumns(t *testing.T) {
+	tenant := &gocql.ColumnMetadata{Name: "tenant", Validator: "text", Kind: gocql.ColumnPartitionKey}
+	created := &gocql.ColumnMetadata{Name: "created", Validator: "timestamp", Kind: gocql.ColumnClusteringKey, ClusteringOrder: "desc"}
+	metadata := &gocql.TableMetadata{
+		Keyspace:          "some_keyspace",
+		Name:              "some_table",
+		PartitionKey:      []*gocql.ColumnMetadata{tenant},
+		ClusteringColumns: []*gocql.ColumnMetadata{created},
+		Columns: map[string]*gocql.ColumnMetadata{
+			"tenant":  tenant,
+			"created": created,
+			"payload": {Name: "payload", Validator: "blob", Kind: gocql.ColumnRegular},
+			"owner":   {Name: "owner", Validator: "text", Kind: gocql.ColumnStatic},
+		},
+	}
+
+	columns := flattenTableColumns(metadata)
+	expected := []string{"tenant", "created", "owner", "payload"}
+	if len(columns) != len(expected) {
+		t.Fatalf("expected %d columns, got %v", len(expected), columns)
+	}
+	for i, name := range expected {
+		if column := columns[i].(map[string]interface{}); column["name"] != name {
+			t.Fatalf("expected column %d to be %s, got %v", i, name, column)
+		}
+	}
+	if column := columns[1].(map[string]interface{}); column["kind"] != "clustering_key" || column["clustering_order"] != "desc" {
+		t.Fatalf("unexpected clustering column %v", column)
+	}
+	if column := columns[2].(map[string]interface{}); column["kind"] != "static" || column["type"] != "text" {
+		t.Fatalf("unexpected static column %v", column)
+	}
+}
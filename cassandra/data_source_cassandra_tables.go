@@ -0,0 +1,105 @@
+package cassandra
+
+import (
+	"context"
+	"sort"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCassandraTables() *schema.Resource {
+	return &schema.Resource{
+		Description: "List the tables of a keyspace",
+		ReadContext: dataSourceTablesRead,
+		Schema: map[string]*schema.Schema{
+			"keyspace": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Keyspace to list the tables of",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Names of the tables, sorted",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"tables": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Basic metadata of the tables, sorted by name",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the table",
+						},
+						"partition_keys": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "Columns of the partition key, in order",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"clustering_keys": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "Clustering columns, in order",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"column_count": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Number of columns of the table",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// flattenKeyspaceTables lists the tables of a keyspace sorted by name.
+func flattenKeyspaceTables(tables map[string]*gocql.TableMetadata) ([]string, []interface{}) {
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	flattened := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		table := tables[name]
+		flattened = append(flattened, map[string]interface{}{
+			"name":            name,
+			"partition_keys":  columnNames(table.PartitionKey),
+			"clustering_keys": columnNames(table.ClusteringColumns),
+			"column_count":    len(table.Columns),
+		})
+	}
+	return names, flattened
+}
+
+func dataSourceTablesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	keyspaceName := d.Get("keyspace").(string)
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	keyspaceMetadata, err := providerConfig.keyspaceMetadata(executor, keyspaceName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	names, tables := flattenKeyspaceTables(keyspaceMetadata.Tables)
+	d.SetId(keyspaceName)
+	d.Set("names", names)
+	d.Set("tables", tables)
+	return diags
+}
@@ -0,0 +1,27 @@
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/gocql/gocql"
+)
+
+func TestFlattenKeyspaceTables(t *testing.T) {
+	id := &gocql.ColumnMetadata{Name: "id", Kind: gocql.ColumnPartitionKey}
+	names, tables := flattenKeyspaceTables(map[string]*gocql.TableMetadata{
+		"users": {
+			Name:         "users",
+			PartitionKey: []*gocql.ColumnMetadata{id},
+			Columns:      map[string]*gocql.ColumnMetadata{"id": id},
+		},
+		"events": {Name: "events"},
+	})
+
+	if len(names) != 2 || names[0] != "events" || names[1] != "users" {
+		t.Fatalf("expected sorted names [events users], got %v", names)
+	}
+	users := tables[1].(map[string]interface{})
+	if users["column_count"] != 1 || len(users["partition_keys"].([]string)) != 1 {
+		t.Fatalf("unexpected metadata %v", users)
+	}
+}
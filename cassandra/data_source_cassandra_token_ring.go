@@ -0,0 +1,182 @@
+package cassandra
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// partitionerRingSizes maps the partitioners whose token ranges can be
+// measured to the number of tokens on their ring.
+var partitionerRingSizes = map[string]*big.Int{
+	"org.apache.cassandra.dht.Murmur3Partitioner": new(big.Int).Lsh(big.NewInt(1), 64),
+	"org.apache.cassandra.dht.RandomPartitioner":  new(big.Int).Lsh(big.NewInt(1), 127),
+}
+
+func dataSourceCassandraTokenRing() *schema.Resource {
+	return &schema.Resource{
+		Description: "Read the tokens owned by each node of the cluster",
+		ReadContext: dataSourceTokenRingRead,
+		Schema: map[string]*schema.Schema{
+			"datacenter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list the nodes of this datacenter",
+			},
+			"partitioner": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Partitioner of the cluster, e.g. org.apache.cassandra.dht.Murmur3Partitioner",
+			},
+			"nodes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Nodes sorted by datacenter, rack and address",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"address": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Broadcast address of the node",
+						},
+						"datacenter": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Datacenter of the node",
+						},
+						"rack": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Rack of the node",
+						},
+						"tokens": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "Tokens of the node, sorted",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"token_count": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Number of tokens of the node",
+						},
+						"ownership": {
+							Type:        schema.TypeFloat,
+							Computed:    true,
+							Description: "Percentage of the ring of its datacenter the node owns as primary replica, 0 for partitioners other than Murmur3Partitioner and RandomPartitioner",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// parseTokens parses tokens as integers, skipping any that are not.
+func parseTokens(tokens []string) []*big.Int {
+	parsed := make([]*big.Int, 0, len(tokens))
+	for _, token := range tokens {
+		if value, ok := new(big.Int).SetString(token, 10); ok {
+			parsed = append(parsed, value)
+		}
+	}
+	sort.Slice(parsed, func(i, j int) bool {
+		return parsed[i].Cmp(parsed[j]) < 0
+	})
+	return parsed
+}
+
+// tokenOwnership returns the percentage of the ring of its datacenter each
+// node owns, by address. Each token owns the range from the previous token of
+// the datacenter, the first one wrapping around the ring.
+func tokenOwnership(nodes []*Node, ringSize *big.Int) map[string]float64 {
+	type ringToken struct {
+		token   *big.Int
+		address string
+	}
+	rings := map[string][]ringToken{}
+	for _, node := range nodes {
+		for _, token := range parseTokens(node.Tokens) {
+			rings[node.Datacenter] = append(rings[node.Datacenter], ringToken{token, node.Address})
+		}
+	}
+
+	ownership := map[string]float64{}
+	for _, ring := range rings {
+		sort.Slice(ring, func(i, j int) bool {
+			return ring[i].token.Cmp(ring[j].token) < 0
+		})
+		for i, current := range ring {
+			previous := ring[(i+len(ring)-1)%len(ring)]
+			size := new(big.Int).Sub(current.token, previous.token)
+			if size.Sign() <= 0 {
+				size.Add(size, ringSize)
+			}
+			share, _ := new(big.Float).Quo(new(big.Float).SetInt(size), new(big.Float).SetInt(ringSize)).Float64()
+			ownership[current.address] += share * 100
+		}
+	}
+	return ownership
+}
+
+// flattenTokenRing lists the tokens and ownership of the nodes of a
+// datacenter, or of all nodes if it is empty.
+func flattenTokenRing(nodes []*Node, datacenter string, partitioner string) []interface{} {
+	ownership := map[string]float64{}
+	if ringSize, ok := partitionerRingSizes[partitioner]; ok {
+		ownership = tokenOwnership(nodes, ringSize)
+	}
+	byAddress := make(map[string]*Node, len(nodes))
+	for _, node := range nodes {
+		byAddress[node.Address] = node
+	}
+
+	// Keep the order of flattenNodes but only the location of each node.
+	flattened := []interface{}{}
+	for _, raw := range flattenNodes(nodes, datacenter) {
+		node := raw.(map[string]interface{})
+		address := node["address"].(string)
+		tokens := []string{}
+		for _, token := range parseTokens(byAddress[address].Tokens) {
+			tokens = append(tokens, token.String())
+		}
+		flattened = append(flattened, map[string]interface{}{
+			"address":     address,
+			"datacenter":  node["datacenter"],
+			"rack":        node["rack"],
+			"tokens":      tokens,
+			"token_count": len(tokens),
+			"ownership":   ownership[address],
+		})
+	}
+	return flattened
+}
+
+func dataSourceTokenRingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	datacenter := d.Get("datacenter").(string)
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	info, err := queryClusterInfo(ctx, executor)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	nodes, err := queryNodes(ctx, executor)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(hash("token_ring:" + datacenter))
+	d.Set("partitioner", info.Partitioner)
+	d.Set("nodes", flattenTokenRing(nodes, datacenter, info.Partitioner))
+	return diags
+}
@@ -0,0 +1,49 @@
+package cassandra
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFlattenTokenRing(t *testing.T) {
+	nodes := []*Node{
+		{Address: "10.0.0.1", Datacenter: "dc1", Rack: "rack1", Tokens: []string{"0", "-4611686018427387904"}},
+		{Address: "10.0.0.2", Datacenter: "dc1", Rack: "rack1", Tokens: []string{"4611686018427387904"}},
+		{Address: "10.0.1.1", Datacenter: "dc2", Rack: "rack1", Tokens: []string{"42"}},
+	}
+
+	flattened := flattenTokenRing(nodes, "", "org.apache.cassandra.dht.Murmur3Partitioner")
+	expected := []struct {
+		address   string
+		tokens    []string
+		ownership float64
+	}{
+		// -2^62 owns (2^62, 2^63) and [-2^63, -2^62], 0 owns (-2^62, 0].
+		{"10.0.0.1", []string{"-4611686018427387904", "0"}, 75},
+		{"10.0.0.2", []string{"4611686018427387904"}, 25},
+		{"10.0.1.1", []string{"42"}, 100},
+	}
+	if len(flattened) != len(expected) {
+		t.Fatalf("expected %d nodes, got %v", len(expected), flattened)
+	}
+	for i, e := range expected {
+		node := flattened[i].(map[string]interface{})
+		tokens := node["tokens"].([]string)
+		if node["address"] != e.address || len(tokens) != len(e.tokens) || node["token_count"] != len(e.tokens) {
+			t.Fatalf("unexpected node %v", node)
+		}
+		for j := range tokens {
+			if tokens[j] != e.tokens[j] {
+				t.Fatalf("expected sorted tokens %v, got %v", e.tokens, tokens)
+			}
+		}
+		if ownership := node["ownership"].(float64); math.Abs(ownership-e.ownership) > 1e-9 {
+			t.Fatalf("expected %s to own %v%%, got %v%%", e.address, e.ownership, ownership)
+		}
+	}
+
+	flattened = flattenTokenRing(nodes, "dc2", "org.apache.cassandra.dht.ByteOrderedPartitioner")
+	if len(flattened) != 1 || flattened[0].(map[string]interface{})["ownership"].(float64) != 0 {
+		t.Fatalf("expected only dc2 without ownership, got %v", flattened)
+	}
+}
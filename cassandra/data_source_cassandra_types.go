@@ -0,0 +1,105 @@
+package cassandra
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/konradotto/terraform-provider-cassandra/cqlschema"
+)
+
+func dataSourceCassandraTypes() *schema.Resource {
+	return &schema.Resource{
+		Description: "List the user-defined types of a keyspace with their fields",
+		ReadContext: dataSourceTypesRead,
+		Schema: map[string]*schema.Schema{
+			"keyspace": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Keyspace to list the types of",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Names of the types, sorted",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"types": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Fields of the types, sorted by name",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the type",
+						},
+						"field": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "Fields of the type, in declaration order",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "Name of the field",
+									},
+									"type": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "CQL type of the field",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// flattenUserTypes lists the types of a keyspace sorted by name.
+func flattenUserTypes(types map[string][]cqlschema.Field) ([]string, []interface{}) {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	flattened := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		flattened = append(flattened, map[string]interface{}{
+			"name":  name,
+			"field": flattenUserTypeFields(types[name]),
+		})
+	}
+	return names, flattened
+}
+
+func dataSourceTypesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	keyspaceName := d.Get("keyspace").(string)
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	types, err := cqlschema.ReadUserTypes(ctx, executor, providerConfig.schemaKeyspace(), keyspaceName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	names, flattened := flattenUserTypes(types)
+	d.SetId(keyspaceName)
+	d.Set("names", names)
+	d.Set("types", flattened)
+	return diags
+}
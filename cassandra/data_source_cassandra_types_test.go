@@ -0,0 +1,23 @@
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/konradotto/terraform-provider-cassandra/cqlschema"
+)
+
+func TestFlattenUserTypes(t *testing.T) {
+	names, types := flattenUserTypes(map[string][]cqlschema.Field{
+		"phone":   {{Name: "country_code", Type: "int"}, {Name: "number", Type: "text"}},
+		"address": {{Name: "street", Type: "text"}},
+	})
+
+	if len(names) != 2 || names[0] != "address" || names[1] != "phone" {
+		t.Fatalf("expected sorted names [address phone], got %v", names)
+	}
+	phone := types[1].(map[string]interface{})
+	fields := phone["field"].([]interface{})
+	if len(fields) != 2 || fields[0].(map[string]interface{})["name"] != "country_code" {
+		t.Fatalf("expected fields in declaration order, got %v", fields)
+	}
+}
@@ -0,0 +1,83 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func ephemeralCassandraQuery() *providerEphemeralResource {
+	return &providerEphemeralResource{
+		schema: &tfprotov5.Schema{
+			Block: &tfprotov5.SchemaBlock{
+				Description: "Run a read-only SELECT statement during the Terraform run without storing the returned rows in plan files or state, e.g. to read a bootstrap secret",
+				Attributes: []*tfprotov5.SchemaAttribute{
+					{Name: "query", Type: tftypes.String, Required: true, Description: "SELECT statement to run, with ? placeholders for the parameters"},
+					{Name: "parameters", Type: tftypes.List{ElementType: tftypes.String}, Optional: true, Description: "Values bound to the ? placeholders of the query, in order. They are converted to the type of the column they are compared with, e.g. int or uuid"},
+					{Name: "columns", Type: tftypes.List{ElementType: tftypes.String}, Computed: true, Description: "Names of the returned columns, in order"},
+					{Name: "rows", Type: tftypes.List{ElementType: tftypes.Map{ElementType: tftypes.String}}, Computed: true, Sensitive: true, Description: "Returned rows as maps of column name to value, rendered like the rows of the cassandra_query data source"},
+				},
+			},
+		},
+		validate: validateEphemeralQuery,
+		open:     openEphemeralQuery,
+	}
+}
+
+func validateEphemeralQuery(config map[string]tftypes.Value) error {
+	if !config["query"].IsKnown() {
+		return nil
+	}
+	query, err := configString(config, "query")
+	if err != nil {
+		return err
+	}
+	if !selectStatementRegex.MatchString(query) {
+		return fmt.Errorf("query must be a SELECT statement")
+	}
+	return nil
+}
+
+// queryResult converts the columns and rows returned by runQuery to the
+// computed attributes of a cassandra_query ephemeral resource.
+func queryResult(columns []string, rows []interface{}) map[string]tftypes.Value {
+	columnValues := make([]tftypes.Value, 0, len(columns))
+	for _, column := range columns {
+		columnValues = append(columnValues, tftypes.NewValue(tftypes.String, column))
+	}
+	rowType := tftypes.Map{ElementType: tftypes.String}
+	rowValues := make([]tftypes.Value, 0, len(rows))
+	for _, row := range rows {
+		values := map[string]tftypes.Value{}
+		for column, value := range row.(map[string]interface{}) {
+			values[column] = tftypes.NewValue(tftypes.String, value)
+		}
+		rowValues = append(rowValues, tftypes.NewValue(rowType, values))
+	}
+	return map[string]tftypes.Value{
+		"columns": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, columnValues),
+		"rows":    tftypes.NewValue(tftypes.List{ElementType: rowType}, rowValues),
+	}
+}
+
+func openEphemeralQuery(ctx context.Context, providerConfig *ProviderConfig, config map[string]tftypes.Value) (map[string]tftypes.Value, []byte, error) {
+	query, err := configString(config, "query")
+	if err != nil {
+		return nil, nil, err
+	}
+	if !selectStatementRegex.MatchString(query) {
+		return nil, nil, fmt.Errorf("query must be a SELECT statement")
+	}
+	parameters, err := configStrings(config, "parameters")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columns, rows, err := providerConfig.selectQuery(ctx, query, parameters)
+	if err != nil {
+		return nil, nil, err
+	}
+	return queryResult(columns, rows), nil, nil
+}
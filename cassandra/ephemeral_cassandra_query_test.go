@@ -0,0 +1,77 @@
+package cassandra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestValidateEphemeralQuery(t *testing.T) {
+	server := NewProviderServer(Provider())
+	for query, valid := range map[string]bool{
+		"SELECT value FROM app.secrets WHERE name = ?": true,
+		"DELETE FROM app.secrets WHERE name = ?":       false,
+	} {
+		resp, err := server.ValidateEphemeralResourceConfig(context.Background(), &tfprotov5.ValidateEphemeralResourceConfigRequest{
+			TypeName: "cassandra_query",
+			Config:   ephemeralResourceRequestConfig(t, "cassandra_query", map[string]tftypes.Value{"query": tftypes.NewValue(tftypes.String, query)}),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (len(resp.Diagnostics) == 0) != valid {
+			t.Fatalf("expected %q to be valid: %t, got %v", query, valid, resp.Diagnostics)
+		}
+	}
+}
+
+func TestQueryResult(t *testing.T) {
+	result := queryResult([]string{"name", "value"}, []interface{}{map[string]interface{}{"name": "bootstrap", "value": "s3cr3t"}})
+
+	var columns []tftypes.Value
+	if err := result["columns"].As(&columns); err != nil {
+		t.Fatal(err)
+	}
+	var rows []tftypes.Value
+	if err := result["rows"].As(&rows); err != nil {
+		t.Fatal(err)
+	}
+	row := map[string]tftypes.Value{}
+	if err := rows[0].As(&row); err != nil {
+		t.Fatal(err)
+	}
+	var value string
+	if err := row["value"].As(&value); err != nil {
+		t.Fatal(err)
+	}
+	if len(columns) != 2 || len(rows) != 1 || value != "s3cr3t" {
+		t.Fatalf("unexpected result %v", result)
+	}
+}
+
+func TestOpenEphemeralQuery(t *testing.T) {
+	executor := newMockCQLExecutor()
+	query := "SELECT value FROM app.secrets WHERE name = ?"
+	executor.columns[query+" [bootstrap]"] = []string{"value"}
+	executor.rows[query+" [bootstrap]"] = []map[string]interface{}{{"value": "s3cr3t"}}
+
+	result, _, err := openEphemeralQuery(context.Background(), executor.providerConfig(), map[string]tftypes.Value{
+		"query":      tftypes.NewValue(tftypes.String, query),
+		"parameters": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "bootstrap")}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows []tftypes.Value
+	if err := result["rows"].As(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("expected one row, got %v", rows)
+	}
+	if executor.closed != 1 {
+		t.Fatalf("expected the query to close its executor, closed %d times", executor.closed)
+	}
+}
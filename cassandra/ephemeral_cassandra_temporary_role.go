@@ -0,0 +1,169 @@
+package cassandra
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+)
+
+const defaultTemporaryRolePrefix = "tf_tmp_"
+
+// temporaryRole is the private data of a cassandra_temporary_role, naming the
+// role to drop when it is closed.
+type temporaryRole struct {
+	Name string `json:"name"`
+}
+
+func ephemeralCassandraTemporaryRole() *providerEphemeralResource {
+	return &providerEphemeralResource{
+		schema: &tfprotov5.Schema{
+			Block: &tfprotov5.SchemaBlock{
+				Description: "Create a login role with a random name and password that is dropped when the Terraform run ends, so jobs get credentials that are never stored in state",
+				Attributes: []*tfprotov5.SchemaAttribute{
+					{Name: "name_prefix", Type: tftypes.String, Optional: true, Description: fmt.Sprintf("Prefix of the generated role name, %s by default", defaultTemporaryRolePrefix)},
+					{Name: "member_of", Type: tftypes.List{ElementType: tftypes.String}, Optional: true, Description: "Roles granted to the temporary role, which scope what it may do"},
+					{Name: "name", Type: tftypes.String, Computed: true, Description: "Name of the temporary role"},
+					{Name: "password", Type: tftypes.String, Computed: true, Sensitive: true, Description: "Generated password of the temporary role"},
+				},
+			},
+		},
+		validate: validateTemporaryRole,
+		open:     openTemporaryRole,
+		close:    closeTemporaryRole,
+	}
+}
+
+// configString returns a string attribute of a configuration, empty if null.
+func configString(config map[string]tftypes.Value, name string) (string, error) {
+	var value *string
+	if err := config[name].As(&value); err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	if value == nil {
+		return "", nil
+	}
+	return *value, nil
+}
+
+// configStrings returns a list of strings attribute of a configuration.
+func configStrings(config map[string]tftypes.Value, name string) ([]string, error) {
+	if config[name].IsNull() {
+		return nil, nil
+	}
+	elements := []tftypes.Value{}
+	if err := config[name].As(&elements); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	values := make([]string, 0, len(elements))
+	for _, element := range elements {
+		var value string
+		if err := element.As(&value); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// randomString returns n random bytes, encoded with encode.
+func randomString(n int, encode func([]byte) string) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encode(b), nil
+}
+
+func validateTemporaryRole(config map[string]tftypes.Value) error {
+	if !config["name_prefix"].IsKnown() {
+		return nil
+	}
+	prefix, err := configString(config, "name_prefix")
+	if err != nil {
+		return err
+	}
+	if len(prefix) > 240 {
+		return fmt.Errorf("name_prefix must be at most 240 characters, got %d", len(prefix))
+	}
+	return nil
+}
+
+func openTemporaryRole(ctx context.Context, providerConfig *ProviderConfig, config map[string]tftypes.Value) (map[string]tftypes.Value, []byte, error) {
+	if providerConfig.ReadOnly {
+		return nil, nil, fmt.Errorf("refusing to create a temporary role because read_only is set in the provider configuration")
+	}
+	prefix, err := configString(config, "name_prefix")
+	if err != nil {
+		return nil, nil, err
+	}
+	if prefix == "" {
+		prefix = defaultTemporaryRolePrefix
+	}
+	memberOf, err := configStrings(config, "member_of")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	suffix, err := randomString(8, hex.EncodeToString)
+	if err != nil {
+		return nil, nil, err
+	}
+	password, err := randomString(24, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return nil, nil, err
+	}
+	name := prefix + suffix
+
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer executor.Close()
+
+	log.Printf("Creating temporary role %s", name)
+	if err := executor.Exec(ctx, generateRoleQueryString("CREATE", name, password, "", true, nil)); err != nil {
+		return nil, nil, err
+	}
+	for _, role := range memberOf {
+		if err := executor.Exec(ctx, fmt.Sprintf(`GRANT %s TO %s`, cql.Literal(role), cql.Literal(name))); err != nil {
+			if dropErr := executor.Exec(ctx, fmt.Sprintf(`DROP ROLE IF EXISTS %s`, cql.Literal(name))); dropErr != nil {
+				log.Printf("[WARN] Dropping temporary role %s failed: %s", name, dropErr)
+			}
+			return nil, nil, fmt.Errorf("granting %s to temporary role %s: %w", role, name, err)
+		}
+	}
+
+	private, err := json.Marshal(temporaryRole{Name: name})
+	if err != nil {
+		return nil, nil, err
+	}
+	return map[string]tftypes.Value{
+		"name":     tftypes.NewValue(tftypes.String, name),
+		"password": tftypes.NewValue(tftypes.String, password),
+	}, private, nil
+}
+
+func closeTemporaryRole(ctx context.Context, providerConfig *ProviderConfig, private []byte) error {
+	var role temporaryRole
+	if err := json.Unmarshal(private, &role); err != nil {
+		return err
+	}
+
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return err
+	}
+	defer executor.Close()
+
+	log.Printf("Dropping temporary role %s", role.Name)
+	return executor.Exec(ctx, fmt.Sprintf(`DROP ROLE IF EXISTS %s`, cql.Literal(role.Name)))
+}
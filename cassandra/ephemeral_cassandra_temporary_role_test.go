@@ -0,0 +1,84 @@
+package cassandra
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestTemporaryRoleLifecycle(t *testing.T) {
+	executor := newMockCQLExecutor()
+	provider := Provider()
+	provider.SetMeta(executor.providerConfig())
+	server := NewProviderServer(provider)
+
+	opened, err := server.OpenEphemeralResource(context.Background(), &tfprotov5.OpenEphemeralResourceRequest{
+		TypeName: "cassandra_temporary_role",
+		Config: ephemeralResourceRequestConfig(t, "cassandra_temporary_role", map[string]tftypes.Value{
+			"member_of": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "reader")}),
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opened.Diagnostics) != 0 {
+		t.Fatal(opened.Diagnostics[0].Detail)
+	}
+
+	value, err := opened.Result.Unmarshal(providerEphemeralResources()["cassandra_temporary_role"].schema.ValueType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := map[string]tftypes.Value{}
+	if err := value.As(&result); err != nil {
+		t.Fatal(err)
+	}
+	var name, password string
+	if err := result["name"].As(&name); err != nil {
+		t.Fatal(err)
+	}
+	if err := result["password"].As(&password); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(name, defaultTemporaryRolePrefix) || len(password) != 32 {
+		t.Fatalf("expected a generated name and password, got %q and %d characters", name, len(password))
+	}
+	if len(executor.executed) != 2 ||
+		executor.executed[0] != "CREATE ROLE '"+name+"' WITH PASSWORD = '"+password+"' AND LOGIN = true" ||
+		executor.executed[1] != "GRANT 'reader' TO '"+name+"'" {
+		t.Fatalf("expected the role to be created and granted reader, got %v", executor.executed)
+	}
+
+	closed, err := server.CloseEphemeralResource(context.Background(), &tfprotov5.CloseEphemeralResourceRequest{
+		TypeName: "cassandra_temporary_role",
+		Private:  opened.Private,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(closed.Diagnostics) != 0 || executor.executed[2] != "DROP ROLE IF EXISTS '"+name+"'" {
+		t.Fatalf("expected the role to be dropped, got %v", executor.executed)
+	}
+}
+
+func TestTemporaryRole_readOnly(t *testing.T) {
+	executor := newMockCQLExecutor()
+	provider := Provider()
+	providerConfig := executor.providerConfig()
+	providerConfig.ReadOnly = true
+	provider.SetMeta(providerConfig)
+
+	opened, err := NewProviderServer(provider).OpenEphemeralResource(context.Background(), &tfprotov5.OpenEphemeralResourceRequest{
+		TypeName: "cassandra_temporary_role",
+		Config:   ephemeralResourceRequestConfig(t, "cassandra_temporary_role", nil),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opened.Diagnostics) != 1 || len(executor.executed) != 0 {
+		t.Fatalf("expected a read-only provider to refuse creating the role, got %v", executor.executed)
+	}
+}
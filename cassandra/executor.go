@@ -0,0 +1,111 @@
+package cassandra
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// CQLExecutor runs CQL against the cluster. The resources run all their
+// queries through it, so they can be unit tested with an in-memory
+// implementation instead of a live cluster.
+type CQLExecutor interface {
+	// Exec executes a statement returning no rows.
+	Exec(ctx context.Context, query string, values ...interface{}) error
+	// ExecSchemaChange executes a DDL statement, retrying it while it races
+	// concurrent schema changes until timeout expires.
+	ExecSchemaChange(ctx context.Context, query string, timeout time.Duration) error
+	// Select returns the rows of a query as maps of column name to value.
+	Select(ctx context.Context, query string, values ...interface{}) ([]map[string]interface{}, error)
+	// SelectColumns returns the names of the columns of a query, in order, and
+	// its rows as Select does.
+	SelectColumns(ctx context.Context, query string, values ...interface{}) ([]string, []map[string]interface{}, error)
+	// KeyspaceMetadata returns the metadata of a keyspace as currently known
+	// to the executor, or gocql.ErrKeyspaceDoesNotExist.
+	KeyspaceMetadata(keyspace string) (*gocql.KeyspaceMetadata, error)
+	// AwaitSchemaAgreement waits until all nodes report the same schema.
+	AwaitSchemaAgreement(ctx context.Context) error
+	// Close releases the connections of the executor.
+	Close()
+}
+
+// newExecutor returns the executor resources run their queries with: the one
+// of NewExecutor if set, a gocql session otherwise.
+func (c *ProviderConfig) newExecutor() (CQLExecutor, error) {
+	if c.NewExecutor != nil {
+		return c.NewExecutor()
+	}
+
+	start := time.Now()
+	session, err := c.Cluster.CreateSession()
+	elapsed := time.Since(start)
+	log.Printf("Getting a session took %s", elapsed)
+
+	if err != nil {
+		return nil, err
+	}
+	return &gocqlExecutor{session: session}, nil
+}
+
+// sharedExecutor returns the executor of the shared session, see
+// sharedSession, or the one of NewExecutor if set. Closing it leaves the
+// shared session open.
+func (c *ProviderConfig) sharedExecutor() (CQLExecutor, error) {
+	if c.NewExecutor != nil {
+		return c.NewExecutor()
+	}
+
+	session, err := c.sharedSession()
+	if err != nil {
+		return nil, err
+	}
+	return &gocqlExecutor{session: session, shared: true}, nil
+}
+
+// gocqlExecutor is the CQLExecutor running queries in a gocql session.
+type gocqlExecutor struct {
+	session *gocql.Session
+	// shared executors leave the session open when closed.
+	shared bool
+}
+
+func (e *gocqlExecutor) Exec(ctx context.Context, query string, values ...interface{}) error {
+	return e.session.Query(query, values...).WithContext(ctx).Exec()
+}
+
+func (e *gocqlExecutor) ExecSchemaChange(ctx context.Context, query string, timeout time.Duration) error {
+	return execSchemaChange(ctx, e.session, query, timeout)
+}
+
+func (e *gocqlExecutor) Select(ctx context.Context, query string, values ...interface{}) ([]map[string]interface{}, error) {
+	return e.session.Query(query, values...).WithContext(ctx).Iter().SliceMap()
+}
+
+func (e *gocqlExecutor) SelectColumns(ctx context.Context, query string, values ...interface{}) ([]string, []map[string]interface{}, error) {
+	iter := e.session.Query(query, values...).WithContext(ctx).Iter()
+	columns := []string{}
+	for _, column := range iter.Columns() {
+		columns = append(columns, column.Name)
+	}
+	rows, err := iter.SliceMap()
+	if err != nil {
+		return nil, nil, err
+	}
+	return columns, rows, nil
+}
+
+func (e *gocqlExecutor) KeyspaceMetadata(keyspace string) (*gocql.KeyspaceMetadata, error) {
+	return e.session.KeyspaceMetadata(keyspace)
+}
+
+func (e *gocqlExecutor) AwaitSchemaAgreement(ctx context.Context) error {
+	return e.session.AwaitSchemaAgreement(ctx)
+}
+
+func (e *gocqlExecutor) Close() {
+	if !e.shared {
+		e.session.Close()
+	}
+}
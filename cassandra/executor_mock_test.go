@@ -0,0 +1,93 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// mockCQLExecutor is an in-memory CQLExecutor recording the statements it
+// executes and answering queries with canned rows.
+type mockCQLExecutor struct {
+	// executed lists the statements passed to Exec and ExecSchemaChange.
+	executed []string
+	// rows maps queries to the rows Select returns, no rows by default.
+	rows map[string][]map[string]interface{}
+	// columns maps queries to the columns SelectColumns returns.
+	columns map[string][]string
+	// errors maps statements and queries to the error they fail with.
+	errors map[string]error
+	// keyspaces maps keyspace names to the metadata KeyspaceMetadata returns.
+	keyspaces map[string]*gocql.KeyspaceMetadata
+	closed    int
+}
+
+func newMockCQLExecutor() *mockCQLExecutor {
+	return &mockCQLExecutor{
+		rows:      map[string][]map[string]interface{}{},
+		columns:   map[string][]string{},
+		errors:    map[string]error{},
+		keyspaces: map[string]*gocql.KeyspaceMetadata{},
+	}
+}
+
+// providerConfig returns a ProviderConfig whose resources use the executor.
+func (e *mockCQLExecutor) providerConfig() *ProviderConfig {
+	return &ProviderConfig{
+		SystemKeyspaceName: "system_auth",
+		Mode:               modeCassandra,
+		NewExecutor: func() (CQLExecutor, error) {
+			return e, nil
+		},
+	}
+}
+
+func (e *mockCQLExecutor) Exec(ctx context.Context, query string, values ...interface{}) error {
+	e.executed = append(e.executed, renderMockQuery(query, values))
+	return e.errors[query]
+}
+
+func (e *mockCQLExecutor) ExecSchemaChange(ctx context.Context, query string, timeout time.Duration) error {
+	return e.Exec(ctx, query)
+}
+
+func (e *mockCQLExecutor) Select(ctx context.Context, query string, values ...interface{}) ([]map[string]interface{}, error) {
+	if err := e.errors[query]; err != nil {
+		return nil, err
+	}
+	return e.rows[renderMockQuery(query, values)], nil
+}
+
+func (e *mockCQLExecutor) SelectColumns(ctx context.Context, query string, values ...interface{}) ([]string, []map[string]interface{}, error) {
+	rows, err := e.Select(ctx, query, values...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return e.columns[renderMockQuery(query, values)], rows, nil
+}
+
+func (e *mockCQLExecutor) KeyspaceMetadata(keyspace string) (*gocql.KeyspaceMetadata, error) {
+	if metadata, ok := e.keyspaces[keyspace]; ok {
+		return metadata, nil
+	}
+	return nil, gocql.ErrKeyspaceDoesNotExist
+}
+
+func (e *mockCQLExecutor) AwaitSchemaAgreement(ctx context.Context) error {
+	return nil
+}
+
+func (e *mockCQLExecutor) Close() {
+	e.closed++
+}
+
+// renderMockQuery appends the bound values to a query, so that canned rows
+// can depend on them.
+func renderMockQuery(query string, values []interface{}) string {
+	if len(values) == 0 {
+		return query
+	}
+	return fmt.Sprintf("%s %v", query, values)
+}
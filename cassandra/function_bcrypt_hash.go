@@ -0,0 +1,107 @@
+package cassandra
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"golang.org/x/crypto/blowfish"
+)
+
+const (
+	bcryptMinCost     = 4
+	bcryptMaxCost     = 16
+	bcryptMaxPassword = 72
+)
+
+var (
+	bcryptEncoding = base64.NewEncoding("./ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789").WithPadding(base64.NoPadding)
+
+	// bcryptMagic is "OrpheanBeholderScryDoubt", encrypted by bcrypt.
+	bcryptMagic = []byte("OrpheanBeholderScryDoubt")
+
+	// bcryptSaltRegex matches the 22 characters encoding the 16 byte salt of
+	// a bcrypt hash.
+	bcryptSaltRegex = regexp.MustCompile(`^[./A-Za-z0-9]{22}$`)
+)
+
+func functionBcryptHash() *providerFunction {
+	return &providerFunction{
+		definition: &tfprotov5.Function{
+			Summary:     "Hash a password with bcrypt",
+			Description: "Hashes a password with bcrypt for hashed_password of cassandra_role, so the plaintext password is neither sent in CQL nor stored in state. The salt is passed in, e.g. from a random_password resource, so the hash is stable across plans and applies.",
+			Parameters: []*tfprotov5.FunctionParameter{
+				{Name: "password", Type: tftypes.String, Description: "Plaintext password, at most 72 bytes"},
+				{Name: "cost", Type: tftypes.Number, Description: fmt.Sprintf("Logarithm of the number of key expansion rounds, between %d and %d. Cassandra uses 10", bcryptMinCost, bcryptMaxCost)},
+				{Name: "salt", Type: tftypes.String, Description: "Salt of 22 characters out of ./A-Z, a-z and 0-9, e.g. the result of a random_password resource with length 22 and special false. Use a salt of its own for every role"},
+			},
+			Return: &tfprotov5.FunctionReturn{Type: tftypes.String},
+		},
+		call: func(arguments []tftypes.Value) (tftypes.Value, error) {
+			var password string
+			if err := arguments[0].As(&password); err != nil {
+				return tftypes.Value{}, err
+			}
+			var cost big.Float
+			if err := arguments[1].As(&cost); err != nil {
+				return tftypes.Value{}, err
+			}
+			rounds, accuracy := cost.Int64()
+			if accuracy != big.Exact || rounds < bcryptMinCost || rounds > bcryptMaxCost {
+				return tftypes.Value{}, fmt.Errorf("cost must be a whole number between %d and %d, got %s", bcryptMinCost, bcryptMaxCost, cost.String())
+			}
+			var salt string
+			if err := arguments[2].As(&salt); err != nil {
+				return tftypes.Value{}, err
+			}
+
+			hash, err := bcryptHash(password, salt, int(rounds))
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			return tftypes.NewValue(tftypes.String, hash), nil
+		},
+	}
+}
+
+// bcryptHash hashes a password in the $2a$ format Cassandra stores. Provider
+// functions must return the same result for the same arguments, so unlike
+// golang.org/x/crypto/bcrypt the salt is passed in instead of being random.
+func bcryptHash(password string, encodedSalt string, cost int) (string, error) {
+	if len(password) > bcryptMaxPassword {
+		return "", fmt.Errorf("password must not be longer than %d bytes", bcryptMaxPassword)
+	}
+	if !bcryptSaltRegex.MatchString(encodedSalt) {
+		return "", fmt.Errorf("salt must be 22 characters out of ./A-Z, a-z and 0-9")
+	}
+	// The last character only contributes its two high bits to the salt.
+	salt, err := bcryptEncoding.DecodeString(encodedSalt)
+	if err != nil {
+		return "", err
+	}
+
+	// Like the C implementation, include the trailing NUL of the key.
+	key := append([]byte(password), 0)
+	cipher, err := blowfish.NewSaltedCipher(key, salt)
+	if err != nil {
+		return "", err
+	}
+	for i := uint64(0); i < 1<<uint(cost); i++ {
+		blowfish.ExpandKey(key, cipher)
+		blowfish.ExpandKey(salt, cipher)
+	}
+
+	data := make([]byte, len(bcryptMagic))
+	copy(data, bcryptMagic)
+	for i := 0; i < len(data); i += 8 {
+		for j := 0; j < 64; j++ {
+			cipher.Encrypt(data[i:i+8], data[i:i+8])
+		}
+	}
+
+	// Like the C implementation, only encode 23 of the 24 encrypted bytes.
+	return fmt.Sprintf("$2a$%02d$%s%s", cost, bcryptEncoding.EncodeToString(salt), bcryptEncoding.EncodeToString(data[:23])), nil
+}
@@ -0,0 +1,58 @@
+package cassandra
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestBcryptHash(t *testing.T) {
+	salt := "kVQ1mEf2xQ0eTjqS8gHnVe"
+	hash, err := bcryptHash("sup3rS3cr3t", salt, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "$2a$04$"+salt[:21]) || !bcryptHashRegex.MatchString(hash) {
+		t.Fatalf("unexpected hash %s", hash)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte("sup3rS3cr3t")); err != nil {
+		t.Fatalf("expected the hash to match the password: %s", err)
+	}
+	if again, _ := bcryptHash("sup3rS3cr3t", salt, 4); again != hash {
+		t.Fatalf("expected a stable hash, got %s and %s", hash, again)
+	}
+	if other, _ := bcryptHash("sup3rS3cr3t", "Q2b7ZsXw9LmPe4RtYu1IoA", 4); other == hash {
+		t.Fatalf("expected another salt to give another hash, got %s twice", hash)
+	}
+	if _, err := bcryptHash(strings.Repeat("x", 73), salt, 4); err == nil {
+		t.Fatal("expected an error for a password longer than 72 bytes")
+	}
+	for _, invalid := range []string{"", "short", "kVQ1mEf2xQ0eTjqS8gHnV-", "kVQ1mEf2xQ0eTjqS8gHnVeX"} {
+		if _, err := bcryptHash("sup3rS3cr3t", invalid, 4); err == nil {
+			t.Fatalf("expected an error for salt %q", invalid)
+		}
+	}
+}
+
+func TestFunctionBcryptHash(t *testing.T) {
+	salt := tftypes.NewValue(tftypes.String, "kVQ1mEf2xQ0eTjqS8gHnVe")
+	result, funcErr := callProviderFunction(t, "bcrypt_hash", tftypes.NewValue(tftypes.String, "sup3rS3cr3t"), tftypes.NewValue(tftypes.Number, 4), salt)
+	if funcErr != nil {
+		t.Fatal(funcErr.Text)
+	}
+	var hash string
+	if err := result.As(&hash); err != nil {
+		t.Fatal(err)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte("sup3rS3cr3t")); err != nil {
+		t.Fatalf("expected the hash to match the password: %s", err)
+	}
+
+	for _, cost := range []int{3, 17} {
+		if _, funcErr := callProviderFunction(t, "bcrypt_hash", tftypes.NewValue(tftypes.String, "sup3rS3cr3t"), tftypes.NewValue(tftypes.Number, cost), salt); funcErr == nil {
+			t.Fatalf("expected an error for cost %d", cost)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package cassandra
+
+import (
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+)
+
+func functionCQLType() *providerFunction {
+	return &providerFunction{
+		definition: &tfprotov5.Function{
+			Summary:     "Validate and normalize a CQL type",
+			Description: "Parses a CQL type expression, e.g. map<varchar, frozen<list<int>>>, and returns it in the spelling Cassandra stores, e.g. map<text, frozen<list<int>>>. Names which are not native types must be passed as user_types, so a misspelled type fails at plan time.",
+			Parameters: []*tfprotov5.FunctionParameter{
+				{Name: "type", Type: tftypes.String, Description: "CQL type expression"},
+			},
+			VariadicParameter: &tfprotov5.FunctionParameter{
+				Name:        "user_types",
+				Type:        tftypes.String,
+				Description: "Names of the user-defined types the expression may reference",
+			},
+			Return: &tfprotov5.FunctionReturn{Type: tftypes.String},
+		},
+		call: func(arguments []tftypes.Value) (tftypes.Value, error) {
+			var cqlType string
+			if err := arguments[0].As(&cqlType); err != nil {
+				return tftypes.Value{}, err
+			}
+			userTypes := make([]string, 0, len(arguments)-1)
+			for _, argument := range arguments[1:] {
+				var userType string
+				if err := argument.As(&userType); err != nil {
+					return tftypes.Value{}, err
+				}
+				userTypes = append(userTypes, userType)
+			}
+
+			normalized, err := cql.ParseType(cqlType, userTypes)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			return tftypes.NewValue(tftypes.String, normalized), nil
+		},
+	}
+}
@@ -0,0 +1,28 @@
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestFunctionCQLType(t *testing.T) {
+	result, funcErr := callProviderFunction(t, "cql_type",
+		tftypes.NewValue(tftypes.String, "map<varchar, frozen<address>>"),
+		tftypes.NewValue(tftypes.String, "address"),
+	)
+	if funcErr != nil {
+		t.Fatal(funcErr.Text)
+	}
+	var value string
+	if err := result.As(&value); err != nil {
+		t.Fatal(err)
+	}
+	if value != "map<text, frozen<address>>" {
+		t.Fatalf("unexpected result %q", value)
+	}
+
+	if _, funcErr := callProviderFunction(t, "cql_type", tftypes.NewValue(tftypes.String, "frozen<address>")); funcErr == nil {
+		t.Fatal("expected an error for a user-defined type not passed as user_types")
+	}
+}
@@ -0,0 +1,12 @@
+package cassandra
+
+import "github.com/konradotto/terraform-provider-cassandra/cql"
+
+func functionEscapeLiteral() *providerFunction {
+	return stringFunction(
+		"Render a CQL string literal",
+		"Renders a value as a single-quoted CQL string literal, escaping embedded single quotes, e.g. escape_literal(\"it's\") returns 'it''s'.",
+		"value",
+		cql.Literal,
+	)
+}
@@ -0,0 +1,240 @@
+package cassandra
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+)
+
+// partitionKeyTypes maps the CQL types supported in partition keys by
+// murmur3_token to the types used to serialize them.
+var partitionKeyTypes = map[string]gocql.Type{
+	"ascii":     gocql.TypeAscii,
+	"bigint":    gocql.TypeBigInt,
+	"blob":      gocql.TypeBlob,
+	"boolean":   gocql.TypeBoolean,
+	"date":      gocql.TypeDate,
+	"double":    gocql.TypeDouble,
+	"float":     gocql.TypeFloat,
+	"inet":      gocql.TypeInet,
+	"int":       gocql.TypeInt,
+	"smallint":  gocql.TypeSmallInt,
+	"text":      gocql.TypeText,
+	"timestamp": gocql.TypeTimestamp,
+	"timeuuid":  gocql.TypeTimeUUID,
+	"tinyint":   gocql.TypeTinyInt,
+	"uuid":      gocql.TypeUUID,
+	"varint":    gocql.TypeVarint,
+}
+
+func functionMurmur3Token() *providerFunction {
+	return &providerFunction{
+		definition: &tfprotov5.Function{
+			Summary:     "Compute the token of a partition key",
+			Description: "Computes the Murmur3Partitioner token of a partition key from the CQL types and values of its columns, i.e. the result of SELECT token(...) for the row.",
+			Parameters: []*tfprotov5.FunctionParameter{
+				{Name: "key_types", Type: tftypes.List{ElementType: tftypes.String}, Description: "CQL types of the partition key columns, in order, e.g. [\"uuid\"] or [\"text\", \"int\"]"},
+				{Name: "key_values", Type: tftypes.List{ElementType: tftypes.String}, Description: "Values of the partition key columns, in order. Blobs are written as 0x-prefixed hex, timestamps as RFC 3339 or milliseconds since the epoch and dates as YYYY-MM-DD"},
+			},
+			Return: &tfprotov5.FunctionReturn{Type: tftypes.Number},
+		},
+		call: func(arguments []tftypes.Value) (tftypes.Value, error) {
+			keyTypes, err := stringListValue(arguments[0])
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			keyValues, err := stringListValue(arguments[1])
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			token, err := murmur3Token(keyTypes, keyValues)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			return tftypes.NewValue(tftypes.Number, new(big.Float).SetInt64(token)), nil
+		},
+	}
+}
+
+// stringListValue returns the elements of a list of strings, rejecting null
+// and unknown elements.
+func stringListValue(list tftypes.Value) ([]string, error) {
+	var elements []tftypes.Value
+	if err := list.As(&elements); err != nil {
+		return nil, err
+	}
+	ret := make([]string, 0, len(elements))
+	for i, element := range elements {
+		if element.IsNull() {
+			return nil, fmt.Errorf("element %d must not be null", i)
+		}
+		var value string
+		if err := element.As(&value); err != nil {
+			return nil, err
+		}
+		ret = append(ret, value)
+	}
+	return ret, nil
+}
+
+// murmur3Token returns the token Murmur3Partitioner assigns to a partition key.
+func murmur3Token(keyTypes []string, keyValues []string) (int64, error) {
+	if len(keyTypes) == 0 {
+		return 0, fmt.Errorf("at least one partition key column is required")
+	}
+	if len(keyTypes) != len(keyValues) {
+		return 0, fmt.Errorf("got %d partition key types but %d values", len(keyTypes), len(keyValues))
+	}
+
+	components := make([][]byte, 0, len(keyTypes))
+	for i, keyType := range keyTypes {
+		component, err := serializePartitionKeyValue(keyType, keyValues[i])
+		if err != nil {
+			return 0, err
+		}
+		components = append(components, component)
+	}
+
+	key := components[0]
+	if len(components) > 1 {
+		// Composite partition keys are serialized as the length, the bytes
+		// and an end-of-component byte for each column.
+		key = []byte{}
+		for _, component := range components {
+			key = binary.BigEndian.AppendUint16(key, uint16(len(component)))
+			key = append(key, component...)
+			key = append(key, 0)
+		}
+	}
+
+	token := murmur3H1(key)
+	if token == math.MinInt64 {
+		// Murmur3Partitioner reserves the minimum token.
+		return math.MaxInt64, nil
+	}
+	return token, nil
+}
+
+func serializePartitionKeyValue(keyType string, value string) ([]byte, error) {
+	name := cql.NativeType(normalizeCQLType(keyType))
+	typ, ok := partitionKeyTypes[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported partition key type %s", keyType)
+	}
+
+	var converted interface{} = value
+	var err error
+	switch name {
+	case "blob":
+		if !strings.HasPrefix(value, "0x") {
+			return nil, fmt.Errorf("blob value %q must be 0x-prefixed hex", value)
+		}
+		converted, err = hex.DecodeString(value[2:])
+	case "boolean":
+		converted, err = strconv.ParseBool(value)
+	case "double":
+		converted, err = strconv.ParseFloat(value, 64)
+	case "float":
+		var parsed float64
+		parsed, err = strconv.ParseFloat(value, 32)
+		converted = float32(parsed)
+	case "timestamp":
+		if millis, parseErr := strconv.ParseInt(value, 10, 64); parseErr == nil {
+			converted = millis
+		} else {
+			converted, err = time.Parse(time.RFC3339Nano, value)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value %q: %s", name, value, err)
+	}
+
+	serialized, err := gocql.Marshal(gocql.NewNativeType(4, typ, ""), converted)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value %q: %s", name, value, err)
+	}
+	return serialized, nil
+}
+
+// murmur3H1 returns the first half of the 128-bit x64 MurmurHash3 the way
+// Cassandra computes it, i.e. sign-extending the bytes of the tail.
+func murmur3H1(data []byte) int64 {
+	const (
+		c1 int64 = -8663945395140668459 // 0x87c37b91114253d5
+		c2 int64 = 5545529020109919103  // 0x4cf5ad432745937f
+	)
+	rotl := func(x int64, r uint) int64 {
+		return x<<r | int64(uint64(x)>>(64-r))
+	}
+	fmix := func(k int64) int64 {
+		k ^= int64(uint64(k) >> 33)
+		k *= -49064778989728563 // 0xff51afd7ed558ccd
+		k ^= int64(uint64(k) >> 33)
+		k *= -4265267296055464877 // 0xc4ceb9fe1a85ec53
+		k ^= int64(uint64(k) >> 33)
+		return k
+	}
+
+	var h1, h2 int64
+	blocks := len(data) / 16
+	for i := 0; i < blocks; i++ {
+		k1 := int64(binary.LittleEndian.Uint64(data[i*16:]))
+		k2 := int64(binary.LittleEndian.Uint64(data[i*16+8:]))
+
+		k1 *= c1
+		k1 = rotl(k1, 31)
+		k1 *= c2
+		h1 ^= k1
+		h1 = rotl(h1, 27)
+		h1 += h2
+		h1 = h1*5 + 0x52dce729
+
+		k2 *= c2
+		k2 = rotl(k2, 33)
+		k2 *= c1
+		h2 ^= k2
+		h2 = rotl(h2, 31)
+		h2 += h1
+		h2 = h2*5 + 0x38495ab5
+	}
+
+	tail := data[blocks*16:]
+	var k1, k2 int64
+	for i := len(tail) - 1; i >= 8; i-- {
+		k2 ^= int64(int8(tail[i])) << (8 * uint(i-8))
+	}
+	if len(tail) > 8 {
+		k2 *= c2
+		k2 = rotl(k2, 33)
+		k2 *= c1
+		h2 ^= k2
+	}
+	for i := min(len(tail), 8) - 1; i >= 0; i-- {
+		k1 ^= int64(int8(tail[i])) << (8 * uint(i))
+	}
+	if len(tail) > 0 {
+		k1 *= c1
+		k1 = rotl(k1, 31)
+		k1 *= c2
+		h1 ^= k1
+	}
+
+	h1 ^= int64(len(data))
+	h2 ^= int64(len(data))
+	h1 += h2
+	h2 += h1
+	h1 = fmix(h1)
+	h2 = fmix(h2)
+	return h1 + h2
+}
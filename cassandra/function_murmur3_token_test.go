@@ -0,0 +1,99 @@
+package cassandra
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestMurmur3H1(t *testing.T) {
+	// Generated with the Java driver, covering every length of the tail.
+	series := []uint64{
+		0x0000000000000000, 0x2ac9debed546a380, 0x649e4eaa7fc1708e, 0xce68f60d7c353bdb,
+		0x0f95757ce7f38254, 0x0f04e459497f3fc1, 0x88c0a92586be0a27, 0x13eb9fb82606f7a6,
+		0x8236039b7387354d, 0x4c1e87519fe738ba, 0x3f9652ac3effeb24, 0x3f33760ded9006c6,
+		0xaed70a6631854cb1, 0x8a299a8f8e0e2da7, 0x624b675c779249a6, 0xa4b203bb1d90b9a3,
+		0xa3293ad698ecb99a, 0xbc740023dbd50048, 0x3fe5ab9837d25cdd, 0x2d0338c1ca87d132,
+	}
+	sample := ""
+	for i, expected := range series {
+		if hash := murmur3H1([]byte(sample)); hash != int64(expected) {
+			t.Fatalf("expected %x for %q, got %x", int64(expected), sample, hash)
+		}
+		sample += strconv.Itoa(i % 10)
+	}
+
+	key, _ := hex.DecodeString("00104327529fb645dd00b883ec39ae448bb800000400066a6b00")
+	if hash := murmur3H1(key); hash != -9223371632693506265 {
+		t.Fatalf("expected the bytes of the tail to be sign-extended, got %d", hash)
+	}
+}
+
+func TestMurmur3Token(t *testing.T) {
+	for _, test := range []struct {
+		keyTypes  []string
+		keyValues []string
+		expected  int64
+	}{
+		{[]string{"int"}, []string{"1"}, -4069959284402364209},
+		{[]string{"varchar"}, []string{"hello"}, int64(-0x3427584cbe4264fe)},
+		{[]string{"blob"}, []string{"0x68656c6c6f"}, int64(-0x3427584cbe4264fe)},
+	} {
+		token, err := murmur3Token(test.keyTypes, test.keyValues)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != test.expected {
+			t.Fatalf("expected token %d for %v, got %d", test.expected, test.keyValues, token)
+		}
+	}
+
+	composite, err := murmur3Token([]string{"text", "int"}, []string{"hello", "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if composite == -0x3427584cbe4264fe {
+		t.Fatal("expected a composite partition key to be serialized with its components")
+	}
+
+	for _, test := range []struct {
+		keyTypes  []string
+		keyValues []string
+	}{
+		{[]string{}, []string{}},
+		{[]string{"int"}, []string{"1", "2"}},
+		{[]string{"int"}, []string{"one"}},
+		{[]string{"blob"}, []string{"68656c6c6f"}},
+		{[]string{"list<int>"}, []string{"[1]"}},
+		{[]string{"uuid"}, []string{"not-a-uuid"}},
+	} {
+		if _, err := murmur3Token(test.keyTypes, test.keyValues); err == nil {
+			t.Fatalf("expected an error for %v %v", test.keyTypes, test.keyValues)
+		}
+	}
+}
+
+func TestFunctionMurmur3Token(t *testing.T) {
+	list := func(values ...string) tftypes.Value {
+		elements := make([]tftypes.Value, 0, len(values))
+		for _, value := range values {
+			elements = append(elements, tftypes.NewValue(tftypes.String, value))
+		}
+		return tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, elements)
+	}
+
+	result, funcErr := callProviderFunction(t, "murmur3_token", list("int"), list("1"))
+	if funcErr != nil {
+		t.Fatal(funcErr.Text)
+	}
+	var token big.Float
+	if err := result.As(&token); err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := token.Int64(); value != -4069959284402364209 {
+		t.Fatalf("unexpected token %s", token.String())
+	}
+}
@@ -0,0 +1,12 @@
+package cassandra
+
+import "github.com/konradotto/terraform-provider-cassandra/cql"
+
+func functionQuoteIdentifier() *providerFunction {
+	return stringFunction(
+		"Quote a CQL identifier",
+		"Double-quotes a keyspace, table, column or role name for use in CQL, escaping embedded double quotes, e.g. quote_identifier(\"My\\\"Table\") returns \"My\"\"Table\". Quoted identifiers keep their case.",
+		"identifier",
+		cql.QuoteIdentifier,
+	)
+}
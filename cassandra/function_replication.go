@@ -0,0 +1,139 @@
+package cassandra
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+)
+
+var (
+	// replicationFactorRegex matches a replication factor, optionally with
+	// the transient replication notation, e.g. 3 or 3/1.
+	replicationFactorRegex = regexp.MustCompile(`^[0-9]+(/[0-9]+)?$`)
+
+	replicationReturnType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"class":   tftypes.String,
+		"options": tftypes.Map{ElementType: tftypes.String},
+		"cql":     tftypes.String,
+	}}
+)
+
+func functionReplication() *providerFunction {
+	return &providerFunction{
+		definition: &tfprotov5.Function{
+			Summary:     "Build the replication settings of a keyspace",
+			Description: "Builds the replication settings of a keyspace from a strategy and either a single replication factor or a map of datacenter to replication factor. Returns an object with the strategy class, the options for strategy_options of cassandra_keyspace and the CQL map literal for a REPLICATION clause.",
+			Parameters: []*tfprotov5.FunctionParameter{
+				{Name: "strategy", Type: tftypes.String, Description: "SimpleStrategy or NetworkTopologyStrategy"},
+				{Name: "replication_factors", Type: tftypes.DynamicPseudoType, Description: "Replication factor, or map of datacenter to replication factor for NetworkTopologyStrategy, e.g. { dc1 = 3, dc2 = \"3/1\" }"},
+			},
+			Return: &tfprotov5.FunctionReturn{Type: replicationReturnType},
+		},
+		call: func(arguments []tftypes.Value) (tftypes.Value, error) {
+			var strategy string
+			if err := arguments[0].As(&strategy); err != nil {
+				return tftypes.Value{}, err
+			}
+			options, err := replicationOptions(strategy, arguments[1])
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+
+			values := make(map[string]tftypes.Value, len(options))
+			for key, value := range options {
+				values[key] = tftypes.NewValue(tftypes.String, value)
+			}
+			return tftypes.NewValue(replicationReturnType, map[string]tftypes.Value{
+				"class":   tftypes.NewValue(tftypes.String, strategy),
+				"options": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, values),
+				"cql":     tftypes.NewValue(tftypes.String, renderReplicationMap(strategy, options)),
+			}), nil
+		},
+	}
+}
+
+// replicationOptions returns the strategy options for a single replication
+// factor or a map of datacenter to replication factor.
+func replicationOptions(strategy string, factors tftypes.Value) (map[string]string, error) {
+	if strategy != "SimpleStrategy" && strategy != "NetworkTopologyStrategy" {
+		return nil, fmt.Errorf("strategy must be SimpleStrategy or NetworkTopologyStrategy, got %s", strategy)
+	}
+
+	if factors.Type().Is(tftypes.Object{}) || factors.Type().Is(tftypes.Map{}) {
+		if strategy != "NetworkTopologyStrategy" {
+			return nil, fmt.Errorf("replication factors per datacenter require NetworkTopologyStrategy")
+		}
+		var datacenters map[string]tftypes.Value
+		if err := factors.As(&datacenters); err != nil {
+			return nil, err
+		}
+		if len(datacenters) == 0 {
+			return nil, fmt.Errorf("at least one datacenter is required")
+		}
+		options := make(map[string]string, len(datacenters))
+		for datacenter, value := range datacenters {
+			factor, err := replicationFactor(value)
+			if err != nil {
+				return nil, fmt.Errorf("datacenter %s: %s", datacenter, err)
+			}
+			options[datacenter] = factor
+		}
+		return options, nil
+	}
+
+	factor, err := replicationFactor(factors)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"replication_factor": factor}, nil
+}
+
+// replicationFactor renders a replication factor given as a whole number or
+// as a string such as 3 or 3/1.
+func replicationFactor(value tftypes.Value) (string, error) {
+	if value.IsNull() {
+		return "", fmt.Errorf("replication factor must not be null")
+	}
+	switch {
+	case value.Type().Is(tftypes.Number):
+		var number big.Float
+		if err := value.As(&number); err != nil {
+			return "", err
+		}
+		if !number.IsInt() || number.Sign() < 0 {
+			return "", fmt.Errorf("replication factor must be a whole number, got %s", number.String())
+		}
+		return number.Text('f', 0), nil
+	case value.Type().Is(tftypes.String):
+		var factor string
+		if err := value.As(&factor); err != nil {
+			return "", err
+		}
+		if !replicationFactorRegex.MatchString(factor) {
+			return "", fmt.Errorf("invalid replication factor %s", factor)
+		}
+		return factor, nil
+	}
+	return "", fmt.Errorf("replication factor must be a number or a string, got %s", value.Type())
+}
+
+// renderReplicationMap renders the REPLICATION map literal with the class first.
+func renderReplicationMap(strategy string, options map[string]string) string {
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	rendered := "{'class': " + cql.Literal(strategy)
+	for _, key := range keys {
+		rendered += fmt.Sprintf(", %s: %s", cql.Literal(key), cql.Literal(options[key]))
+	}
+	return rendered + "}"
+}
@@ -0,0 +1,62 @@
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestFunctionReplication(t *testing.T) {
+	datacenters := tftypes.NewValue(
+		tftypes.Object{AttributeTypes: map[string]tftypes.Type{"eu-west": tftypes.Number, "us-east": tftypes.String}},
+		map[string]tftypes.Value{
+			"eu-west": tftypes.NewValue(tftypes.Number, 3),
+			"us-east": tftypes.NewValue(tftypes.String, "3/1"),
+		},
+	)
+	result, funcErr := callProviderFunction(t, "replication", tftypes.NewValue(tftypes.String, "NetworkTopologyStrategy"), datacenters)
+	if funcErr != nil {
+		t.Fatal(funcErr.Text)
+	}
+
+	var attributes map[string]tftypes.Value
+	if err := result.As(&attributes); err != nil {
+		t.Fatal(err)
+	}
+	var cql string
+	if err := attributes["cql"].As(&cql); err != nil {
+		t.Fatal(err)
+	}
+	expected := "{'class': 'NetworkTopologyStrategy', 'eu-west': '3', 'us-east': '3/1'}"
+	if cql != expected {
+		t.Fatalf("expected %q, got %q", expected, cql)
+	}
+	var options map[string]tftypes.Value
+	if err := attributes["options"].As(&options); err != nil {
+		t.Fatal(err)
+	}
+	if len(options) != 2 {
+		t.Fatalf("expected options for 2 datacenters, got %v", options)
+	}
+}
+
+func TestReplicationOptions(t *testing.T) {
+	options, err := replicationOptions("SimpleStrategy", tftypes.NewValue(tftypes.Number, 3))
+	if err != nil || len(options) != 1 || options["replication_factor"] != "3" {
+		t.Fatalf("unexpected options %v (%v)", options, err)
+	}
+
+	for _, test := range []struct {
+		strategy string
+		factors  tftypes.Value
+	}{
+		{"EverywhereStrategy", tftypes.NewValue(tftypes.Number, 3)},
+		{"SimpleStrategy", tftypes.NewValue(tftypes.Map{ElementType: tftypes.Number}, map[string]tftypes.Value{"dc1": tftypes.NewValue(tftypes.Number, 3)})},
+		{"SimpleStrategy", tftypes.NewValue(tftypes.Number, 1.5)},
+		{"NetworkTopologyStrategy", tftypes.NewValue(tftypes.String, "three")},
+	} {
+		if _, err := replicationOptions(test.strategy, test.factors); err == nil {
+			t.Fatalf("expected an error for %s with %s", test.strategy, test.factors)
+		}
+	}
+}
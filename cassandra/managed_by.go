@@ -0,0 +1,149 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+)
+
+// managedByTrackedResources are the resources whose objects have no comment
+// to carry the managed-by marker, so they are recorded in managed_by_table.
+var managedByTrackedResources = map[string]bool{
+	"cassandra_keyspace": true,
+	"cassandra_role":     true,
+	"cassandra_user":     true,
+	"cassandra_grant":    true,
+}
+
+// managedByMarkerPattern matches a marker appended by managedByComment,
+// whatever the workspace it names.
+var managedByMarkerPattern = regexp.MustCompile(`(^|; )managed-by: terraform \([^()]*\)$`)
+
+// managedByMarker returns the marker naming the workspace managedBy.
+func managedByMarker(managedBy string) string {
+	return fmt.Sprintf("managed-by: terraform (%s)", managedBy)
+}
+
+// managedByComment appends the marker of managedBy to comment, replacing the
+// one already there. Without managedBy, comment is returned as is.
+func managedByComment(comment string, managedBy string) string {
+	if managedBy == "" {
+		return comment
+	}
+	if comment = stripManagedByMarker(comment); comment == "" {
+		return managedByMarker(managedBy)
+	}
+	return comment + "; " + managedByMarker(managedBy)
+}
+
+// stripManagedByMarker removes the marker from a comment read from the
+// cluster, so it does not show up as drift.
+func stripManagedByMarker(comment string) string {
+	return managedByMarkerPattern.ReplaceAllString(comment, "")
+}
+
+// parseManagedByTable splits managed_by_table into keyspace and table.
+func parseManagedByTable(name string) (string, string, error) {
+	parts := strings.SplitN(name, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("unexpected format of managed_by_table (%s), expected keyspace.table", name)
+	}
+	return parts[0], parts[1], nil
+}
+
+func validateManagedByTable(i interface{}, k string) ([]string, []error) {
+	if _, _, err := parseManagedByTable(i.(string)); err != nil {
+		return nil, []error{err}
+	}
+	return nil, nil
+}
+
+// trackManagedObject records or forgets the object id of the resource name
+// in managed_by_table, creating the table the first time. The object has
+// been changed already, so failing to do so only warns.
+func (c *ProviderConfig) trackManagedObject(ctx context.Context, name string, id string, remove bool) diag.Diagnostics {
+	err := c.execManagedBy(ctx, name, id, remove)
+	if err != nil {
+		log.Printf("[WARN] Recording %s %s in %s failed: %s", name, id, c.ManagedByTable, err)
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("%s %s is not recorded in managed_by_table", name, id),
+			Detail:   err.Error(),
+		}}
+	}
+	return nil
+}
+
+func (c *ProviderConfig) execManagedBy(ctx context.Context, name string, id string, remove bool) error {
+	keyspace, table, err := parseManagedByTable(c.ManagedByTable)
+	if err != nil {
+		return err
+	}
+	qualifiedName := cql.QualifiedName(keyspace, table)
+
+	executor, err := c.newExecutor()
+	if err != nil {
+		return err
+	}
+	defer executor.Close()
+
+	c.managedByMu.Lock()
+	if !c.managedByTableCreated {
+		query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (resource text, id text, managed_by text, updated_at timestamp, PRIMARY KEY ((resource), id))`, qualifiedName)
+		if err := executor.ExecSchemaChange(ctx, query, time.Minute); err != nil {
+			c.managedByMu.Unlock()
+			return err
+		}
+		c.managedByTableCreated = true
+	}
+	c.managedByMu.Unlock()
+
+	if remove {
+		return executor.Exec(ctx, fmt.Sprintf(`DELETE FROM %s WHERE resource = ? AND id = ?`, qualifiedName), name, id)
+	}
+	return executor.Exec(ctx, fmt.Sprintf(`INSERT INTO %s (resource, id, managed_by, updated_at) VALUES (?, ?, ?, toTimestamp(now()))`, qualifiedName),
+		name, id, managedByMarker(c.ManagedBy))
+}
+
+// withManagedBy records the objects of the resource name in managed_by_table
+// when they are created and forgets them when they are deleted, if the
+// resource has no comment to stamp and managed_by and managed_by_table are
+// set.
+func withManagedBy(name string, r *schema.Resource) *schema.Resource {
+	if !managedByTrackedResources[name] {
+		return r
+	}
+
+	tracks := func(meta interface{}) (*ProviderConfig, bool) {
+		providerConfig, ok := meta.(*ProviderConfig)
+		return providerConfig, ok && providerConfig.ManagedBy != "" && providerConfig.ManagedByTable != ""
+	}
+	if createContext := r.CreateContext; createContext != nil {
+		r.CreateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			diags := createContext(ctx, d, meta)
+			if providerConfig, ok := tracks(meta); ok && !diags.HasError() && d.Id() != "" {
+				diags = append(diags, providerConfig.trackManagedObject(ctx, name, d.Id(), false)...)
+			}
+			return diags
+		}
+	}
+	if deleteContext := r.DeleteContext; deleteContext != nil {
+		r.DeleteContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			id := d.Id()
+			diags := deleteContext(ctx, d, meta)
+			if providerConfig, ok := tracks(meta); ok && !diags.HasError() {
+				diags = append(diags, providerConfig.trackManagedObject(ctx, name, id, true)...)
+			}
+			return diags
+		}
+	}
+	return r
+}
@@ -0,0 +1,99 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestManagedByComment(t *testing.T) {
+	for _, test := range []struct {
+		comment   string
+		managedBy string
+		expected  string
+	}{
+		{"", "", ""},
+		{"orders", "", "orders"},
+		{"", "prod", "managed-by: terraform (prod)"},
+		{"orders", "prod", "orders; managed-by: terraform (prod)"},
+		{"orders; managed-by: terraform (staging)", "prod", "orders; managed-by: terraform (prod)"},
+	} {
+		if comment := managedByComment(test.comment, test.managedBy); comment != test.expected {
+			t.Fatalf("expected %q for %q managed by %q, got %q", test.expected, test.comment, test.managedBy, comment)
+		}
+	}
+
+	for comment, expected := range map[string]string{
+		"managed-by: terraform (prod)":         "",
+		"orders; managed-by: terraform (prod)": "orders",
+		"orders managed-by: terraform (prod)":  "orders managed-by: terraform (prod)",
+		"managed-by: terraform (prod); orders": "managed-by: terraform (prod); orders",
+	} {
+		if stripped := stripManagedByMarker(comment); stripped != expected {
+			t.Fatalf("expected %q stripped to %q, got %q", comment, expected, stripped)
+		}
+	}
+}
+
+func TestParseManagedByTable(t *testing.T) {
+	if keyspace, table, err := parseManagedByTable("ops.terraform_objects"); err != nil || keyspace != "ops" || table != "terraform_objects" {
+		t.Fatalf("unexpected %s, %s, %v", keyspace, table, err)
+	}
+	for _, name := range []string{"terraform_objects", ".terraform_objects", "ops."} {
+		if _, _, err := parseManagedByTable(name); err == nil {
+			t.Fatalf("expected %q to be rejected", name)
+		}
+	}
+}
+
+func TestWithManagedBy(t *testing.T) {
+	executor := newMockCQLExecutor()
+	r := withManagedBy("cassandra_role", &schema.Resource{
+		Schema: map[string]*schema.Schema{"name": {Type: schema.TypeString, Required: true}},
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			d.SetId(d.Get("name").(string))
+			return nil
+		},
+		DeleteContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			d.SetId("")
+			return nil
+		},
+	})
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"name": "app"})
+
+	providerConfig := executor.providerConfig()
+	if diags := r.CreateContext(context.Background(), d, providerConfig); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(executor.executed) != 0 {
+		t.Fatalf("expected nothing to be recorded without managed_by, got %v", executor.executed)
+	}
+
+	providerConfig.ManagedBy = "prod"
+	providerConfig.ManagedByTable = "ops.terraform_objects"
+	if diags := r.CreateContext(context.Background(), d, providerConfig); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if diags := r.DeleteContext(context.Background(), d, providerConfig); diags.HasError() {
+		t.Fatal(diags)
+	}
+	expected := []string{
+		"CREATE TABLE IF NOT EXISTS ops.terraform_objects (resource text, id text, managed_by text, updated_at timestamp, PRIMARY KEY ((resource), id))",
+		"INSERT INTO ops.terraform_objects (resource, id, managed_by, updated_at) VALUES (?, ?, ?, toTimestamp(now())) [cassandra_role app managed-by: terraform (prod)]",
+		"DELETE FROM ops.terraform_objects WHERE resource = ? AND id = ? [cassandra_role app]",
+	}
+	if !reflect.DeepEqual(executor.executed, expected) {
+		t.Fatalf("expected %v, got %v", expected, executor.executed)
+	}
+
+	executor.errors["DELETE FROM ops.terraform_objects WHERE resource = ? AND id = ?"] = errors.New("unavailable")
+	d.SetId("app")
+	diags := r.DeleteContext(context.Background(), d, providerConfig)
+	if diags.HasError() || len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Fatalf("expected failing to forget the role to only warn, got %v", diags)
+	}
+}
@@ -0,0 +1,100 @@
+package cassandra
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+const defaultMetadataCacheTTL = 30 * time.Second
+
+// schemaChangeRegex matches the statements changing the schema, after which
+// cached metadata may be stale.
+var schemaChangeRegex = regexp.MustCompile(`(?i)^\s*(CREATE|ALTER|DROP)\s`)
+
+type cachedKeyspaceMetadata struct {
+	metadata *gocql.KeyspaceMetadata
+	expires  time.Time
+}
+
+// metadataCache shares the keyspace metadata read by the resources and data
+// sources of a provider, which each open their own session, so refreshing
+// hundreds of tables does not read system_schema hundreds of times. Any
+// schema change made by the provider invalidates it. A nil cache or a TTL
+// of zero disables it.
+type metadataCache struct {
+	ttl time.Duration
+
+	mu         sync.Mutex
+	keyspaces  map[string]cachedKeyspaceMetadata
+	generation uint64
+}
+
+func newMetadataCache(ttl time.Duration) *metadataCache {
+	return &metadataCache{ttl: ttl, keyspaces: map[string]cachedKeyspaceMetadata{}}
+}
+
+// keyspaceMetadata returns the cached metadata of keyspace, loading it with
+// load when missing or expired. Errors are not cached.
+func (c *metadataCache) keyspaceMetadata(keyspace string, load func(string) (*gocql.KeyspaceMetadata, error)) (*gocql.KeyspaceMetadata, error) {
+	if c == nil || c.ttl <= 0 {
+		return load(keyspace)
+	}
+
+	c.mu.Lock()
+	cached, ok := c.keyspaces[keyspace]
+	generation := c.generation
+	c.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.metadata, nil
+	}
+
+	metadata, err := load(keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// A schema change made while loading may not be part of the metadata.
+	if c.generation == generation {
+		c.keyspaces[keyspace] = cachedKeyspaceMetadata{metadata: metadata, expires: time.Now().Add(c.ttl)}
+	}
+	return metadata, nil
+}
+
+func (c *metadataCache) invalidate() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.keyspaces = map[string]cachedKeyspaceMetadata{}
+	c.generation++
+}
+
+// ObserveQuery invalidates the cache after every successful schema change.
+func (c *metadataCache) ObserveQuery(ctx context.Context, q gocql.ObservedQuery) {
+	if q.Err == nil && schemaChangeRegex.MatchString(q.Statement) {
+		c.invalidate()
+	}
+}
+
+// queryObservers passes the queries of a cluster to several observers.
+type queryObservers []gocql.QueryObserver
+
+func (o queryObservers) ObserveQuery(ctx context.Context, q gocql.ObservedQuery) {
+	for _, observer := range o {
+		observer.ObserveQuery(ctx, q)
+	}
+}
+
+// keyspaceMetadata returns the metadata of keyspace from the metadata cache of
+// the provider. Code waiting for a schema change to show up reads the
+// metadata of the executor instead.
+func (c *ProviderConfig) keyspaceMetadata(executor CQLExecutor, keyspace string) (*gocql.KeyspaceMetadata, error) {
+	return c.metadataCache.keyspaceMetadata(keyspace, executor.KeyspaceMetadata)
+}
@@ -0,0 +1,79 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+func TestMetadataCache(t *testing.T) {
+	cache := newMetadataCache(time.Minute)
+	loads := 0
+	load := func(keyspace string) (*gocql.KeyspaceMetadata, error) {
+		loads++
+		return &gocql.KeyspaceMetadata{Name: keyspace}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		metadata, err := cache.keyspaceMetadata("app", load)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if metadata.Name != "app" {
+			t.Fatalf("expected the metadata of app, got %s", metadata.Name)
+		}
+	}
+	if loads != 1 {
+		t.Fatalf("expected the metadata to be loaded once, got %d loads", loads)
+	}
+
+	cache.ObserveQuery(context.Background(), gocql.ObservedQuery{Statement: "SELECT * FROM app.events"})
+	cache.ObserveQuery(context.Background(), gocql.ObservedQuery{Statement: "DROP TABLE app.events", Err: errors.New("Unauthorized")})
+	cache.keyspaceMetadata("app", load)
+	if loads != 1 {
+		t.Fatalf("expected reads and failed schema changes to keep the cache, got %d loads", loads)
+	}
+
+	cache.ObserveQuery(context.Background(), gocql.ObservedQuery{Statement: "ALTER TABLE app.events ADD id int"})
+	cache.keyspaceMetadata("app", load)
+	if loads != 2 {
+		t.Fatalf("expected a schema change to invalidate the cache, got %d loads", loads)
+	}
+}
+
+func TestMetadataCacheSkipsStaleLoads(t *testing.T) {
+	cache := newMetadataCache(time.Minute)
+	loads := 0
+	load := func(keyspace string) (*gocql.KeyspaceMetadata, error) {
+		loads++
+		if loads == 1 {
+			cache.invalidate()
+		}
+		return &gocql.KeyspaceMetadata{Name: keyspace}, nil
+	}
+
+	cache.keyspaceMetadata("app", load)
+	cache.keyspaceMetadata("app", load)
+	if loads != 2 {
+		t.Fatalf("expected metadata loaded during a schema change not to be cached, got %d loads", loads)
+	}
+}
+
+func TestMetadataCacheDisabled(t *testing.T) {
+	loads := 0
+	load := func(keyspace string) (*gocql.KeyspaceMetadata, error) {
+		loads++
+		return &gocql.KeyspaceMetadata{Name: keyspace}, nil
+	}
+
+	var cache *metadataCache
+	cache.keyspaceMetadata("app", load)
+	newMetadataCache(0).keyspaceMetadata("app", load)
+	newMetadataCache(0).keyspaceMetadata("app", load)
+	if loads != 3 {
+		t.Fatalf("expected every read to load the metadata, got %d loads", loads)
+	}
+}
@@ -0,0 +1,222 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// metricsSummaryInterval is the least time between two summaries of the
+	// operations, so long applies log one every now and then.
+	metricsSummaryInterval = 30 * time.Second
+	statsdPrefix           = "terraform.cassandra"
+)
+
+type operationRecordKey struct{}
+
+// operationRecord counts the queries of a resource operation and how many
+// of them were retries.
+type operationRecord struct {
+	queries int64
+	retries int64
+}
+
+// operationRecordObserver counts the queries run with the context of a
+// resource operation.
+type operationRecordObserver struct{}
+
+func (operationRecordObserver) ObserveQuery(ctx context.Context, q gocql.ObservedQuery) {
+	record, ok := ctx.Value(operationRecordKey{}).(*operationRecord)
+	if !ok {
+		return
+	}
+	if q.Attempt > 0 {
+		atomic.AddInt64(&record.retries, 1)
+	} else {
+		atomic.AddInt64(&record.queries, 1)
+	}
+}
+
+type operationKey struct {
+	name      string
+	operation string
+}
+
+// operationStats aggregate the operations of one kind on a resource type.
+type operationStats struct {
+	Count         int
+	Errors        int
+	Queries       int64
+	Retries       int64
+	TotalDuration time.Duration
+	MaxDuration   time.Duration
+}
+
+// operationMetrics aggregate the count, duration, queries and retries of the
+// operations per resource type, log a summary of them every
+// metricsSummaryInterval and send every operation to StatsD if configured.
+type operationMetrics struct {
+	statsd net.Conn
+
+	mu          sync.Mutex
+	stats       map[operationKey]*operationStats
+	lastSummary time.Time
+}
+
+// newOperationMetrics returns metrics sending to the StatsD server at
+// statsdAddress, host:port, or to none if it is empty.
+func newOperationMetrics(statsdAddress string) (*operationMetrics, error) {
+	m := &operationMetrics{
+		stats:       map[operationKey]*operationStats{},
+		lastSummary: time.Now(),
+	}
+	if statsdAddress != "" {
+		conn, err := net.Dial("udp", statsdAddress)
+		if err != nil {
+			return nil, err
+		}
+		m.statsd = conn
+	}
+	return m, nil
+}
+
+// observe adds an operation to the statistics. It returns the summary of all
+// operations when one is due, or nil.
+func (m *operationMetrics) observe(key operationKey, duration time.Duration, record *operationRecord, failed bool) map[operationKey]operationStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.stats[key]
+	if !ok {
+		stats = &operationStats{}
+		m.stats[key] = stats
+	}
+	stats.Count++
+	if failed {
+		stats.Errors++
+	}
+	stats.Queries += atomic.LoadInt64(&record.queries)
+	stats.Retries += atomic.LoadInt64(&record.retries)
+	stats.TotalDuration += duration
+	if duration > stats.MaxDuration {
+		stats.MaxDuration = duration
+	}
+
+	if time.Since(m.lastSummary) < metricsSummaryInterval {
+		return nil
+	}
+	m.lastSummary = time.Now()
+	summary := make(map[operationKey]operationStats, len(m.stats))
+	for k, s := range m.stats {
+		summary[k] = *s
+	}
+	return summary
+}
+
+// statsdLines renders an operation as StatsD counters and timer.
+func statsdLines(key operationKey, duration time.Duration, record *operationRecord, failed bool) string {
+	prefix := fmt.Sprintf("%s.%s.%s.", statsdPrefix, key.name, key.operation)
+	lines := []string{
+		prefix + "count:1|c",
+		fmt.Sprintf("%sduration:%d|ms", prefix, duration.Milliseconds()),
+		fmt.Sprintf("%squeries:%d|c", prefix, atomic.LoadInt64(&record.queries)),
+		fmt.Sprintf("%sretries:%d|c", prefix, atomic.LoadInt64(&record.retries)),
+	}
+	if failed {
+		lines = append(lines, prefix+"errors:1|c")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// logSummary logs the statistics of every kind of operation, those that
+// took longest in total first.
+func logSummary(ctx context.Context, summary map[operationKey]operationStats) {
+	keys := make([]operationKey, 0, len(summary))
+	for key := range summary {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return summary[keys[i]].TotalDuration > summary[keys[j]].TotalDuration
+	})
+
+	for _, key := range keys {
+		stats := summary[key]
+		tflog.Info(ctx, "Operation summary", map[string]interface{}{
+			"resource":       key.name,
+			"operation":      key.operation,
+			"count":          stats.Count,
+			"errors":         stats.Errors,
+			"queries":        stats.Queries,
+			"retries":        stats.Retries,
+			"total_duration": stats.TotalDuration.String(),
+			"mean_duration":  (stats.TotalDuration / time.Duration(stats.Count)).String(),
+			"max_duration":   stats.MaxDuration.String(),
+		})
+	}
+}
+
+// withOperationMetrics measures the operations of the resource name.
+func withOperationMetrics(name string, r *schema.Resource) *schema.Resource {
+	r.CreateContext = addOperationMetrics(name, "create", r.CreateContext)
+	r.ReadContext = addOperationMetrics(name, "read", r.ReadContext)
+	r.UpdateContext = addOperationMetrics(name, "update", r.UpdateContext)
+	r.DeleteContext = addOperationMetrics(name, "delete", r.DeleteContext)
+	return r
+}
+
+func addOperationMetrics(name string, operation string, f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		providerConfig, _ := meta.(*ProviderConfig)
+		var diags diag.Diagnostics
+		measureOperation(ctx, providerConfig, name, operation, func(ctx context.Context) bool {
+			diags = f(ctx, d, meta)
+			return diags.HasError()
+		})
+		return diags
+	}
+}
+
+// measureOperation runs an operation on the resource name, which returns
+// whether it failed, and adds it to the metrics of providerConfig.
+func measureOperation(ctx context.Context, providerConfig *ProviderConfig, name string, operation string, f func(context.Context) bool) {
+	record := &operationRecord{}
+	start := time.Now()
+	failed := f(context.WithValue(ctx, operationRecordKey{}, record))
+	duration := time.Since(start)
+
+	if providerConfig == nil || providerConfig.metrics == nil {
+		return
+	}
+	key := operationKey{name: name, operation: operation}
+	tflog.Debug(ctx, "Operation finished", map[string]interface{}{
+		"resource":  name,
+		"operation": operation,
+		"duration":  duration.String(),
+		"queries":   atomic.LoadInt64(&record.queries),
+		"retries":   atomic.LoadInt64(&record.retries),
+		"failed":    failed,
+	})
+	if conn := providerConfig.metrics.statsd; conn != nil {
+		if _, err := conn.Write([]byte(statsdLines(key, duration, record, failed))); err != nil {
+			log.Printf("[DEBUG] Sending metrics to StatsD failed: %s", err)
+		}
+	}
+	if summary := providerConfig.metrics.observe(key, duration, record, failed); summary != nil {
+		logSummary(ctx, summary)
+	}
+}
@@ -0,0 +1,92 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestWithOperationMetrics(t *testing.T) {
+	r := withOperationMetrics("cassandra_keyspace", &schema.Resource{
+		Schema: map[string]*schema.Schema{},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			operationRecordObserver{}.ObserveQuery(ctx, gocql.ObservedQuery{Statement: "SELECT * FROM system_schema.keyspaces"})
+			operationRecordObserver{}.ObserveQuery(ctx, gocql.ObservedQuery{Statement: "SELECT * FROM system_schema.tables"})
+			operationRecordObserver{}.ObserveQuery(ctx, gocql.ObservedQuery{Statement: "SELECT * FROM system_schema.tables", Attempt: 1})
+			return diag.FromErr(errors.New("Operation timed out"))
+		},
+	})
+	if r.CreateContext != nil {
+		t.Fatal("expected missing operations to stay missing")
+	}
+
+	metrics, err := newOperationMetrics("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+	r.ReadContext(context.Background(), d, &ProviderConfig{metrics: metrics})
+	r.ReadContext(context.Background(), d, nil)
+
+	stats := metrics.stats[operationKey{name: "cassandra_keyspace", operation: "read"}]
+	if stats == nil || stats.Count != 1 || stats.Errors != 1 || stats.Queries != 2 || stats.Retries != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestOperationMetricsSummary(t *testing.T) {
+	metrics, err := newOperationMetrics("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := operationKey{name: "cassandra_table", operation: "create"}
+	if summary := metrics.observe(key, time.Second, &operationRecord{queries: 3}, false); summary != nil {
+		t.Fatalf("expected no summary before %s, got %v", metricsSummaryInterval, summary)
+	}
+
+	metrics.lastSummary = time.Now().Add(-metricsSummaryInterval)
+	summary := metrics.observe(key, 3*time.Second, &operationRecord{queries: 2, retries: 1}, true)
+	expected := operationStats{Count: 2, Errors: 1, Queries: 5, Retries: 1, TotalDuration: 4 * time.Second, MaxDuration: 3 * time.Second}
+	if summary[key] != expected {
+		t.Fatalf("expected %+v, got %+v", expected, summary[key])
+	}
+	if summary = metrics.observe(key, time.Second, &operationRecord{}, false); summary != nil {
+		t.Fatal("expected the next summary to wait for the interval")
+	}
+}
+
+func TestStatsdLines(t *testing.T) {
+	lines := statsdLines(operationKey{name: "cassandra_role", operation: "read"}, 1500*time.Millisecond, &operationRecord{queries: 2, retries: 1}, true)
+	expected := "terraform.cassandra.cassandra_role.read.count:1|c\n" +
+		"terraform.cassandra.cassandra_role.read.duration:1500|ms\n" +
+		"terraform.cassandra.cassandra_role.read.queries:2|c\n" +
+		"terraform.cassandra.cassandra_role.read.retries:1|c\n" +
+		"terraform.cassandra.cassandra_role.read.errors:1|c"
+	if lines != expected {
+		t.Fatalf("expected %q, got %q", expected, lines)
+	}
+}
+
+func TestMeasureOperation(t *testing.T) {
+	metrics, err := newOperationMetrics("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	measureOperation(context.Background(), &ProviderConfig{metrics: metrics}, "cassandra_query", "open", func(ctx context.Context) bool {
+		operationRecordObserver{}.ObserveQuery(ctx, gocql.ObservedQuery{Statement: "SELECT value FROM app.secrets"})
+		return false
+	})
+	measureOperation(context.Background(), nil, "cassandra_query", "open", func(ctx context.Context) bool {
+		return true
+	})
+
+	stats := metrics.stats[operationKey{name: "cassandra_query", operation: "open"}]
+	if stats == nil || stats.Count != 1 || stats.Errors != 0 || stats.Queries != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
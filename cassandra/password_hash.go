@@ -0,0 +1,154 @@
+package cassandra
+
+import (
+	"crypto/sha512"
+	"crypto/subtle"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	sha512CryptPrefix        = "$6$"
+	sha512CryptDefaultRounds = 5000
+	sha512CryptMinRounds     = 1000
+	sha512CryptMaxRounds     = 999999999
+	sha512CryptMaxSalt       = 16
+	cryptAlphabet            = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// passwordMatchesHash reports whether password hashes to the salted_hash of
+// a role: bcrypt ($2a$) in Cassandra and DataStax Enterprise, SHA-512 crypt
+// ($6$) in ScyllaDB. Hashes of other algorithms never match.
+func passwordMatchesHash(password string, hash string) bool {
+	switch {
+	case password == "" || hash == "":
+		return false
+	case bcryptHashRegex.MatchString(hash):
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+	case strings.HasPrefix(hash, sha512CryptPrefix):
+		computed, ok := sha512Crypt(password, hash)
+		return ok && subtle.ConstantTimeCompare([]byte(computed), []byte(hash)) == 1
+	}
+	return false
+}
+
+// sha512Crypt hashes password with the salt and rounds of setting, a
+// SHA-512 crypt hash or its $6$[rounds=N$]salt prefix, as described in
+// https://www.akkadia.org/drepper/SHA-crypt.txt.
+func sha512Crypt(password string, setting string) (string, bool) {
+	if !strings.HasPrefix(setting, sha512CryptPrefix) {
+		return "", false
+	}
+	rest := setting[len(sha512CryptPrefix):]
+
+	rounds := sha512CryptDefaultRounds
+	prefix := sha512CryptPrefix
+	if strings.HasPrefix(rest, "rounds=") {
+		end := strings.IndexByte(rest, '$')
+		if end < 0 {
+			return "", false
+		}
+		n, err := strconv.Atoi(rest[len("rounds="):end])
+		if err != nil {
+			return "", false
+		}
+		rounds = n
+		if rounds < sha512CryptMinRounds {
+			rounds = sha512CryptMinRounds
+		} else if rounds > sha512CryptMaxRounds {
+			rounds = sha512CryptMaxRounds
+		}
+		prefix += "rounds=" + strconv.Itoa(rounds) + "$"
+		rest = rest[end+1:]
+	}
+	salt := rest
+	if end := strings.IndexByte(salt, '$'); end >= 0 {
+		salt = salt[:end]
+	}
+	if len(salt) > sha512CryptMaxSalt {
+		salt = salt[:sha512CryptMaxSalt]
+	}
+
+	p := []byte(password)
+	s := []byte(salt)
+
+	h := sha512.New()
+	h.Write(p)
+	h.Write(s)
+	h.Write(p)
+	b := h.Sum(nil)
+
+	h.Reset()
+	h.Write(p)
+	h.Write(s)
+	h.Write(repeatDigest(b, len(p)))
+	for i := len(p); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			h.Write(b)
+		} else {
+			h.Write(p)
+		}
+	}
+	a := h.Sum(nil)
+
+	h.Reset()
+	for range p {
+		h.Write(p)
+	}
+	pSequence := repeatDigest(h.Sum(nil), len(p))
+
+	h.Reset()
+	for i := 0; i < 16+int(a[0]); i++ {
+		h.Write(s)
+	}
+	sSequence := repeatDigest(h.Sum(nil), len(s))
+
+	for i := 0; i < rounds; i++ {
+		h.Reset()
+		if i&1 != 0 {
+			h.Write(pSequence)
+		} else {
+			h.Write(a)
+		}
+		if i%3 != 0 {
+			h.Write(sSequence)
+		}
+		if i%7 != 0 {
+			h.Write(pSequence)
+		}
+		if i&1 != 0 {
+			h.Write(a)
+		} else {
+			h.Write(pSequence)
+		}
+		a = h.Sum(nil)
+	}
+
+	var encoded strings.Builder
+	for i := 0; i < 21; i++ {
+		// The bytes are spread over the groups as the reference
+		// implementation does: (0, 21, 42), (22, 43, 1), (44, 2, 23), ...
+		encodeCrypt24(&encoded, a[(i*22)%63], a[(i*22+21)%63], a[(i*22+42)%63], 4)
+	}
+	encodeCrypt24(&encoded, 0, 0, a[63], 2)
+	return prefix + salt + "$" + encoded.String(), true
+}
+
+// repeatDigest repeats digest up to length bytes.
+func repeatDigest(digest []byte, length int) []byte {
+	ret := make([]byte, 0, length)
+	for len(ret)+len(digest) < length {
+		ret = append(ret, digest...)
+	}
+	return append(ret, digest[:length-len(ret)]...)
+}
+
+func encodeCrypt24(w *strings.Builder, b2, b1, b0 byte, n int) {
+	v := uint(b2)<<16 | uint(b1)<<8 | uint(b0)
+	for ; n > 0; n-- {
+		w.WriteByte(cryptAlphabet[v&0x3f])
+		v >>= 6
+	}
+}
@@ -0,0 +1,46 @@
+package cassandra
+
+import "testing"
+
+func TestSHA512Crypt(t *testing.T) {
+	// Test vectors of https://www.akkadia.org/drepper/SHA-crypt.txt.
+	for _, test := range []struct {
+		password string
+		setting  string
+		expected string
+	}{
+		{"Hello world!", "$6$saltstring", "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1"},
+		{"Hello world!", "$6$rounds=10000$saltstringsaltstring", "$6$rounds=10000$saltstringsaltst$OW1/O6BYHV6BcXZu8QVeXbDWra3Oeqh0sbHbbMCVNSnCM/UrjmM0Dp8vOuZeHBy/YTBmSK6H9qs/y3RnOaw5v."},
+		{"we have a short salt string but not a short password", "$6$rounds=77777$short", "$6$rounds=77777$short$WuQyW2YR.hBNpjjRhpYD/ifIw05xdfeEyQoMxIXbkvr0gge1a1x3yRULJ5CCaUeOxFmtlcGZelFl5CxtgfiAc0"},
+		{"a very much longer text to encrypt.  This one even stretches over morethan one line.", "$6$rounds=1400$anotherlongsaltstring", "$6$rounds=1400$anotherlongsalts$POfYwTEok97VWcjxIiSOjiykti.o/pQs.wPvMxQ6Fm7I6IoYN3CmLs66x9t0oSwbtEW7o7UmJEiDwGqd8p4ur1"},
+		{"the minimum number is still observed", "$6$rounds=10$roundstoolow", "$6$rounds=1000$roundstoolow$kUMsbe306n21p9R.FRkW3IGn.S9NPN0x50YhH1xhLsPuWGsUSklZt58jaTfF4ZEQpyUNGc0dqbpBYYBaHHrsX."},
+	} {
+		if computed, ok := sha512Crypt(test.password, test.setting); !ok || computed != test.expected {
+			t.Fatalf("expected %s for %s, got %s", test.expected, test.setting, computed)
+		}
+	}
+}
+
+func TestPasswordMatchesHash(t *testing.T) {
+	bcryptHashed, err := bcryptHash("correct horse battery staple", "kVQ1mEf2xQ0eTjqS8gHnVe", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		password string
+		hash     string
+		expected bool
+	}{
+		{"correct horse battery staple", bcryptHashed, true},
+		{"correct horse battery", bcryptHashed, false},
+		{"Hello world!", "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1", true},
+		{"Hello world?", "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1", false},
+		{"Hello world!", "$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZF4kVQ.9", false},
+		{"", bcryptHashed, false},
+		{"secret", "", false},
+	} {
+		if matches := passwordMatchesHash(test.password, test.hash); matches != test.expected {
+			t.Fatalf("expected %q matching %s to be %t", test.password, test.hash, test.expected)
+		}
+	}
+}
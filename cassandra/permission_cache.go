@@ -0,0 +1,157 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+)
+
+// permissionChangeRegex matches the statements changing permissions. Creating
+// a keyspace, table or role grants permissions on it to its creator and
+// dropping it revokes all permissions on it.
+var permissionChangeRegex = regexp.MustCompile(`(?i)^\s*(GRANT|REVOKE|CREATE|DROP)\s`)
+
+type cachedRolePermissions struct {
+	// loaded is closed once resources and err are set.
+	loaded    chan struct{}
+	resources map[string]bool
+	err       error
+	expires   time.Time
+}
+
+// permissionCache shares the resources roles have permissions on between the
+// reads of the grants of a provider, so refreshing many grants of a role
+// reads its permissions once instead of once per grant. Concurrent reads of
+// the same role wait for a single load. Any permission change made by the
+// provider invalidates it. A nil cache or a TTL of zero disables it.
+type permissionCache struct {
+	ttl time.Duration
+
+	mu         sync.Mutex
+	roles      map[string]*cachedRolePermissions
+	generation uint64
+}
+
+func newPermissionCache(ttl time.Duration) *permissionCache {
+	return &permissionCache{ttl: ttl, roles: map[string]*cachedRolePermissions{}}
+}
+
+// hasPermissions tells whether role has permissions on resource, loading the
+// resources of the role with load when missing or expired. Errors are not
+// cached.
+func (c *permissionCache) hasPermissions(role string, resource string, load func(string) (map[string]bool, error)) (bool, error) {
+	if c == nil || c.ttl <= 0 {
+		resources, err := load(role)
+		return resources[resource], err
+	}
+
+	c.mu.Lock()
+	entry, ok := c.roles[role]
+	if ok {
+		select {
+		case <-entry.loaded:
+			ok = time.Now().Before(entry.expires)
+		default:
+		}
+	}
+	if ok {
+		c.mu.Unlock()
+		<-entry.loaded
+		return entry.resources[resource], entry.err
+	}
+
+	entry = &cachedRolePermissions{loaded: make(chan struct{})}
+	c.roles[role] = entry
+	generation := c.generation
+	c.mu.Unlock()
+
+	entry.resources, entry.err = load(role)
+	entry.expires = time.Now().Add(c.ttl)
+	close(entry.loaded)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Permissions changed while loading may not be part of the result.
+	if (entry.err != nil || c.generation != generation) && c.roles[role] == entry {
+		delete(c.roles, role)
+	}
+	return entry.resources[resource], entry.err
+}
+
+func (c *permissionCache) invalidate() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.roles = map[string]*cachedRolePermissions{}
+	c.generation++
+}
+
+// ObserveQuery invalidates the cache after every successful permission change.
+func (c *permissionCache) ObserveQuery(ctx context.Context, q gocql.ObservedQuery) {
+	if q.Err == nil && permissionChangeRegex.MatchString(q.Statement) {
+		c.invalidate()
+	}
+}
+
+// queryRolePermissionResources returns the resources role has permissions on,
+// as stored in role_permissions, in a single query of the partition of role.
+// Accounts that may not read role_permissions list the permissions instead.
+func queryRolePermissionResources(ctx context.Context, executor CQLExecutor, systemKeyspace string, role string) (map[string]bool, error) {
+	query := fmt.Sprintf(`SELECT resource FROM %s.role_permissions WHERE role = ?`, systemKeyspace)
+	rows, err := executor.Select(ctx, query, role)
+	if err != nil {
+		if classifyQueryError(err) != queryErrorUnauthorized {
+			return nil, err
+		}
+		log.Printf("Reading %s.role_permissions failed (%s), listing permissions instead", systemKeyspace, err)
+		return listRolePermissionResources(ctx, executor, role)
+	}
+	resources := map[string]bool{}
+	for _, row := range rows {
+		resource, _ := row["resource"].(string)
+		resources[resource] = true
+	}
+	return resources, nil
+}
+
+// listRolePermissionResources returns the data resources role has permissions
+// on with LIST PERMISSIONS, named as in role_permissions.
+func listRolePermissionResources(ctx context.Context, executor CQLExecutor, role string) (map[string]bool, error) {
+	rows, err := executor.Select(ctx, fmt.Sprintf(`LIST ALL PERMISSIONS OF %s NORECURSIVE`, cql.Literal(role)))
+	if err != nil {
+		return nil, err
+	}
+	resources := map[string]bool{}
+	for _, row := range rows {
+		listed, _ := row["resource"].(string)
+		if resource, ok := dataResourceOf(listed); ok {
+			resources[resource] = true
+		}
+	}
+	return resources, nil
+}
+
+// dataResourceOf converts a data resource as listed by LIST PERMISSIONS, e.g.
+// <table app.events>, to its name in role_permissions, e.g. data/app/events.
+func dataResourceOf(listed string) (string, bool) {
+	target := strings.TrimSuffix(strings.TrimPrefix(listed, "<"), ">")
+	switch {
+	case target == "all keyspaces":
+		return "data", true
+	case strings.HasPrefix(target, "keyspace "):
+		return "data/" + strings.TrimPrefix(target, "keyspace "), true
+	case strings.HasPrefix(target, "table "):
+		return "data/" + strings.Replace(strings.TrimPrefix(target, "table "), ".", "/", 1), true
+	}
+	return "", false
+}
@@ -0,0 +1,112 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+func TestPermissionCache(t *testing.T) {
+	cache := newPermissionCache(time.Minute)
+	loads := 0
+	load := func(role string) (map[string]bool, error) {
+		loads++
+		return map[string]bool{"data/app": true, "data/app/events": true}, nil
+	}
+
+	cases := map[string]bool{"data/app": true, "data/app/events": true, "roles/app": false}
+	for resource, expected := range cases {
+		actual, err := cache.hasPermissions("app", resource, load)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != expected {
+			t.Fatalf("expected %t for %s, got %t", expected, resource, actual)
+		}
+	}
+	if loads != 1 {
+		t.Fatalf("expected the permissions of a role to be loaded once, got %d loads", loads)
+	}
+
+	cache.ObserveQuery(context.Background(), gocql.ObservedQuery{Statement: "SELECT * FROM app.events"})
+	cache.hasPermissions("app", "data/app", load)
+	if loads != 1 {
+		t.Fatalf("expected reads to keep the cache, got %d loads", loads)
+	}
+
+	cache.ObserveQuery(context.Background(), gocql.ObservedQuery{Statement: `REVOKE SELECT ON KEYSPACE app FROM "app"`})
+	cache.hasPermissions("app", "data/app", load)
+	if loads != 2 {
+		t.Fatalf("expected a revoke to invalidate the cache, got %d loads", loads)
+	}
+}
+
+func TestPermissionCacheSharesConcurrentLoads(t *testing.T) {
+	cache := newPermissionCache(time.Minute)
+	release := make(chan struct{})
+	var mu sync.Mutex
+	loads := 0
+	load := func(role string) (map[string]bool, error) {
+		mu.Lock()
+		loads++
+		mu.Unlock()
+		<-release
+		return map[string]bool{"data/app": true}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ok, err := cache.hasPermissions("app", "data/app", load); err != nil || !ok {
+				t.Errorf("expected the permission to be found, got %t, %v", ok, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if loads != 1 {
+		t.Fatalf("expected concurrent reads to share one load, got %d loads", loads)
+	}
+}
+
+func TestPermissionCacheSkipsErrors(t *testing.T) {
+	cache := newPermissionCache(time.Minute)
+	loads := 0
+	load := func(role string) (map[string]bool, error) {
+		loads++
+		return nil, errors.New("Operation timed out")
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := cache.hasPermissions("app", "data/app", load); err == nil {
+			t.Fatal("expected the error of the load")
+		}
+	}
+	if loads != 2 {
+		t.Fatalf("expected errors not to be cached, got %d loads", loads)
+	}
+}
+
+func TestDataResourceOf(t *testing.T) {
+	cases := map[string]string{
+		"<all keyspaces>":    "data",
+		"<keyspace app>":     "data/app",
+		"<table app.events>": "data/app/events",
+	}
+	for listed, expected := range cases {
+		if actual, ok := dataResourceOf(listed); !ok || actual != expected {
+			t.Fatalf("expected %s for %s, got %s", expected, listed, actual)
+		}
+	}
+	if _, ok := dataResourceOf("<role app>"); ok {
+		t.Fatal("expected a role resource not to be converted")
+	}
+}
@@ -0,0 +1,95 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+)
+
+// protectedKeyspaceAttributes are the attributes holding the keyspace of the
+// resources whose Delete drops, revokes or gives up managing something in a
+// keyspace.
+var protectedKeyspaceAttributes = map[string]string{
+	"cassandra_keyspace":             "name",
+	"cassandra_keyspace_replication": "keyspace",
+	"cassandra_schema_migration":     "keyspace",
+	"cassandra_table":                "keyspace",
+	"cassandra_table_column":         "keyspace",
+	"cassandra_type":                 "keyspace",
+	"cassandra_function":             "keyspace",
+	"cassandra_aggregate":            "keyspace",
+	"cassandra_index":                "keyspace",
+	"cassandra_vector_index":         "keyspace",
+	"cassandra_materialized_view":    "keyspace",
+	"cassandra_trigger":              "keyspace",
+	"cassandra_row_level_access":     "keyspace",
+	"cassandra_grant":                identifierKeyspaceName,
+}
+
+// protectedKeyspaceStatements are the attributes holding the CQL the Delete
+// of a resource runs, which is refused when it names a protected keyspace.
+var protectedKeyspaceStatements = map[string]string{
+	"cassandra_cql_exec": "destroy_cql",
+}
+
+// protectedKeyspacePattern returns the pattern of protected_keyspaces
+// matching keyspace, if any.
+func (c *ProviderConfig) protectedKeyspacePattern(keyspace string) (string, bool) {
+	if keyspace == "" {
+		return "", false
+	}
+	for _, pattern := range c.ProtectedKeyspaces {
+		if matched, _ := path.Match(pattern, keyspace); matched {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+func validateKeyspacePattern(i interface{}, k string) ([]string, []error) {
+	if _, err := path.Match(i.(string), ""); err != nil {
+		return nil, []error{fmt.Errorf("%s: invalid pattern %q: %s", k, i.(string), err)}
+	}
+	return nil, nil
+}
+
+// withProtectedKeyspaces refuses to delete the resource name when it lives in
+// a keyspace matching protected_keyspaces, or its destroy CQL names one,
+// whatever its own settings.
+func withProtectedKeyspaces(name string, r *schema.Resource) *schema.Resource {
+	attribute, hasKeyspace := protectedKeyspaceAttributes[name]
+	statementAttribute, hasStatement := protectedKeyspaceStatements[name]
+	if !hasKeyspace && !hasStatement || r.DeleteContext == nil {
+		return r
+	}
+
+	deleteContext := r.DeleteContext
+	r.DeleteContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		providerConfig := meta.(*ProviderConfig)
+		keyspaces := []string{}
+		if hasKeyspace {
+			keyspace, _ := d.Get(attribute).(string)
+			keyspaces = append(keyspaces, keyspace)
+		}
+		if hasStatement {
+			defaultKeyspace := ""
+			if providerConfig.Cluster != nil {
+				defaultKeyspace = providerConfig.Cluster.Keyspace
+			}
+			statement, _ := d.Get(statementAttribute).(string)
+			keyspaces = append(keyspaces, cql.StatementKeyspaces(statement, defaultKeyspace)...)
+		}
+		for _, keyspace := range keyspaces {
+			if pattern, ok := providerConfig.protectedKeyspacePattern(keyspace); ok {
+				return diag.Errorf("refusing to delete %s %s: keyspace %s matches %q of protected_keyspaces - remove it from the provider configuration to allow deleting", name, d.Id(), keyspace, pattern)
+			}
+		}
+		return deleteContext(ctx, d, meta)
+	}
+	return r
+}
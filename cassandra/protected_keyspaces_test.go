@@ -0,0 +1,95 @@
+package cassandra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestProtectedKeyspaceAttributes(t *testing.T) {
+	resources := Provider().ResourcesMap
+	for name, attribute := range protectedKeyspaceAttributes {
+		r, ok := resources[name]
+		if !ok {
+			t.Fatalf("%s is not a resource of the provider", name)
+		}
+		if _, ok := r.Schema[attribute]; !ok {
+			t.Fatalf("%s has no attribute %s", name, attribute)
+		}
+	}
+}
+
+func TestProtectedKeyspaceStatements(t *testing.T) {
+	resources := Provider().ResourcesMap
+	for name, attribute := range protectedKeyspaceStatements {
+		if _, ok := resources[name].Schema[attribute]; !ok {
+			t.Fatalf("%s has no attribute %s", name, attribute)
+		}
+	}
+}
+
+func TestWithProtectedKeyspaces(t *testing.T) {
+	executor := newMockCQLExecutor()
+	r := withProtectedKeyspaces("cassandra_trigger", resourceCassandraTrigger())
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"keyspace": "prod_orders",
+		"table":    "events",
+		"name":     "audit",
+		"class":    "com.example.AuditTrigger",
+	})
+	d.SetId("prod_orders.events.audit")
+
+	providerConfig := executor.providerConfig()
+	providerConfig.ProtectedKeyspaces = []string{"billing", "prod_*"}
+	if diags := r.DeleteContext(context.Background(), d, providerConfig); !diags.HasError() {
+		t.Fatal("expected deleting from a protected keyspace to be refused")
+	}
+	if len(executor.executed) != 0 {
+		t.Fatalf("expected nothing to be executed, got %v", executor.executed)
+	}
+
+	providerConfig.ProtectedKeyspaces = []string{"billing"}
+	if diags := r.DeleteContext(context.Background(), d, providerConfig); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(executor.executed) != 1 {
+		t.Fatalf("expected the trigger to be dropped, got %v", executor.executed)
+	}
+}
+
+func TestWithProtectedKeyspaces_destroyCQL(t *testing.T) {
+	executor := newMockCQLExecutor()
+	r := withProtectedKeyspaces("cassandra_cql_exec", resourceCassandraCQLExec())
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"create_cql":  "CREATE TABLE billing.archive (id int PRIMARY KEY)",
+		"destroy_cql": "DROP TABLE billing.archive",
+	})
+	d.SetId("archive")
+
+	providerConfig := executor.providerConfig()
+	providerConfig.ProtectedKeyspaces = []string{"billing"}
+	if diags := r.DeleteContext(context.Background(), d, providerConfig); !diags.HasError() {
+		t.Fatal("expected destroy CQL dropping from a protected keyspace to be refused")
+	}
+	if len(executor.executed) != 0 {
+		t.Fatalf("expected nothing to be executed, got %v", executor.executed)
+	}
+
+	providerConfig.ProtectedKeyspaces = []string{"prod_*"}
+	if diags := r.DeleteContext(context.Background(), d, providerConfig); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(executor.executed) != 1 {
+		t.Fatalf("expected the destroy CQL to be executed, got %v", executor.executed)
+	}
+}
+
+func TestValidateKeyspacePattern(t *testing.T) {
+	if _, errs := validateKeyspacePattern("prod_*", "protected_keyspaces.0"); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if _, errs := validateKeyspacePattern("prod_[", "protected_keyspaces.0"); len(errs) == 0 {
+		t.Fatal("expected a malformed pattern to be rejected")
+	}
+}
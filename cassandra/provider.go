@@ -6,6 +6,8 @@ import (
 	"crypto/x509"
 	"fmt"
 	"log"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gocql/gocql"
@@ -15,7 +17,23 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+const (
+	modeCassandra    = "cassandra"
+	modeScylla       = "scylla"
+	modeAWSKeyspaces = "aws_keyspaces"
+	// modeAzureManagedInstance is Apache Cassandra run by Azure Managed
+	// Instance for Apache Cassandra. Resources treat it as modeCassandra.
+	modeAzureManagedInstance = "azure_managed_instance"
+
+	defaultSystemKeyspace = "system_auth"
+	// keyspacesSchemaKeyspace describes the schema of Amazon Keyspaces and is
+	// the system keyspace of the provider in aws_keyspaces mode.
+	keyspacesSchemaKeyspace = "system_schema_mcs"
+)
+
 var (
+	allowedModes = []string{modeCassandra, modeScylla, modeAWSKeyspaces, modeAzureManagedInstance}
+
 	allowedTLSProtocols = map[string]uint16{
 		"TLS1.0": tls.VersionTLS10,
 		"TLS1.1": tls.VersionTLS11,
@@ -40,18 +58,94 @@ var (
 type ProviderConfig struct {
 	Cluster            *gocql.ClusterConfig
 	SystemKeyspaceName string
+	Mode               string
+	// ProtectedKeyspaces are patterns of keyspaces nothing is dropped from or
+	// revoked on.
+	ProtectedKeyspaces []string
+	// ReadOnly refuses every create, update and delete.
+	ReadOnly bool
+	// ManagedBy names the workspace in the managed-by marker of the objects
+	// the provider creates, which are recorded in ManagedByTable if they have
+	// no comment.
+	ManagedBy      string
+	ManagedByTable string
+	// NewExecutor replaces the gocql sessions of the resources, e.g. with an
+	// in-memory executor in unit tests.
+	NewExecutor func() (CQLExecutor, error)
+
+	metadataCache   *metadataCache
+	permissionCache *permissionCache
+	metrics         *operationMetrics
+
+	sessionMu sync.Mutex
+	session   *gocql.Session
+
+	probeMu sync.Mutex
+	probe   *ClusterProbe
+
+	authMu           sync.Mutex
+	authKeyspaceName string
+
+	managedByMu           sync.Mutex
+	managedByTableCreated bool
+}
+
+// schemaKeyspace returns the keyspace describing the schema of the cluster.
+// Amazon Keyspaces describes it in system_schema_mcs, which has the same
+// tables as system_schema and more columns.
+func (c *ProviderConfig) schemaKeyspace() string {
+	if c.Mode == modeAWSKeyspaces {
+		return keyspacesSchemaKeyspace
+	}
+	return "system_schema"
 }
 
 // Provider returns a terraform.ResourceProvider
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		ResourcesMap: map[string]*schema.Resource{
-			"cassandra_keyspace": resourceCassandraKeyspace(),
-			"cassandra_role":     resourceCassandraRole(),
-			"cassandra_grant":    resourceCassandraGrant(),
-			"cassandra_table":    resourceCassandraTableSpace(),
+			"cassandra_keyspace":             resourceCassandraKeyspace(),
+			"cassandra_keyspace_replication": resourceCassandraKeyspaceReplication(),
+			"cassandra_materialized_view":    resourceCassandraMaterializedView(),
+			"cassandra_user":                 resourceCassandraUser(),
+			"cassandra_role":                 resourceCassandraRole(),
+			"cassandra_grant":                resourceCassandraGrant(),
+			"cassandra_table":                resourceCassandraTableSpace(),
+			"cassandra_table_column":         resourceCassandraTableColumn(),
+			"cassandra_table_options":        resourceCassandraTableOptions(),
+			"cassandra_index":                resourceCassandraIndex(),
+			"cassandra_type":                 resourceCassandraType(),
+			"cassandra_function":             resourceCassandraFunction(),
+			"cassandra_aggregate":            resourceCassandraAggregate(),
+			"cassandra_trigger":              resourceCassandraTrigger(),
+			"cassandra_cql_exec":             resourceCassandraCQLExec(),
+			"cassandra_schema_migration":     resourceCassandraSchemaMigration(),
+			"cassandra_row_level_access":     resourceCassandraRowLevelAccess(),
+			"cassandra_vector_index":         resourceCassandraVectorIndex(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"cassandra_keyspace":              dataSourceCassandraKeyspace(),
+			"cassandra_keyspaces":             dataSourceCassandraKeyspaces(),
+			"cassandra_table":                 dataSourceCassandraTable(),
+			"cassandra_tables":                dataSourceCassandraTables(),
+			"cassandra_query":                 dataSourceCassandraQuery(),
+			"cassandra_cluster_info":          dataSourceCassandraClusterInfo(),
+			"cassandra_nodes":                 dataSourceCassandraNodes(),
+			"cassandra_schema_agreement":      dataSourceCassandraSchemaAgreement(),
+			"cassandra_settings":              dataSourceCassandraSettings(),
+			"cassandra_clients":               dataSourceCassandraClients(),
+			"cassandra_role_members":          dataSourceCassandraRoleMembers(),
+			"cassandra_types":                 dataSourceCassandraTypes(),
+			"cassandra_functions":             dataSourceCassandraFunctions(),
+			"cassandra_indexes":               dataSourceCassandraIndexes(),
+			"cassandra_materialized_views":    dataSourceCassandraMaterializedViews(),
+			"cassandra_keyspace_ddl":          dataSourceCassandraKeyspaceDDL(),
+			"cassandra_token_ring":            dataSourceCassandraTokenRing(),
+			"cassandra_capabilities":          dataSourceCassandraCapabilities(),
+			"cassandra_table_size_estimates":  dataSourceCassandraTableSizeEstimates(),
+			"cassandra_effective_permissions": dataSourceCassandraEffectivePermissions(),
 		},
-		ConfigureContextFunc: configureProvider,
+		ConfigureProvider: configureProviderDeferred,
 		Schema: map[string]*schema.Schema{
 			"username": {
 				Type:        schema.TypeString,
@@ -143,7 +237,7 @@ func Provider() *schema.Provider {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     true,
-				Description: "Option to disable host verification with SSL. Setting this to false is equivalent to setting SSL_VALIDATE=false with cql",
+				Description: "Option to disable host verification with SSL, also in azure_managed_instance mode. Setting this to false is equivalent to setting SSL_VALIDATE=false with cql",
 			},
 			"insecure_skip_verify": {
 				Type:        schema.TypeBool,
@@ -182,8 +276,14 @@ func Provider() *schema.Provider {
 			"system_keyspace_name": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "system_auth",
-				Description: "System keyspace name for roles and grants",
+				Description: fmt.Sprintf("System keyspace name for roles and grants. Defaults to %s in %s mode, to %s from ScyllaDB 6.0 on in %s mode and to %s otherwise", keyspacesSchemaKeyspace, modeAWSKeyspaces, scyllaAuthKeyspace, modeScylla, defaultSystemKeyspace),
+			},
+			"mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      modeCassandra,
+				Description:  fmt.Sprintf("Kind of cluster the provider talks to - allowed values are %s", strings.Join(allowedModes, ", ")),
+				ValidateFunc: validation.StringInSlice(allowedModes, false),
 			},
 			"pw_encryption_algorithm": {
 				Type:         schema.TypeString,
@@ -192,8 +292,75 @@ func Provider() *schema.Provider {
 				Description:  "Password encryption algorithm. Allowed values: bcrypt, sha-512",
 				ValidateFunc: validation.StringInSlice([]string{"bcrypt", "sha-512"}, false),
 			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultMaxRetries,
+				Description:  "Number of times a query failing with a transient error, i.e. Unavailable, ReadTimeout, WriteTimeout or a client timeout, is retried. Other errors are never retried, and neither are statements other than reads, which a timed out attempt may have applied already",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"retry_min_backoff": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(defaultRetryMinBackoff / time.Millisecond),
+				Description:  "Backoff before the first retry of a query in milliseconds, doubling with every retry",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"retry_max_backoff": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(defaultRetryMaxBackoff / time.Millisecond),
+				Description:  "Maximum backoff between retries of a query in milliseconds",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"protected_keyspaces": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateKeyspacePattern,
+				},
+				Optional:    true,
+				Description: "Names or glob patterns, e.g. prod_*, of keyspaces the provider never drops anything from or revokes permissions on, whatever the settings of the resources. Destroying a resource in one of them fails",
+			},
+			"read_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Only read the cluster: refreshes, plans and data sources work, but creating, updating or deleting any resource fails. Use it to detect drift from environments that must never change the cluster",
+			},
+			"managed_by": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Name of the workspace managing the cluster, e.g. terraform.workspace. Tables created by the provider, and comments set by cassandra_table_options, are stamped with a managed-by: terraform (<managed_by>) marker, which refreshes ignore",
+			},
+			"managed_by_table": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Table, as keyspace.table, recording the keyspaces, roles, users and grants created with managed_by set, which have no comment to stamp. It is created if missing",
+				ValidateFunc: validateManagedByTable,
+			},
+			"metadata_cache_ttl": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(defaultMetadataCacheTTL / time.Second),
+				Description:  "Number of seconds the keyspace and table metadata and the permissions of roles read by refreshes are shared between resources. Schema and permission changes made by the provider clear them. Set to 0 to read them for every resource",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"statsd_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Address of a StatsD server, e.g. 127.0.0.1:8125, to send the count, duration, queries, retries and errors of every operation to. A summary of them per resource type is logged regardless",
+			},
 		},
 	}
+
+	for name, r := range provider.ResourcesMap {
+		withOperationMetrics(name, withQueryErrorHints(name, withReadOnly(name, withProtectedKeyspaces(name, withManagedBy(name, r)))))
+	}
+	for name, r := range provider.DataSourcesMap {
+		withOperationMetrics(name, withQueryErrorHints(name, r))
+	}
+	return provider
 }
 
 func configureProvider(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
@@ -230,6 +397,15 @@ func configureProvider(ctx context.Context, d *schema.ResourceData) (interface{}
 	}
 	cluster.ConnectTimeout = time.Millisecond * time.Duration(connectionTimeout)
 	cluster.Timeout = time.Minute * 1
+	cluster.RetryPolicy = newTransientErrorRetryPolicy(
+		d.Get("max_retries").(int),
+		time.Millisecond*time.Duration(d.Get("retry_min_backoff").(int)),
+		time.Millisecond*time.Duration(d.Get("retry_max_backoff").(int)),
+	)
+	cacheTTL := time.Second * time.Duration(d.Get("metadata_cache_ttl").(int))
+	metadataCache := newMetadataCache(cacheTTL)
+	permissionCache := newPermissionCache(cacheTTL)
+	cluster.QueryObserver = queryObservers{failedStatementObserver{}, operationRecordObserver{}, metadataCache, permissionCache}
 	cluster.CQLVersion = d.Get("cql_version").(string)
 
 	if v, ok := d.GetOk("keyspace"); ok && v.(string) != "" {
@@ -247,16 +423,31 @@ func configureProvider(ctx context.Context, d *schema.ResourceData) (interface{}
 		cluster.DisableInitialHostLookup = v.(bool)
 	}
 
+	mode := d.Get("mode").(string)
+	if mode == modeAzureManagedInstance {
+		// Managed instances only accept TLS connections. Their certificates
+		// are verified like any other, against root_ca and the system pool.
+		useSSL = true
+		mode = modeCassandra
+	}
+
 	if useSSL {
 		rootCA := d.Get("root_ca").(string)
 		minTLSVersion := d.Get("min_tls_version").(string)
 		insecureSkipVerify := d.Get("insecure_skip_verify").(bool)
 		tlsConfig := &tls.Config{
-			MinVersion: allowedTLSProtocols[minTLSVersion],
+			MinVersion:         allowedTLSProtocols[minTLSVersion],
 			InsecureSkipVerify: insecureSkipVerify,
 		}
 		if rootCA != "" {
 			caPool := x509.NewCertPool()
+			if d.Get("mode").(string) == modeAzureManagedInstance {
+				// Managed instances present chains of public CAs, which
+				// root_ca adds to rather than replaces.
+				if systemPool, err := x509.SystemCertPool(); err == nil {
+					caPool = systemPool
+				}
+			}
 			ok := caPool.AppendCertsFromPEM([]byte(rootCA))
 			if !ok {
 				diags = append(diags, diag.Diagnostic{
@@ -269,15 +460,38 @@ func configureProvider(ctx context.Context, d *schema.ResourceData) (interface{}
 			tlsConfig.RootCAs = caPool
 		}
 		cluster.SslOpts = &gocql.SslOptions{
-			Config: tlsConfig,
+			Config:                 tlsConfig,
 			EnableHostVerification: d.Get("enable_host_verification").(bool),
 		}
 	}
 
 	systemKeyspaceName := d.Get("system_keyspace_name").(string)
+	// In scylla mode, an empty name is resolved on first use by authKeyspace.
+	if systemKeyspaceName == "" && mode != modeScylla {
+		systemKeyspaceName = defaultSystemKeyspace
+		if mode == modeAWSKeyspaces {
+			systemKeyspaceName = keyspacesSchemaKeyspace
+		}
+	}
+	metrics, err := newOperationMetrics(d.Get("statsd_address").(string))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	protectedKeyspaces := []string{}
+	for _, v := range d.Get("protected_keyspaces").([]interface{}) {
+		protectedKeyspaces = append(protectedKeyspaces, v.(string))
+	}
 
 	return &ProviderConfig{
 		Cluster:            cluster,
 		SystemKeyspaceName: systemKeyspaceName,
+		Mode:               mode,
+		ProtectedKeyspaces: protectedKeyspaces,
+		ReadOnly:           d.Get("read_only").(bool),
+		ManagedBy:          d.Get("managed_by").(string),
+		ManagedByTable:     d.Get("managed_by_table").(string),
+		metadataCache:      metadataCache,
+		permissionCache:    permissionCache,
+		metrics:            metrics,
 	}, diags
 }
@@ -0,0 +1,170 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+)
+
+// providerEphemeralResource is an ephemeral resource (Terraform 1.10+),
+// served next to the resources and data sources of the SDK provider. Its
+// result is never stored in plan files or state.
+type providerEphemeralResource struct {
+	schema *tfprotov5.Schema
+	// validate checks a configuration, whose values may still be unknown.
+	validate func(config map[string]tftypes.Value) error
+	// open returns the computed attributes and the private data close needs.
+	open func(ctx context.Context, providerConfig *ProviderConfig, config map[string]tftypes.Value) (map[string]tftypes.Value, []byte, error)
+	// close releases what open acquired. It is nil if there is nothing to release.
+	close func(ctx context.Context, providerConfig *ProviderConfig, private []byte) error
+}
+
+func providerEphemeralResources() map[string]*providerEphemeralResource {
+	return map[string]*providerEphemeralResource{
+		"cassandra_temporary_role": ephemeralCassandraTemporaryRole(),
+		"cassandra_query":          ephemeralCassandraQuery(),
+	}
+}
+
+// ephemeralResourceError returns the diagnostics of an ephemeral resource
+// operation that failed with err. Like the diagnostics of resources, they are
+// redacted, as errors may quote the statement that failed.
+func ephemeralResourceError(summary string, err error) []*tfprotov5.Diagnostic {
+	return []*tfprotov5.Diagnostic{{
+		Severity: tfprotov5.DiagnosticSeverityError,
+		Summary:  cql.Redact(summary),
+		Detail:   cql.Redact(err.Error()),
+	}}
+}
+
+// ephemeralResourceConfig decodes the configuration of an ephemeral resource.
+func ephemeralResourceConfig(resource *providerEphemeralResource, config *tfprotov5.DynamicValue) (map[string]tftypes.Value, error) {
+	value, err := config.Unmarshal(resource.schema.ValueType())
+	if err != nil {
+		return nil, err
+	}
+	attributes := map[string]tftypes.Value{}
+	if err := value.As(&attributes); err != nil {
+		return nil, err
+	}
+	return attributes, nil
+}
+
+// ephemeralResource looks up an ephemeral resource by its type name.
+func (s *providerServer) ephemeralResource(typeName string) (*providerEphemeralResource, error) {
+	resource, ok := s.ephemeralResources[typeName]
+	if !ok {
+		return nil, fmt.Errorf("no ephemeral resource named %q was found in the provider", typeName)
+	}
+	return resource, nil
+}
+
+// providerConfig returns the configuration of the SDK provider, or nil if it
+// is not configured, e.g. because it was deferred.
+func (s *providerServer) providerConfig() *ProviderConfig {
+	providerConfig, _ := s.provider.Meta().(*ProviderConfig)
+	return providerConfig
+}
+
+func (s *providerServer) ValidateEphemeralResourceConfig(ctx context.Context, req *tfprotov5.ValidateEphemeralResourceConfigRequest) (*tfprotov5.ValidateEphemeralResourceConfigResponse, error) {
+	resp := &tfprotov5.ValidateEphemeralResourceConfigResponse{}
+	resource, err := s.ephemeralResource(req.TypeName)
+	if err != nil {
+		resp.Diagnostics = ephemeralResourceError("Ephemeral Resource Not Found", err)
+		return resp, nil
+	}
+	config, err := ephemeralResourceConfig(resource, req.Config)
+	if err != nil {
+		resp.Diagnostics = ephemeralResourceError("Invalid Configuration", err)
+		return resp, nil
+	}
+	if resource.validate != nil {
+		if err := resource.validate(config); err != nil {
+			resp.Diagnostics = ephemeralResourceError(fmt.Sprintf("Invalid %s configuration", req.TypeName), err)
+		}
+	}
+	return resp, nil
+}
+
+func (s *providerServer) OpenEphemeralResource(ctx context.Context, req *tfprotov5.OpenEphemeralResourceRequest) (*tfprotov5.OpenEphemeralResourceResponse, error) {
+	resp := &tfprotov5.OpenEphemeralResourceResponse{}
+	resource, err := s.ephemeralResource(req.TypeName)
+	if err != nil {
+		resp.Diagnostics = ephemeralResourceError("Ephemeral Resource Not Found", err)
+		return resp, nil
+	}
+
+	providerConfig := s.providerConfig()
+	if providerConfig == nil {
+		if req.ClientCapabilities != nil && req.ClientCapabilities.DeferralAllowed {
+			resp.Deferred = &tfprotov5.Deferred{Reason: tfprotov5.DeferredReasonProviderConfigUnknown}
+			return resp, nil
+		}
+		resp.Diagnostics = ephemeralResourceError("Provider Not Configured", fmt.Errorf("cannot open %s before the provider is configured", req.TypeName))
+		return resp, nil
+	}
+
+	config, err := ephemeralResourceConfig(resource, req.Config)
+	if err != nil {
+		resp.Diagnostics = ephemeralResourceError("Invalid Configuration", err)
+		return resp, nil
+	}
+	var computed map[string]tftypes.Value
+	var private []byte
+	measureOperation(ctx, providerConfig, req.TypeName, "open", func(ctx context.Context) bool {
+		computed, private, err = resource.open(ctx, providerConfig, config)
+		return err != nil
+	})
+	if err != nil {
+		resp.Diagnostics = ephemeralResourceError(fmt.Sprintf("Error opening %s", req.TypeName), err)
+		return resp, nil
+	}
+
+	for name, value := range computed {
+		config[name] = value
+	}
+	result, err := tfprotov5.NewDynamicValue(resource.schema.ValueType(), tftypes.NewValue(resource.schema.ValueType(), config))
+	if err != nil {
+		resp.Diagnostics = ephemeralResourceError(fmt.Sprintf("Error opening %s", req.TypeName), err)
+		return resp, nil
+	}
+	resp.Result = &result
+	resp.Private = private
+	return resp, nil
+}
+
+// RenewEphemeralResource does nothing: the ephemeral resources of the provider
+// stay valid until they are closed.
+func (s *providerServer) RenewEphemeralResource(ctx context.Context, req *tfprotov5.RenewEphemeralResourceRequest) (*tfprotov5.RenewEphemeralResourceResponse, error) {
+	return &tfprotov5.RenewEphemeralResourceResponse{Private: req.Private}, nil
+}
+
+func (s *providerServer) CloseEphemeralResource(ctx context.Context, req *tfprotov5.CloseEphemeralResourceRequest) (*tfprotov5.CloseEphemeralResourceResponse, error) {
+	resp := &tfprotov5.CloseEphemeralResourceResponse{}
+	resource, err := s.ephemeralResource(req.TypeName)
+	if err != nil {
+		resp.Diagnostics = ephemeralResourceError("Ephemeral Resource Not Found", err)
+		return resp, nil
+	}
+	if resource.close == nil {
+		return resp, nil
+	}
+
+	providerConfig := s.providerConfig()
+	if providerConfig == nil {
+		resp.Diagnostics = ephemeralResourceError("Provider Not Configured", fmt.Errorf("cannot close %s before the provider is configured", req.TypeName))
+		return resp, nil
+	}
+	measureOperation(ctx, providerConfig, req.TypeName, "close", func(ctx context.Context) bool {
+		err = resource.close(ctx, providerConfig, req.Private)
+		return err != nil
+	})
+	if err != nil {
+		resp.Diagnostics = ephemeralResourceError(fmt.Sprintf("Error closing %s", req.TypeName), err)
+	}
+	return resp, nil
+}
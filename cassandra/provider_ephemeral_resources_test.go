@@ -0,0 +1,75 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// ephemeralResourceRequestConfig renders the configuration of an ephemeral
+// resource, leaving the attributes missing from values null.
+func ephemeralResourceRequestConfig(t *testing.T, typeName string, values map[string]tftypes.Value) *tfprotov5.DynamicValue {
+	t.Helper()
+
+	valueType := providerEphemeralResources()[typeName].schema.ValueType()
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range valueType.(tftypes.Object).AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+		}
+	}
+	config, err := tfprotov5.NewDynamicValue(valueType, tftypes.NewValue(valueType, attributes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &config
+}
+
+func TestProviderServerEphemeralResourceSchemas(t *testing.T) {
+	resp, err := NewProviderServer(Provider()).GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name := range providerEphemeralResources() {
+		if _, ok := resp.EphemeralResourceSchemas[name]; !ok {
+			t.Fatalf("expected the schema of ephemeral resource %s to be served", name)
+		}
+	}
+}
+
+func TestOpenEphemeralResource_deferred(t *testing.T) {
+	server := NewProviderServer(Provider())
+	req := &tfprotov5.OpenEphemeralResourceRequest{
+		TypeName:           "cassandra_temporary_role",
+		Config:             ephemeralResourceRequestConfig(t, "cassandra_temporary_role", nil),
+		ClientCapabilities: &tfprotov5.OpenEphemeralResourceClientCapabilities{DeferralAllowed: true},
+	}
+
+	opened, err := server.OpenEphemeralResource(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opened.Deferred == nil || opened.Deferred.Reason != tfprotov5.DeferredReasonProviderConfigUnknown {
+		t.Fatalf("expected an unconfigured provider to defer the ephemeral resource, got %+v", opened)
+	}
+
+	req.ClientCapabilities = nil
+	if opened, err = server.OpenEphemeralResource(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if len(opened.Diagnostics) != 1 || opened.Diagnostics[0].Summary != "Provider Not Configured" {
+		t.Fatalf("expected an error without deferral, got %+v", opened)
+	}
+}
+
+func TestEphemeralResourceError(t *testing.T) {
+	diags := ephemeralResourceError("Error opening cassandra_temporary_role", errors.New(`line 1:0 no viable alternative at input 'CREATE' (CREATE ROLE 'tmp' WITH PASSWORD = 's3cr3t' AND LOGIN = true)`))
+	expected := `line 1:0 no viable alternative at input 'CREATE' (CREATE ROLE 'tmp' WITH PASSWORD = '***' AND LOGIN = true)`
+	if len(diags) != 1 || diags[0].Detail != expected {
+		t.Fatalf("expected the password to be redacted, got %v", diags)
+	}
+}
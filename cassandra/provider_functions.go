@@ -0,0 +1,154 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// providerFunction is a provider-defined function (Terraform 1.8+), served
+// next to the resources and data sources of the SDK provider.
+type providerFunction struct {
+	definition *tfprotov5.Function
+	call       func(arguments []tftypes.Value) (tftypes.Value, error)
+}
+
+func providerFunctions() map[string]*providerFunction {
+	return map[string]*providerFunction{
+		"quote_identifier": functionQuoteIdentifier(),
+		"escape_literal":   functionEscapeLiteral(),
+		"replication":      functionReplication(),
+		"bcrypt_hash":      functionBcryptHash(),
+		"cql_type":         functionCQLType(),
+		"murmur3_token":    functionMurmur3Token(),
+	}
+}
+
+// providerServer adds the provider-defined functions and the ephemeral
+// resources to the protocol server of the SDK provider, which supports
+// neither itself.
+type providerServer struct {
+	tfprotov5.ProviderServer
+	provider           *schema.Provider
+	functions          map[string]*providerFunction
+	ephemeralResources map[string]*providerEphemeralResource
+}
+
+// NewProviderServer returns the protocol server serving provider together
+// with the provider-defined functions and the ephemeral resources.
+func NewProviderServer(provider *schema.Provider) tfprotov5.ProviderServer {
+	redactLogOutput()
+	return &providerServer{
+		ProviderServer:     schema.NewGRPCProviderServer(provider),
+		provider:           provider,
+		functions:          providerFunctions(),
+		ephemeralResources: providerEphemeralResources(),
+	}
+}
+
+func (s *providerServer) functionDefinitions() map[string]*tfprotov5.Function {
+	definitions := make(map[string]*tfprotov5.Function, len(s.functions))
+	for name, function := range s.functions {
+		definitions[name] = function.definition
+	}
+	return definitions
+}
+
+func (s *providerServer) GetMetadata(ctx context.Context, req *tfprotov5.GetMetadataRequest) (*tfprotov5.GetMetadataResponse, error) {
+	resp, err := s.ProviderServer.GetMetadata(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	for name := range s.functions {
+		resp.Functions = append(resp.Functions, tfprotov5.FunctionMetadata{Name: name})
+	}
+	for name := range s.ephemeralResources {
+		resp.EphemeralResources = append(resp.EphemeralResources, tfprotov5.EphemeralResourceMetadata{TypeName: name})
+	}
+	return resp, nil
+}
+
+func (s *providerServer) GetProviderSchema(ctx context.Context, req *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+	resp, err := s.ProviderServer.GetProviderSchema(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	resp.Functions = s.functionDefinitions()
+	resp.EphemeralResourceSchemas = make(map[string]*tfprotov5.Schema, len(s.ephemeralResources))
+	for name, resource := range s.ephemeralResources {
+		resp.EphemeralResourceSchemas[name] = resource.schema
+	}
+	return resp, nil
+}
+
+func (s *providerServer) GetFunctions(ctx context.Context, req *tfprotov5.GetFunctionsRequest) (*tfprotov5.GetFunctionsResponse, error) {
+	return &tfprotov5.GetFunctionsResponse{Functions: s.functionDefinitions()}, nil
+}
+
+func (s *providerServer) CallFunction(ctx context.Context, req *tfprotov5.CallFunctionRequest) (*tfprotov5.CallFunctionResponse, error) {
+	function, ok := s.functions[req.Name]
+	if !ok {
+		return &tfprotov5.CallFunctionResponse{
+			Error: &tfprotov5.FunctionError{Text: fmt.Sprintf("Function Not Found: No function named %q was found in the provider.", req.Name)},
+		}, nil
+	}
+
+	parameters := function.definition.Parameters
+	variadic := function.definition.VariadicParameter
+	if len(req.Arguments) < len(parameters) || (variadic == nil && len(req.Arguments) > len(parameters)) {
+		return &tfprotov5.CallFunctionResponse{
+			Error: &tfprotov5.FunctionError{Text: fmt.Sprintf("%s expects %d arguments, got %d", req.Name, len(parameters), len(req.Arguments))},
+		}, nil
+	}
+	arguments := make([]tftypes.Value, 0, len(req.Arguments))
+	for i, argument := range req.Arguments {
+		// Terraform sends the values of the variadic parameter as individual
+		// arguments following the fixed ones.
+		parameter := variadic
+		if i < len(parameters) {
+			parameter = parameters[i]
+		}
+		value, err := argument.Unmarshal(parameter.Type)
+		if err != nil {
+			position := int64(i)
+			return &tfprotov5.CallFunctionResponse{
+				Error: &tfprotov5.FunctionError{Text: err.Error(), FunctionArgument: &position},
+			}, nil
+		}
+		arguments = append(arguments, value)
+	}
+
+	result, err := function.call(arguments)
+	if err != nil {
+		return &tfprotov5.CallFunctionResponse{Error: &tfprotov5.FunctionError{Text: err.Error()}}, nil
+	}
+	value, err := tfprotov5.NewDynamicValue(function.definition.Return.Type, result)
+	if err != nil {
+		return &tfprotov5.CallFunctionResponse{Error: &tfprotov5.FunctionError{Text: err.Error()}}, nil
+	}
+	return &tfprotov5.CallFunctionResponse{Result: &value}, nil
+}
+
+// stringFunction returns a provider-defined function mapping one string to another.
+func stringFunction(summary string, description string, parameter string, call func(string) string) *providerFunction {
+	return &providerFunction{
+		definition: &tfprotov5.Function{
+			Summary:     summary,
+			Description: description,
+			Parameters: []*tfprotov5.FunctionParameter{
+				{Name: parameter, Type: tftypes.String},
+			},
+			Return: &tfprotov5.FunctionReturn{Type: tftypes.String},
+		},
+		call: func(arguments []tftypes.Value) (tftypes.Value, error) {
+			var value string
+			if err := arguments[0].As(&value); err != nil {
+				return tftypes.Value{}, err
+			}
+			return tftypes.NewValue(tftypes.String, call(value)), nil
+		},
+	}
+}
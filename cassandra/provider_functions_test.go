@@ -0,0 +1,102 @@
+package cassandra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// callProviderFunction calls a provider-defined function through the protocol
+// server and returns its result.
+func callProviderFunction(t *testing.T, name string, arguments ...tftypes.Value) (tftypes.Value, *tfprotov5.FunctionError) {
+	t.Helper()
+
+	server := NewProviderServer(Provider())
+	functionServer, ok := server.(tfprotov5.FunctionServer)
+	if !ok {
+		t.Fatal("expected the provider server to implement FunctionServer")
+	}
+
+	definitions, err := functionServer.GetFunctions(context.Background(), &tfprotov5.GetFunctionsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	definition, ok := definitions.Functions[name]
+	if !ok {
+		resp, err := functionServer.CallFunction(context.Background(), &tfprotov5.CallFunctionRequest{Name: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tftypes.Value{}, resp.Error
+	}
+
+	req := &tfprotov5.CallFunctionRequest{Name: name}
+	for i, argument := range arguments {
+		parameter := definition.VariadicParameter
+		if i < len(definition.Parameters) {
+			parameter = definition.Parameters[i]
+		}
+		value, err := tfprotov5.NewDynamicValue(parameter.Type, argument)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Arguments = append(req.Arguments, &value)
+	}
+	resp, err := functionServer.CallFunction(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error != nil {
+		return tftypes.Value{}, resp.Error
+	}
+
+	result, err := resp.Result.Unmarshal(definition.Return.Type)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result, nil
+}
+
+func TestProviderServerFunctions(t *testing.T) {
+	resp, err := NewProviderServer(Provider()).GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name := range providerFunctions() {
+		if resp.Functions[name] == nil {
+			t.Fatalf("expected function %s in the provider schema", name)
+		}
+	}
+	if resp.ResourceSchemas["cassandra_keyspace"] == nil {
+		t.Fatal("expected the resources of the SDK provider in the provider schema")
+	}
+
+	if _, funcErr := callProviderFunction(t, "unknown"); funcErr == nil {
+		t.Fatal("expected an error calling an unknown function")
+	}
+}
+
+func TestFunctionQuoting(t *testing.T) {
+	for _, test := range []struct {
+		function string
+		argument string
+		expected string
+	}{
+		{"quote_identifier", `My"Table`, `"My""Table"`},
+		{"escape_literal", "it's", "'it''s'"},
+	} {
+		result, funcErr := callProviderFunction(t, test.function, tftypes.NewValue(tftypes.String, test.argument))
+		if funcErr != nil {
+			t.Fatal(funcErr.Text)
+		}
+		var value string
+		if err := result.As(&value); err != nil {
+			t.Fatal(err)
+		}
+		if value != test.expected {
+			t.Fatalf("expected %s(%q) to return %q, got %q", test.function, test.argument, test.expected, value)
+		}
+	}
+}
@@ -35,6 +35,18 @@ func TestProvider_impl(t *testing.T) {
 	var _ *schema.Provider = Provider()
 }
 
+func TestProvider_quoteIdentifiers(t *testing.T) {
+	provider := Provider()
+	for name, resource := range provider.ResourcesMap {
+		if attribute, ok := resource.Schema["quote_identifiers"]; ok && attribute.Default != true {
+			t.Errorf("expected quote_identifiers of %s to default to true, got %v", name, attribute.Default)
+		}
+	}
+	if _, ok := provider.ResourcesMap["cassandra_keyspace"].Schema["quote_identifiers"]; !ok {
+		t.Error("expected cassandra_keyspace to have quote_identifiers")
+	}
+}
+
 func TestProvider_configure1(t *testing.T) {
 	rc := terraform.NewResourceConfigRaw(map[string]interface{}{
 		"username": "cassanrda",
@@ -81,3 +93,28 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestProvider_configureSystemKeyspace(t *testing.T) {
+	cases := map[string][]string{
+		"":                       {defaultSystemKeyspace, "system_schema"},
+		modeAWSKeyspaces:         {keyspacesSchemaKeyspace, keyspacesSchemaKeyspace},
+		modeAzureManagedInstance: {defaultSystemKeyspace, "system_schema"},
+	}
+	for mode, expected := range cases {
+		raw := map[string]interface{}{"host": "asdf"}
+		if mode != "" {
+			raw["mode"] = mode
+		}
+		p := Provider()
+		if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(raw)); diags.HasError() {
+			t.Fatal(diags)
+		}
+		providerConfig := p.Meta().(*ProviderConfig)
+		if providerConfig.SystemKeyspaceName != expected[0] {
+			t.Fatalf("expected system keyspace %s in mode %q, got %s", expected[0], mode, providerConfig.SystemKeyspaceName)
+		}
+		if schemaKeyspace := providerConfig.schemaKeyspace(); schemaKeyspace != expected[1] {
+			t.Fatalf("expected schema keyspace %s in mode %q, got %s", expected[1], mode, schemaKeyspace)
+		}
+	}
+}
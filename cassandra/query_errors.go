@@ -0,0 +1,244 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+)
+
+const (
+	defaultMaxRetries      = 3
+	defaultRetryMinBackoff = 100 * time.Millisecond
+	defaultRetryMaxBackoff = 10 * time.Second
+)
+
+type queryErrorClass int
+
+const (
+	queryErrorOther queryErrorClass = iota
+	// queryErrorTransient errors go away once enough replicas respond again.
+	queryErrorTransient
+	queryErrorConnection
+	queryErrorTLS
+	queryErrorAuthentication
+	queryErrorSuperuser
+	queryErrorUnauthorized
+	queryErrorSyntax
+	queryErrorMissingKeyspace
+)
+
+// queryErrorPatterns classify errors whose type is lost, e.g. once turned
+// into diagnostics, or that gocql does not type, e.g. failed logins. The
+// first matching pattern wins.
+var queryErrorPatterns = []struct {
+	class   queryErrorClass
+	pattern *regexp.Regexp
+}{
+	{queryErrorTransient, regexp.MustCompile(`(?i)cannot achieve consistency level|operation timed out|timeout during|no response received from cassandra within timeout period`)},
+	{queryErrorTLS, regexp.MustCompile(`(?i)x509:|tls:|first record does not look like a tls handshake`)},
+	{queryErrorConnection, regexp.MustCompile(`(?i)no hosts available|no connections were made|no hosts provided|connection refused|no such host|unable to discover protocol version`)},
+	{queryErrorAuthentication, regexp.MustCompile(`(?i)and/or password are incorrect|authentication failed|failed to authenticate`)},
+	{queryErrorSuperuser, regexp.MustCompile(`(?i)only superusers (are allowed|can)`)},
+	{queryErrorUnauthorized, regexp.MustCompile(`(?i)permission on|you have to be logged in`)},
+	{queryErrorSyntax, regexp.MustCompile(`(?i)no viable alternative|mismatched input|extraneous input|mismatched character`)},
+	{queryErrorMissingKeyspace, regexp.MustCompile(`(?i)keyspace ('[^']*'|"[^"]*"|\S+ )?does not exist|non-existing keyspace`)},
+}
+
+// queryErrorHints tell users what to do about the errors of a class.
+var queryErrorHints = map[queryErrorClass]string{
+	queryErrorTransient:       "The cluster did not answer in time or not enough replicas were alive, even after retrying. Check the health of the nodes, or raise max_retries and retry_max_backoff of the provider.",
+	queryErrorConnection:      "The provider could not connect to the cluster. Check host, hosts and port, that the nodes are up and reachable, and that use_ssl matches the client encryption of the cluster.",
+	queryErrorTLS:             "The TLS handshake with the cluster failed. Check use_ssl, root_ca, min_tls_version and enable_host_verification.",
+	queryErrorAuthentication:  "The cluster rejected the username and password of the provider. Check the username and password arguments or the CASSANDRA_USERNAME and CASSANDRA_PASSWORD variables.",
+	queryErrorSuperuser:       "This statement requires a superuser. Connect as a role with super_user = true.",
+	queryErrorUnauthorized:    "The role of the provider lacks a permission for this statement. Grant it to the role, or connect as a role that has it.",
+	queryErrorSyntax:          "The cluster rejected the statement as invalid CQL. Check the names and values in the configuration, and that the server version supports the feature.",
+	queryErrorMissingKeyspace: "The keyspace does not exist. Create it first, e.g. with a cassandra_keyspace resource referenced by this one so it is created before.",
+}
+
+func classifyQueryError(err error) queryErrorClass {
+	switch err.(type) {
+	case *gocql.RequestErrUnavailable, *gocql.RequestErrReadTimeout, *gocql.RequestErrWriteTimeout:
+		return queryErrorTransient
+	}
+	switch {
+	case errors.Is(err, gocql.ErrTimeoutNoResponse):
+		return queryErrorTransient
+	case errors.Is(err, gocql.ErrNoConnections), errors.Is(err, gocql.ErrNoConnectionsStarted), errors.Is(err, gocql.ErrNoHosts):
+		return queryErrorConnection
+	case errors.Is(err, gocql.ErrKeyspaceDoesNotExist):
+		return queryErrorMissingKeyspace
+	}
+	if requestErr, ok := err.(gocql.RequestError); ok {
+		switch requestErr.Code() {
+		case gocql.ErrCodeCredentials:
+			return queryErrorAuthentication
+		case gocql.ErrCodeUnauthorized:
+			if strings.Contains(strings.ToLower(requestErr.Message()), "superuser") {
+				return queryErrorSuperuser
+			}
+			return queryErrorUnauthorized
+		case gocql.ErrCodeSyntax:
+			return queryErrorSyntax
+		}
+	}
+
+	for _, p := range queryErrorPatterns {
+		if p.pattern.MatchString(err.Error()) {
+			return p.class
+		}
+	}
+	return queryErrorOther
+}
+
+// transientErrorRetryPolicy retries the idempotent queries failing with
+// transient errors on the next host with exponential backoff, and fails on
+// any other error.
+type transientErrorRetryPolicy struct {
+	gocql.ExponentialBackoffRetryPolicy
+}
+
+func newTransientErrorRetryPolicy(maxRetries int, minBackoff time.Duration, maxBackoff time.Duration) *transientErrorRetryPolicy {
+	return &transientErrorRetryPolicy{
+		gocql.ExponentialBackoffRetryPolicy{NumRetries: maxRetries, Min: minBackoff, Max: maxBackoff},
+	}
+}
+
+// Attempt is asked before GetRetryType and backs off before returning, so it
+// classifies the error failedStatementObserver recorded for the query first
+// and returns other errors right away. A statement that timed out may have
+// been applied, so statements that are not idempotent are never retried.
+func (p *transientErrorRetryPolicy) Attempt(q gocql.RetryableQuery) bool {
+	if q.Attempts() > p.NumRetries || !isIdempotentQuery(q) {
+		return false
+	}
+	if err := lastQueryError(q); err != nil && classifyQueryError(err) != queryErrorTransient {
+		return false
+	}
+	return p.ExponentialBackoffRetryPolicy.Attempt(q)
+}
+
+func (p *transientErrorRetryPolicy) GetRetryType(err error) gocql.RetryType {
+	if classifyQueryError(err) == queryErrorTransient {
+		return gocql.RetryNextHost
+	}
+	return gocql.Rethrow
+}
+
+// isIdempotentQuery reports whether running q again cannot apply it twice:
+// reads, and queries marked idempotent.
+func isIdempotentQuery(q gocql.RetryableQuery) bool {
+	if query, ok := q.(interface{ IsIdempotent() bool }); ok && query.IsIdempotent() {
+		return true
+	}
+	query, ok := q.(interface{ Statement() string })
+	if !ok {
+		return false
+	}
+	statement := strings.ToUpper(strings.TrimSpace(query.Statement()))
+	return strings.HasPrefix(statement, "SELECT ") || strings.HasPrefix(statement, "LIST ")
+}
+
+// lastQueryError returns the error the last attempt of q failed with, if it
+// ran in the context of a resource operation.
+func lastQueryError(q gocql.RetryableQuery) error {
+	recorder, ok := q.Context().Value(statementRecorderKey{}).(*statementRecorder)
+	if !ok {
+		return nil
+	}
+	query, ok := q.(interface{ Statement() string })
+	if !ok {
+		return nil
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if recorder.statement != query.Statement() {
+		return nil
+	}
+	return recorder.err
+}
+
+type statementRecorderKey struct{}
+
+// statementRecorder remembers the statement that failed last in the context
+// of a resource operation, and its error.
+type statementRecorder struct {
+	mu        sync.Mutex
+	statement string
+	err       error
+}
+
+// failedStatementObserver records the failing statements of the queries run
+// with the context of a resource operation.
+type failedStatementObserver struct{}
+
+func (failedStatementObserver) ObserveQuery(ctx context.Context, q gocql.ObservedQuery) {
+	recorder, ok := ctx.Value(statementRecorderKey{}).(*statementRecorder)
+	if !ok {
+		return
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if q.Err != nil {
+		recorder.statement = q.Statement
+		recorder.err = q.Err
+	} else if recorder.statement == q.Statement {
+		recorder.statement = ""
+		recorder.err = nil
+	}
+}
+
+// withQueryErrorHints adds what to do about them, the operation and the
+// failing statement to the errors of the operations of the resource name,
+// and masks the secrets of every diagnostic they return.
+func withQueryErrorHints(name string, r *schema.Resource) *schema.Resource {
+	r.CreateContext = addQueryErrorHints(name, "create", r.CreateContext)
+	r.ReadContext = addQueryErrorHints(name, "read", r.ReadContext)
+	r.UpdateContext = addQueryErrorHints(name, "update", r.UpdateContext)
+	r.DeleteContext = addQueryErrorHints(name, "delete", r.DeleteContext)
+	return r
+}
+
+func addQueryErrorHints(name string, operation string, f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		recorder := &statementRecorder{}
+		diags := f(context.WithValue(ctx, statementRecorderKey{}, recorder), d, meta)
+		for i := range diags {
+			diags[i].Summary = cql.Redact(diags[i].Summary)
+			diags[i].Detail = cql.Redact(diags[i].Detail)
+			if diags[i].Severity != diag.Error || diags[i].Detail != "" {
+				continue
+			}
+
+			details := []string{}
+			if hint := queryErrorHints[classifyQueryError(errors.New(diags[i].Summary))]; hint != "" {
+				details = append(details, hint)
+			}
+			target := name
+			if id := d.Id(); id != "" {
+				target = fmt.Sprintf("%s %q", name, id)
+			}
+			details = append(details, fmt.Sprintf("Failed to %s %s.", operation, target))
+			recorder.mu.Lock()
+			if recorder.statement != "" {
+				details = append(details, "Statement: "+cql.Redact(recorder.statement))
+			}
+			recorder.mu.Unlock()
+			diags[i].Detail = strings.Join(details, "\n\n")
+		}
+		return diags
+	}
+}
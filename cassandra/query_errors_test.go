@@ -0,0 +1,159 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+type testRequestError struct {
+	code    int
+	message string
+}
+
+func (e testRequestError) Code() int       { return e.code }
+func (e testRequestError) Message() string { return e.message }
+func (e testRequestError) Error() string   { return e.message }
+
+func TestClassifyQueryError(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected queryErrorClass
+	}{
+		{&gocql.RequestErrUnavailable{}, queryErrorTransient},
+		{&gocql.RequestErrReadTimeout{}, queryErrorTransient},
+		{&gocql.RequestErrWriteTimeout{}, queryErrorTransient},
+		{gocql.ErrTimeoutNoResponse, queryErrorTransient},
+		{fmt.Errorf("reading roles: %w", gocql.ErrTimeoutNoResponse), queryErrorTransient},
+		{testRequestError{gocql.ErrCodeCredentials, "Bad credentials"}, queryErrorAuthentication},
+		{testRequestError{gocql.ErrCodeUnauthorized, "Unauthorized"}, queryErrorUnauthorized},
+		{testRequestError{gocql.ErrCodeSyntax, "line 1:0"}, queryErrorSyntax},
+		{testRequestError{gocql.ErrCodeInvalid, "Undefined column name id"}, queryErrorOther},
+		{errors.New("Cannot achieve consistency level QUORUM"), queryErrorTransient},
+		{errors.New("Provided username app and/or password are incorrect"), queryErrorAuthentication},
+		{errors.New("User app has no CREATE permission on <all keyspaces> or any of its parents"), queryErrorUnauthorized},
+		{errors.New("line 1:7 no viable alternative at input 'KEYSPAC'"), queryErrorSyntax},
+		{errors.New("Keyspace app does not exist"), queryErrorMissingKeyspace},
+		{errors.New("Keyspace 'app' does not exist"), queryErrorMissingKeyspace},
+		{gocql.ErrKeyspaceDoesNotExist, queryErrorMissingKeyspace},
+		{gocql.ErrNoConnectionsStarted, queryErrorConnection},
+		{errors.New("gocql: unable to create session: unable to discover protocol version: dial tcp 10.0.0.1:9042: connect: connection refused"), queryErrorConnection},
+		{errors.New("gocql: unable to create session: control: unable to connect to initial hosts: tls: first record does not look like a TLS handshake"), queryErrorTLS},
+		{errors.New("x509: certificate signed by unknown authority"), queryErrorTLS},
+		{testRequestError{gocql.ErrCodeUnauthorized, "Only superusers can create a role with superuser status"}, queryErrorSuperuser},
+		{errors.New("Only superusers are allowed to perform CREATE (NON-)SUPERUSER ROLE queries"), queryErrorSuperuser},
+		{errors.New("Table app.events does not exist"), queryErrorOther},
+	}
+	for _, c := range cases {
+		if actual := classifyQueryError(c.err); actual != c.expected {
+			t.Fatalf("expected %q to be classified as %d, got %d", c.err, c.expected, actual)
+		}
+	}
+}
+
+func TestTransientErrorRetryPolicy(t *testing.T) {
+	policy := newTransientErrorRetryPolicy(3, defaultRetryMinBackoff, defaultRetryMaxBackoff)
+	if retryType := policy.GetRetryType(&gocql.RequestErrWriteTimeout{}); retryType != gocql.RetryNextHost {
+		t.Fatalf("expected a write timeout to be retried, got %d", retryType)
+	}
+	if retryType := policy.GetRetryType(testRequestError{gocql.ErrCodeSyntax, "line 1:0"}); retryType != gocql.Rethrow {
+		t.Fatalf("expected a syntax error to be returned, got %d", retryType)
+	}
+}
+
+type testRetryableQuery struct {
+	ctx        context.Context
+	statement  string
+	attempts   int
+	idempotent bool
+}
+
+func (q *testRetryableQuery) Attempts() int                      { return q.attempts }
+func (q *testRetryableQuery) SetConsistency(c gocql.Consistency) {}
+func (q *testRetryableQuery) GetConsistency() gocql.Consistency  { return gocql.One }
+func (q *testRetryableQuery) Context() context.Context           { return q.ctx }
+func (q *testRetryableQuery) Statement() string                  { return q.statement }
+func (q *testRetryableQuery) IsIdempotent() bool                 { return q.idempotent }
+
+func TestTransientErrorRetryPolicyAttempt(t *testing.T) {
+	// Backing off for an hour would time the test out, so only the
+	// retried query may get to it.
+	policy := newTransientErrorRetryPolicy(3, time.Hour, time.Hour)
+	failed := func(statement string, err error) *testRetryableQuery {
+		recorder := &statementRecorder{}
+		ctx := context.WithValue(context.Background(), statementRecorderKey{}, recorder)
+		failedStatementObserver{}.ObserveQuery(ctx, gocql.ObservedQuery{Statement: statement, Err: err})
+		return &testRetryableQuery{ctx: ctx, statement: statement, attempts: 1}
+	}
+
+	if policy.Attempt(failed(`SELECT id FROM app.settings`, testRequestError{gocql.ErrCodeSyntax, "line 1:0"})) {
+		t.Fatal("expected a syntax error not to be retried")
+	}
+	if policy.Attempt(failed(`ALTER TABLE app.settings ADD note text`, &gocql.RequestErrWriteTimeout{})) {
+		t.Fatal("expected a statement that is not idempotent not to be retried")
+	}
+	exhausted := failed(`SELECT id FROM app.settings`, &gocql.RequestErrReadTimeout{})
+	exhausted.attempts = 4
+	if policy.Attempt(exhausted) {
+		t.Fatal("expected a query to be retried at most max_retries times")
+	}
+
+	policy = newTransientErrorRetryPolicy(3, time.Millisecond, time.Millisecond)
+	if !policy.Attempt(failed(`SELECT id FROM app.settings`, &gocql.RequestErrReadTimeout{})) {
+		t.Fatal("expected a read failing with a transient error to be retried")
+	}
+	marked := failed(`UPDATE app.settings SET note = 'x' WHERE id = 1`, &gocql.RequestErrWriteTimeout{})
+	marked.idempotent = true
+	if !policy.Attempt(marked) {
+		t.Fatal("expected a query marked idempotent to be retried")
+	}
+}
+
+func TestWithQueryErrorHints(t *testing.T) {
+	r := withQueryErrorHints("cassandra_keyspace", &schema.Resource{
+		Schema: map[string]*schema.Schema{},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			failedStatementObserver{}.ObserveQuery(ctx, gocql.ObservedQuery{
+				Statement: "SELECT * FROM system_schema.keyspaces",
+			})
+			failedStatementObserver{}.ObserveQuery(ctx, gocql.ObservedQuery{
+				Statement: "ALTER KEYSPACE app WITH DURABLE_WRITES = true",
+				Err:       errors.New("User app has no ALTER permission on <keyspace app> or any of its parents"),
+			})
+			diags := diag.FromErr(errors.New("User app has no ALTER permission on <keyspace app> or any of its parents"))
+			diags = append(diags, diag.FromErr(errors.New("Undefined column name id"))...)
+			return append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Role app was created",
+				Detail:   "CREATE ROLE 'app' WITH PASSWORD = 'secret'",
+			})
+		},
+	})
+	if r.CreateContext != nil {
+		t.Fatal("expected missing operations to stay missing")
+	}
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+	d.SetId("app")
+	diags := r.ReadContext(context.Background(), d, nil)
+	expected := queryErrorHints[queryErrorUnauthorized] + "\n\n" +
+		`Failed to read cassandra_keyspace "app".` + "\n\n" +
+		"Statement: ALTER KEYSPACE app WITH DURABLE_WRITES = true"
+	if diags[0].Detail != expected {
+		t.Fatalf("expected %q, got %q", expected, diags[0].Detail)
+	}
+	expected = `Failed to read cassandra_keyspace "app".` + "\n\n" +
+		"Statement: ALTER KEYSPACE app WITH DURABLE_WRITES = true"
+	if diags[1].Detail != expected {
+		t.Fatalf("expected no hint for an unclassified error, got %q", diags[1].Detail)
+	}
+	if expected = "CREATE ROLE 'app' WITH PASSWORD = '***'"; diags[2].Detail != expected {
+		t.Fatalf("expected the password to be masked, got %q", diags[2].Detail)
+	}
+}
@@ -0,0 +1,37 @@
+package cassandra
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// withReadOnly refuses to create, update or delete the resource name when
+// read_only is set, so plans can detect drift from where nothing may change.
+func withReadOnly(name string, r *schema.Resource) *schema.Resource {
+	r.CreateContext = addReadOnly(name, "create", r.CreateContext)
+	r.UpdateContext = addReadOnly(name, "update", r.UpdateContext)
+	r.DeleteContext = addReadOnly(name, "delete", r.DeleteContext)
+	return r
+}
+
+func addReadOnly(name string, operation string, f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		if providerConfig, ok := meta.(*ProviderConfig); ok && providerConfig.ReadOnly {
+			target := name
+			if id := d.Id(); id != "" {
+				target = name + " " + id
+			}
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  "The provider is read-only",
+				Detail:   "Refusing to " + operation + " " + target + " because read_only is set in the provider configuration. Unset it to apply changes.",
+			}}
+		}
+		return f(ctx, d, meta)
+	}
+}
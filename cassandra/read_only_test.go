@@ -0,0 +1,41 @@
+package cassandra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestWithReadOnly(t *testing.T) {
+	executor := newMockCQLExecutor()
+	r := withReadOnly("cassandra_trigger", resourceCassandraTrigger())
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"keyspace": "orders",
+		"table":    "events",
+		"name":     "audit",
+		"class":    "com.example.AuditTrigger",
+	})
+
+	providerConfig := executor.providerConfig()
+	providerConfig.ReadOnly = true
+	diags := r.CreateContext(context.Background(), d, providerConfig)
+	if !diags.HasError() || diags[0].Detail != "Refusing to create cassandra_trigger because read_only is set in the provider configuration. Unset it to apply changes." {
+		t.Fatalf("expected creating to be refused, got %v", diags)
+	}
+	d.SetId("orders.events.audit")
+	if diags := r.DeleteContext(context.Background(), d, providerConfig); !diags.HasError() {
+		t.Fatal("expected deleting to be refused")
+	}
+	if len(executor.executed) != 0 {
+		t.Fatalf("expected nothing to be executed, got %v", executor.executed)
+	}
+
+	providerConfig.ReadOnly = false
+	if diags := r.DeleteContext(context.Background(), d, providerConfig); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(executor.executed) != 1 {
+		t.Fatalf("expected the trigger to be dropped, got %v", executor.executed)
+	}
+}
@@ -0,0 +1,33 @@
+package cassandra
+
+import (
+	"io"
+	"log"
+	"sync"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+)
+
+var redactLogOutputOnce sync.Once
+
+// redactLogOutput masks the secrets of the statements in every line logged
+// by the provider or gocql from now on. The plugin SDK sets up the log output
+// before the provider server is created, so it is wrapped rather than replaced.
+func redactLogOutput() {
+	redactLogOutputOnce.Do(func() {
+		log.SetOutput(redactingWriter{w: log.Writer()})
+	})
+}
+
+// redactingWriter masks the secrets of what is written to w. The log package
+// writes each line with a single call.
+type redactingWriter struct {
+	w io.Writer
+}
+
+func (r redactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(r.w, cql.Redact(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,18 @@
+package cassandra
+
+import (
+	"bytes"
+	"log"
+	"testing"
+)
+
+func TestRedactingWriter(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := log.New(redactingWriter{w: &buffer}, "", 0)
+	logger.Printf("Executing query: %s", `CREATE ROLE 'app' WITH PASSWORD = 'secret' AND LOGIN = true`)
+
+	expected := "Executing query: CREATE ROLE 'app' WITH PASSWORD = '***' AND LOGIN = true\n"
+	if buffer.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, buffer.String())
+	}
+}
@@ -0,0 +1,333 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCassandraAggregate() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Create and Delete user-defined aggregates built from user-defined functions",
+		CreateContext: resourceAggregateCreate,
+		ReadContext:   resourceAggregateRead,
+		UpdateContext: resourceAggregateUpdate,
+		DeleteContext: resourceAggregateDelete,
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if mode := meta.(*ProviderConfig).Mode; mode == modeAWSKeyspaces {
+				return fmt.Errorf("user-defined aggregates are not supported in %s mode", mode)
+			}
+			return nil
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceAggregateImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"keyspace": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Keyspace to create the aggregate within, it must also hold the state and final functions",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Name of the aggregate - must contain between 1 and 256 characters",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"argument_types": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "CQL types of the values aggregated, in order. Aggregates are overloaded by their argument types",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"state_function": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Name of the function called for each row (SFUNC). It takes the state followed by the argument types and returns the new state",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"state_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "CQL type of the state (STYPE)",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"final_function": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Name of the function applied to the final state (FINALFUNC). The final state is returned as is when omitted",
+			},
+			"initial_condition": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Initial state as a CQL literal (INITCOND), e.g. 0, 'text' or (0, 0). The state starts out null when omitted",
+			},
+			"return_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "CQL type of the value returned by the aggregate",
+			},
+			"quote_identifiers": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Double-quote the aggregate and function names in generated CQL, keeping their case. Set to false to use unquoted, case-insensitive identifiers",
+			},
+		},
+	}
+}
+
+// Aggregate holds everything needed to render the DDL of a cassandra_aggregate.
+type Aggregate struct {
+	Keyspace         string
+	Name             string
+	ArgumentTypes    []string
+	StateFunction    string
+	StateType        string
+	FinalFunction    string
+	InitialCondition string
+	ReturnType       string
+
+	QuoteIdentifiers bool
+}
+
+// table returns a Table for the aggregate, used to render identifiers.
+func (a *Aggregate) table() *Table {
+	return &Table{Keyspace: a.Keyspace, Name: a.Name, QuoteIdentifiers: a.QuoteIdentifiers}
+}
+
+// signature renders the qualified name and argument types identifying the
+// aggregate among its overloads, e.g. ks.agg(int).
+func (a *Aggregate) signature() string {
+	return fmt.Sprintf("%s(%s)", a.table().qualifiedName(), strings.Join(a.ArgumentTypes, ", "))
+}
+
+func parseAggregateData(d attributeGetter) *Aggregate {
+	return &Aggregate{
+		Keyspace:         d.Get("keyspace").(string),
+		Name:             d.Get("name").(string),
+		ArgumentTypes:    listToArray(d.Get("argument_types")),
+		StateFunction:    d.Get("state_function").(string),
+		StateType:        d.Get("state_type").(string),
+		FinalFunction:    d.Get("final_function").(string),
+		InitialCondition: d.Get("initial_condition").(string),
+
+		QuoteIdentifiers: d.Get("quote_identifiers").(bool),
+	}
+}
+
+// generateCreateAggregateQueryString renders CREATE AGGREGATE, or CREATE OR
+// REPLACE AGGREGATE to change the functions or initial condition in place.
+func generateCreateAggregateQueryString(aggregate *Aggregate, replace bool) string {
+	table := aggregate.table()
+
+	create := "CREATE"
+	if replace {
+		create = "CREATE OR REPLACE"
+	}
+	query := fmt.Sprintf(`%s AGGREGATE %s SFUNC %s STYPE %s`, create, aggregate.signature(), table.identifier(aggregate.StateFunction), aggregate.StateType)
+	if aggregate.FinalFunction != "" {
+		query += " FINALFUNC " + table.identifier(aggregate.FinalFunction)
+	}
+	if aggregate.InitialCondition != "" {
+		query += " INITCOND " + aggregate.InitialCondition
+	}
+	return query
+}
+
+func generateDropAggregateQueryString(aggregate *Aggregate) string {
+	return fmt.Sprintf(`DROP AGGREGATE %s`, aggregate.signature())
+}
+
+// queryAggregates reads the overloads of an aggregate by its stored name, or
+// all aggregates of the keyspace if name is empty.
+func queryAggregates(ctx context.Context, executor CQLExecutor, keyspace string, name string) ([]*Aggregate, error) {
+	var rows []map[string]interface{}
+	var err error
+	if name != "" {
+		rows, err = executor.Select(ctx, `SELECT aggregate_name, argument_types, state_func, state_type, final_func, initcond, return_type FROM system_schema.aggregates WHERE keyspace_name = ? AND aggregate_name = ?`, keyspace, name)
+	} else {
+		rows, err = executor.Select(ctx, `SELECT aggregate_name, argument_types, state_func, state_type, final_func, initcond, return_type FROM system_schema.aggregates WHERE keyspace_name = ?`, keyspace)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	aggregates := []*Aggregate{}
+	for _, row := range rows {
+		aggregate := &Aggregate{Keyspace: keyspace}
+		aggregate.Name, _ = row["aggregate_name"].(string)
+		aggregate.ArgumentTypes, _ = row["argument_types"].([]string)
+		aggregate.StateFunction, _ = row["state_func"].(string)
+		aggregate.StateType, _ = row["state_type"].(string)
+		aggregate.FinalFunction, _ = row["final_func"].(string)
+		aggregate.InitialCondition, _ = row["initcond"].(string)
+		aggregate.ReturnType, _ = row["return_type"].(string)
+		aggregates = append(aggregates, aggregate)
+	}
+	return aggregates, nil
+}
+
+func aggregateID(aggregate *Aggregate) string {
+	return fmt.Sprintf("%s.%s(%s)", aggregate.Keyspace, aggregate.Name, strings.Join(aggregate.ArgumentTypes, ", "))
+}
+
+func resourceAggregateImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	keyspaceName, name, argumentTypes, err := parseFunctionID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return nil, err
+	}
+	defer executor.Close()
+
+	aggregates, err := queryAggregates(ctx, executor, keyspaceName, name)
+	if err != nil {
+		return nil, err
+	}
+	matches := []*Aggregate{}
+	for _, aggregate := range aggregates {
+		if argumentTypes == nil || sameArgumentTypes(argumentTypes, aggregate.ArgumentTypes) {
+			matches = append(matches, aggregate)
+		}
+	}
+	if len(matches) != 1 {
+		return nil, fmt.Errorf("found %d aggregates matching %s, specify the argument types as keyspace.aggregate(type, ...)", len(matches), d.Id())
+	}
+
+	aggregate := matches[0]
+	d.SetId(aggregateID(aggregate))
+	d.Set("keyspace", keyspaceName)
+	d.Set("name", name)
+	d.Set("argument_types", aggregate.ArgumentTypes)
+	d.Set("quote_identifiers", true)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceAggregateCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	aggregate := parseAggregateData(d)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := generateCreateAggregateQueryString(aggregate, false)
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(aggregateID(aggregate))
+	diags = append(diags, warnAfterCreate(resourceAggregateRead(ctx, d, meta))...)
+	return diags
+}
+
+func resourceAggregateRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	aggregate := parseAggregateData(d)
+	table := aggregate.table()
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	aggregates, err := queryAggregates(ctx, executor, table.metadataName(aggregate.Keyspace), table.metadataName(aggregate.Name))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var existing *Aggregate
+	for _, candidate := range aggregates {
+		if sameArgumentTypes(aggregate.ArgumentTypes, candidate.ArgumentTypes) {
+			existing = candidate
+		}
+	}
+	if existing == nil {
+		log.Printf("Aggregate %s no longer exists", aggregate.signature())
+		d.SetId("")
+		return nil
+	}
+
+	// Keep the configured spelling of names, types and the initial condition
+	// unless they actually differ from the definition stored by the cluster.
+	if table.metadataName(aggregate.StateFunction) != existing.StateFunction {
+		d.Set("state_function", existing.StateFunction)
+	}
+	if normalizeCQLType(aggregate.StateType) != normalizeCQLType(existing.StateType) {
+		d.Set("state_type", existing.StateType)
+	}
+	if table.metadataName(aggregate.FinalFunction) != existing.FinalFunction {
+		d.Set("final_function", existing.FinalFunction)
+	}
+	if strings.ReplaceAll(aggregate.InitialCondition, " ", "") != strings.ReplaceAll(existing.InitialCondition, " ", "") {
+		d.Set("initial_condition", existing.InitialCondition)
+	}
+	d.Set("return_type", existing.ReturnType)
+	return diags
+}
+
+func resourceAggregateUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	aggregate := parseAggregateData(d)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := generateCreateAggregateQueryString(aggregate, true)
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	diags = append(diags, resourceAggregateRead(ctx, d, meta)...)
+	return diags
+}
+
+func resourceAggregateDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	aggregate := parseAggregateData(d)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := generateDropAggregateQueryString(aggregate)
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
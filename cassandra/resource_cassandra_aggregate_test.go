@@ -0,0 +1,33 @@
+package cassandra
+
+import "testing"
+
+func TestGenerateAggregateQueryStrings(t *testing.T) {
+	aggregate := &Aggregate{
+		Keyspace:         "some_keyspace",
+		Name:             "average",
+		ArgumentTypes:    []string{"int"},
+		StateFunction:    "avg_state",
+		StateType:        "tuple<int, bigint>",
+		FinalFunction:    "avg_final",
+		InitialCondition: "(0, 0)",
+	}
+
+	expected := `CREATE AGGREGATE some_keyspace.average(int) SFUNC avg_state STYPE tuple<int, bigint> FINALFUNC avg_final INITCOND (0, 0)`
+	if query := generateCreateAggregateQueryString(aggregate, false); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+
+	aggregate.FinalFunction = ""
+	aggregate.InitialCondition = ""
+	aggregate.QuoteIdentifiers = true
+	expected = `CREATE OR REPLACE AGGREGATE "some_keyspace"."average"(int) SFUNC "avg_state" STYPE tuple<int, bigint>`
+	if query := generateCreateAggregateQueryString(aggregate, true); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+
+	expected = `DROP AGGREGATE "some_keyspace"."average"(int)`
+	if query := generateDropAggregateQueryString(aggregate); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+}
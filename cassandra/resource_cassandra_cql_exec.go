@@ -0,0 +1,121 @@
+package cassandra
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCassandraCQLExec() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Run arbitrary CQL when the resource is created and destroyed, for changes not covered by the other resources",
+		CreateContext: resourceCQLExecCreate,
+		ReadContext:   resourceCQLExecRead,
+		UpdateContext: resourceCQLExecUpdate,
+		DeleteContext: resourceCQLExecDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"create_cql": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "CQL statement executed when the resource is created. Changing it destroys and re-creates the resource",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"destroy_cql": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "CQL statement executed when the resource is destroyed. Nothing is executed when omitted",
+			},
+			"exists_query": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "SELECT statement run on refresh. The resource is considered gone, and re-created on the next apply, when it returns no rows",
+			},
+		},
+	}
+}
+
+func resourceCQLExecCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	query := d.Get("create_cql").(string)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(hash(query))
+	diags = append(diags, warnAfterCreate(resourceCQLExecRead(ctx, d, meta))...)
+	return diags
+}
+
+func resourceCQLExecRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	query := d.Get("exists_query").(string)
+	if query == "" {
+		return diags
+	}
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	log.Printf("Executing query: %s", query)
+	rows, err := executor.Select(ctx, query)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if len(rows) == 0 {
+		log.Printf("Query '%s' returned no rows, the resource no longer exists", query)
+		d.SetId("")
+	}
+	return diags
+}
+
+func resourceCQLExecUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// Only destroy_cql and exists_query can change in place, and both are
+	// used by later operations.
+	return resourceCQLExecRead(ctx, d, meta)
+}
+
+func resourceCQLExecDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	query := d.Get("destroy_cql").(string)
+	if query == "" {
+		log.Printf("No destroy_cql set, leaving the effects of '%s' in place", d.Get("create_cql").(string))
+		return diags
+	}
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
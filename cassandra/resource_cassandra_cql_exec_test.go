@@ -0,0 +1,82 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceCQLExecLifecycle(t *testing.T) {
+	executor := newMockCQLExecutor()
+	executor.rows["SELECT id FROM app.settings WHERE id = 1"] = []map[string]interface{}{{"id": 1}}
+	d := schema.TestResourceDataRaw(t, resourceCassandraCQLExec().Schema, map[string]interface{}{
+		"create_cql":   "INSERT INTO app.settings (id) VALUES (1)",
+		"destroy_cql":  "DELETE FROM app.settings WHERE id = 1",
+		"exists_query": "SELECT id FROM app.settings WHERE id = 1",
+	})
+
+	if diags := resourceCQLExecCreate(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if d.Id() == "" {
+		t.Fatal("expected the resource to exist after create")
+	}
+
+	delete(executor.rows, "SELECT id FROM app.settings WHERE id = 1")
+	if diags := resourceCQLExecRead(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if d.Id() != "" {
+		t.Fatal("expected the resource to be gone once exists_query returns no rows")
+	}
+
+	if diags := resourceCQLExecDelete(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	expected := []string{"INSERT INTO app.settings (id) VALUES (1)", "DELETE FROM app.settings WHERE id = 1"}
+	if len(executor.executed) != len(expected) || executor.executed[0] != expected[0] || executor.executed[1] != expected[1] {
+		t.Fatalf("expected %v to be executed, got %v", expected, executor.executed)
+	}
+	// Create reads the resource back, so four executors were opened.
+	if executor.closed != 4 {
+		t.Fatalf("expected every operation to close its executor, got %d closes", executor.closed)
+	}
+}
+
+func TestResourceCQLExecCreateError(t *testing.T) {
+	executor := newMockCQLExecutor()
+	executor.errors["CREATE ROLE app"] = errors.New("line 1:12 no viable alternative")
+	d := schema.TestResourceDataRaw(t, resourceCassandraCQLExec().Schema, map[string]interface{}{
+		"create_cql": "CREATE ROLE app",
+	})
+
+	if diags := resourceCQLExecCreate(context.Background(), d, executor.providerConfig()); !diags.HasError() {
+		t.Fatal("expected the error of create_cql to be returned")
+	}
+	if d.Id() != "" {
+		t.Fatal("expected no ID after a failed create")
+	}
+}
+
+func TestResourceCQLExecCreateReadError(t *testing.T) {
+	executor := newMockCQLExecutor()
+	executor.errors["SELECT id FROM app.settings WHERE id = 1"] = errors.New("Operation timed out")
+	d := schema.TestResourceDataRaw(t, resourceCassandraCQLExec().Schema, map[string]interface{}{
+		"create_cql":   "INSERT INTO app.settings (id) VALUES (1)",
+		"exists_query": "SELECT id FROM app.settings WHERE id = 1",
+	})
+
+	diags := resourceCQLExecCreate(context.Background(), d, executor.providerConfig())
+	if diags.HasError() {
+		t.Fatalf("expected a failed read after create to be a warning, got %v", diags)
+	}
+	if len(diags) != 1 || diags[0].Severity != diag.Warning || diags[0].Detail != createdObjectDetail {
+		t.Fatalf("expected a warning explaining the object was created, got %v", diags)
+	}
+	if d.Id() == "" {
+		t.Fatal("expected the created object to keep its ID")
+	}
+}
@@ -0,0 +1,452 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCassandraFunction() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Create and Delete user-defined functions within Keyspaces",
+		CreateContext: resourceFunctionCreate,
+		ReadContext:   resourceFunctionRead,
+		UpdateContext: resourceFunctionUpdate,
+		DeleteContext: resourceFunctionDelete,
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			mode := meta.(*ProviderConfig).Mode
+			if mode == modeAWSKeyspaces {
+				return fmt.Errorf("user-defined functions are not supported in %s mode", mode)
+			}
+			return validateFunctionLanguage(d.Get("language").(string), mode)
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceFunctionImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"keyspace": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Keyspace to create the function within",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Name of the function - must contain between 1 and 256 characters",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"argument": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Arguments of the function, in order. Functions are overloaded by their argument types",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ForceNew:     true,
+							Description:  "Name of the argument",
+							ValidateFunc: validation.StringLenBetween(1, 256),
+						},
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ForceNew:     true,
+							Description:  "CQL type of the argument",
+							ValidateFunc: validation.StringLenBetween(1, 256),
+						},
+					},
+				},
+			},
+			"return_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "CQL type of the value returned by the function",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"language": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "java",
+				Description: "Language of the body, e.g. java or javascript. ScyllaDB only supports lua and wasm",
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return strings.EqualFold(old, new)
+				},
+			},
+			"called_on_null_input": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Call the function when an argument is null (CALLED ON NULL INPUT) instead of returning null (RETURNS NULL ON NULL INPUT)",
+			},
+			"body": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Source code of the function, without the surrounding $$ delimiters",
+				ValidateFunc: func(i interface{}, k string) ([]string, []error) {
+					if strings.Contains(i.(string), "$$") {
+						return nil, []error{fmt.Errorf("%s: must not contain $$", k)}
+					}
+					return nil, nil
+				},
+			},
+			"quote_identifiers": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Double-quote the function and argument names in generated CQL, keeping their case. Set to false to use unquoted, case-insensitive identifiers",
+			},
+		},
+	}
+}
+
+// scyllaFunctionLanguages are the languages ScyllaDB runs user-defined
+// functions in. It has no JVM, so java and javascript are not among them.
+var scyllaFunctionLanguages = []string{"lua", "wasm"}
+
+func validateFunctionLanguage(language string, mode string) error {
+	if mode != modeScylla {
+		return nil
+	}
+	for _, supported := range scyllaFunctionLanguages {
+		if strings.EqualFold(language, supported) {
+			return nil
+		}
+	}
+	return fmt.Errorf("language %s is not supported in %s mode, only %s", language, mode, strings.Join(scyllaFunctionLanguages, " and "))
+}
+
+// Function holds everything needed to render the DDL of a cassandra_function.
+type Function struct {
+	Keyspace          string
+	Name              string
+	Arguments         []TableColumn
+	ReturnType        string
+	Language          string
+	CalledOnNullInput bool
+	Body              string
+
+	QuoteIdentifiers bool
+}
+
+// table returns a Table for the function, used to render identifiers.
+func (f *Function) table() *Table {
+	return &Table{Keyspace: f.Keyspace, Name: f.Name, QuoteIdentifiers: f.QuoteIdentifiers}
+}
+
+func (f *Function) argumentTypes() []string {
+	types := make([]string, 0, len(f.Arguments))
+	for _, argument := range f.Arguments {
+		types = append(types, argument.Type)
+	}
+	return types
+}
+
+// signature renders the qualified name and argument types identifying the
+// function among its overloads, e.g. ks.fn(int, text).
+func (f *Function) signature() string {
+	return fmt.Sprintf("%s(%s)", f.table().qualifiedName(), strings.Join(f.argumentTypes(), ", "))
+}
+
+func parseFunctionData(d attributeGetter) *Function {
+	function := &Function{
+		Keyspace:          d.Get("keyspace").(string),
+		Name:              d.Get("name").(string),
+		ReturnType:        d.Get("return_type").(string),
+		Language:          d.Get("language").(string),
+		CalledOnNullInput: d.Get("called_on_null_input").(bool),
+		Body:              d.Get("body").(string),
+
+		QuoteIdentifiers: d.Get("quote_identifiers").(bool),
+	}
+	for _, raw := range d.Get("argument").([]interface{}) {
+		argument := raw.(map[string]interface{})
+		function.Arguments = append(function.Arguments, TableColumn{
+			Name: argument["name"].(string),
+			Type: argument["type"].(string),
+		})
+	}
+	return function
+}
+
+// generateCreateFunctionQueryString renders CREATE FUNCTION, or CREATE OR
+// REPLACE FUNCTION to change the body, language or null handling in place.
+func generateCreateFunctionQueryString(function *Function, replace bool) string {
+	table := function.table()
+	arguments := make([]string, 0, len(function.Arguments))
+	for _, argument := range function.Arguments {
+		arguments = append(arguments, fmt.Sprintf("%s %s", table.identifier(argument.Name), argument.Type))
+	}
+
+	onNullInput := "RETURNS NULL ON NULL INPUT"
+	if function.CalledOnNullInput {
+		onNullInput = "CALLED ON NULL INPUT"
+	}
+
+	create := "CREATE"
+	if replace {
+		create = "CREATE OR REPLACE"
+	}
+	return fmt.Sprintf(`%s FUNCTION %s(%s) %s RETURNS %s LANGUAGE %s AS $$%s$$`,
+		create, table.qualifiedName(), strings.Join(arguments, ", "), onNullInput, function.ReturnType, function.Language, function.Body)
+}
+
+func generateDropFunctionQueryString(function *Function) string {
+	return fmt.Sprintf(`DROP FUNCTION %s`, function.signature())
+}
+
+// queryFunctions reads the overloads of a function by its stored name, or all
+// functions of the keyspace if name is empty.
+func queryFunctions(ctx context.Context, executor CQLExecutor, keyspace string, name string) ([]*Function, error) {
+	var rows []map[string]interface{}
+	var err error
+	if name != "" {
+		rows, err = executor.Select(ctx, `SELECT function_name, argument_names, argument_types, return_type, language, called_on_null_input, body FROM system_schema.functions WHERE keyspace_name = ? AND function_name = ?`, keyspace, name)
+	} else {
+		rows, err = executor.Select(ctx, `SELECT function_name, argument_names, argument_types, return_type, language, called_on_null_input, body FROM system_schema.functions WHERE keyspace_name = ?`, keyspace)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	functions := []*Function{}
+	for _, row := range rows {
+		function := &Function{Keyspace: keyspace}
+		function.Name, _ = row["function_name"].(string)
+		argumentNames, _ := row["argument_names"].([]string)
+		argumentTypes, _ := row["argument_types"].([]string)
+		function.Arguments = userTypeFields(argumentNames, argumentTypes)
+		function.ReturnType, _ = row["return_type"].(string)
+		function.Language, _ = row["language"].(string)
+		function.CalledOnNullInput, _ = row["called_on_null_input"].(bool)
+		function.Body, _ = row["body"].(string)
+		functions = append(functions, function)
+	}
+	return functions, nil
+}
+
+// sameArgumentTypes compares configured argument types with stored ones,
+// ignoring the spelling of equivalent types such as text and varchar.
+func sameArgumentTypes(configured, existing []string) bool {
+	if len(configured) != len(existing) {
+		return false
+	}
+	for i := range configured {
+		if normalizeCQLType(configured[i]) != normalizeCQLType(existing[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func flattenFunctionArguments(arguments []TableColumn) []interface{} {
+	ret := make([]interface{}, 0, len(arguments))
+	for _, argument := range arguments {
+		ret = append(ret, map[string]interface{}{
+			"name": argument.Name,
+			"type": argument.Type,
+		})
+	}
+	return ret
+}
+
+// parseFunctionID splits an ID of the form keyspace.name(type, ...). The
+// argument types are optional when importing a function that is not overloaded.
+func parseFunctionID(id string) (string, string, []string, error) {
+	signature := id
+	var argumentTypes []string
+	if open := strings.Index(id, "("); open >= 0 && strings.HasSuffix(id, ")") {
+		signature = id[:open]
+		argumentTypes = splitTopLevel(id[open+1 : len(id)-1])
+	}
+	parts := strings.SplitN(signature, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", nil, fmt.Errorf("unexpected format of ID (%s), expected keyspace.function or keyspace.function(type, ...)", id)
+	}
+	return parts[0], parts[1], argumentTypes, nil
+}
+
+// splitTopLevel splits a comma separated list of CQL types, keeping the
+// commas nested in collection types such as map<text, int>.
+func splitTopLevel(list string) []string {
+	parts := []string{}
+	depth, start := 0, 0
+	for i, r := range list {
+		switch r {
+		case '<', '(':
+			depth++
+		case '>', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(list[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if last := strings.TrimSpace(list[start:]); last != "" {
+		parts = append(parts, last)
+	}
+	return parts
+}
+
+func functionID(function *Function) string {
+	return fmt.Sprintf("%s.%s(%s)", function.Keyspace, function.Name, strings.Join(function.argumentTypes(), ", "))
+}
+
+func resourceFunctionImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	keyspaceName, name, argumentTypes, err := parseFunctionID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return nil, err
+	}
+	defer executor.Close()
+
+	functions, err := queryFunctions(ctx, executor, keyspaceName, name)
+	if err != nil {
+		return nil, err
+	}
+	matches := []*Function{}
+	for _, function := range functions {
+		if argumentTypes == nil || sameArgumentTypes(argumentTypes, function.argumentTypes()) {
+			matches = append(matches, function)
+		}
+	}
+	if len(matches) != 1 {
+		return nil, fmt.Errorf("found %d functions matching %s, specify the argument types as keyspace.function(type, ...)", len(matches), d.Id())
+	}
+
+	function := matches[0]
+	d.SetId(functionID(function))
+	d.Set("keyspace", keyspaceName)
+	d.Set("name", name)
+	d.Set("argument", flattenFunctionArguments(function.Arguments))
+	d.Set("quote_identifiers", true)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceFunctionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	function := parseFunctionData(d)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := generateCreateFunctionQueryString(function, false)
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(functionID(function))
+	diags = append(diags, warnAfterCreate(resourceFunctionRead(ctx, d, meta))...)
+	return diags
+}
+
+func resourceFunctionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	function := parseFunctionData(d)
+	table := function.table()
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	functions, err := queryFunctions(ctx, executor, table.metadataName(function.Keyspace), table.metadataName(function.Name))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var existing *Function
+	for _, candidate := range functions {
+		if sameArgumentTypes(function.argumentTypes(), candidate.argumentTypes()) {
+			existing = candidate
+		}
+	}
+	if existing == nil {
+		log.Printf("Function %s no longer exists", function.signature())
+		d.SetId("")
+		return nil
+	}
+
+	// Keep the configured spelling of types and the body unless they actually
+	// differ from the definition stored by the cluster.
+	if normalizeCQLType(function.ReturnType) != normalizeCQLType(existing.ReturnType) {
+		d.Set("return_type", existing.ReturnType)
+	}
+	if !strings.EqualFold(function.Language, existing.Language) {
+		d.Set("language", existing.Language)
+	}
+	if strings.TrimSpace(function.Body) != strings.TrimSpace(existing.Body) {
+		d.Set("body", existing.Body)
+	}
+	d.Set("called_on_null_input", existing.CalledOnNullInput)
+	return diags
+}
+
+func resourceFunctionUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	function := parseFunctionData(d)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := generateCreateFunctionQueryString(function, true)
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	diags = append(diags, resourceFunctionRead(ctx, d, meta)...)
+	return diags
+}
+
+func resourceFunctionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	function := parseFunctionData(d)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := generateDropFunctionQueryString(function)
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
@@ -0,0 +1,100 @@
+package cassandra
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateFunctionQueryStrings(t *testing.T) {
+	function := &Function{
+		Keyspace:   "some_keyspace",
+		Name:       "fLog",
+		Arguments:  []TableColumn{{Name: "input", Type: "double"}, {Name: "weights", Type: "map<text, int>"}},
+		ReturnType: "double",
+		Language:   "java",
+		Body:       "return Double.valueOf(Math.log(input.doubleValue()));",
+
+		QuoteIdentifiers: true,
+	}
+
+	expected := `CREATE FUNCTION "some_keyspace"."fLog"("input" double, "weights" map<text, int>) RETURNS NULL ON NULL INPUT RETURNS double LANGUAGE java AS $$return Double.valueOf(Math.log(input.doubleValue()));$$`
+	if query := generateCreateFunctionQueryString(function, false); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+
+	function.CalledOnNullInput = true
+	expected = `CREATE OR REPLACE FUNCTION "some_keyspace"."fLog"("input" double, "weights" map<text, int>) CALLED ON NULL INPUT RETURNS double LANGUAGE java AS $$return Double.valueOf(Math.log(input.doubleValue()));$$`
+	if query := generateCreateFunctionQueryString(function, true); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+
+	expected = `DROP FUNCTION "some_keyspace"."fLog"(double, map<text, int>)`
+	if query := generateDropFunctionQueryString(function); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+}
+
+func TestParseFunctionID(t *testing.T) {
+	function := &Function{Keyspace: "some_keyspace", Name: "fn", Arguments: []TableColumn{{Type: "int"}, {Type: "map<text, int>"}}}
+	keyspace, name, argumentTypes, err := parseFunctionID(functionID(function))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyspace != "some_keyspace" || name != "fn" || !reflect.DeepEqual(argumentTypes, []string{"int", "map<text, int>"}) {
+		t.Fatalf("unexpected ID parts %q, %q, %v", keyspace, name, argumentTypes)
+	}
+
+	if _, _, argumentTypes, err := parseFunctionID("some_keyspace.fn()"); err != nil || argumentTypes == nil || len(argumentTypes) != 0 {
+		t.Fatalf("expected a function without arguments, got %v, %v", argumentTypes, err)
+	}
+	if _, _, argumentTypes, err := parseFunctionID("some_keyspace.fn"); err != nil || argumentTypes != nil {
+		t.Fatalf("expected no argument types, got %v, %v", argumentTypes, err)
+	}
+	if _, _, _, err := parseFunctionID("fn(int)"); err == nil {
+		t.Fatal("expected an ID without a keyspace to be rejected")
+	}
+}
+
+func TestValidateFunctionLanguage(t *testing.T) {
+	if err := validateFunctionLanguage("java", modeCassandra); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateFunctionLanguage("LUA", modeScylla); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateFunctionLanguage("java", modeScylla); err == nil {
+		t.Fatal("expected java to be rejected in scylla mode")
+	}
+}
+
+func TestQueryFunctions(t *testing.T) {
+	executor := newMockCQLExecutor()
+	executor.rows[`SELECT function_name, argument_names, argument_types, return_type, language, called_on_null_input, body FROM system_schema.functions WHERE keyspace_name = ? AND function_name = ? [app add]`] = []map[string]interface{}{
+		{
+			"function_name":        "add",
+			"argument_names":       []string{"a", "b"},
+			"argument_types":       []string{"int", "int"},
+			"return_type":          "int",
+			"language":             "java",
+			"called_on_null_input": false,
+			"body":                 "return a + b;",
+		},
+	}
+
+	functions, err := queryFunctions(context.Background(), executor, "app", "add")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*Function{{
+		Keyspace:   "app",
+		Name:       "add",
+		Arguments:  []TableColumn{{Name: "a", Type: "int"}, {Name: "b", Type: "int"}},
+		ReturnType: "int",
+		Language:   "java",
+		Body:       "return a + b;",
+	}}
+	if !reflect.DeepEqual(functions, expected) {
+		t.Fatalf("expected %+v, got %+v", expected[0], functions)
+	}
+}
@@ -1,10 +1,8 @@
 package cassandra
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"html/template"
 	"log"
 	"regexp"
 	"strings"
@@ -13,15 +11,10 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-)
 
-const (
-	deleteGrantRawTemplate = `REVOKE {{ .Privilege }} ON {{.ResourceType}} {{if .Keyspace }}"{{ .Keyspace}}"{{end}}{{if and .Keyspace .Identifier}}.{{end}}{{if .Identifier}}"{{.Identifier}}"{{end}} FROM "{{.Grantee}}"`
-	createGrantRawTemplate = `GRANT {{ .Privilege }} ON {{.ResourceType}} {{if .Keyspace }}"{{ .Keyspace}}"{{end}}{{if and .Keyspace .Identifier}}.{{end}}{{if .Identifier}}"{{.Identifier}}"{{end}} TO "{{.Grantee}}"`
+	"github.com/konradotto/terraform-provider-cassandra/cql"
 )
 
-const templateReadGrant = `SELECT permissions FROM {{.SystemKeyspace}}.role_permissions where resource='data/{{if .Keyspace }}{{ .Keyspace }}{{end}}{{if and .Keyspace .Identifier}}/{{end}}{{if .Identifier}}{{.Identifier}}{{end}}' and role='{{.Grantee}}' ALLOW FILTERING;`
-
 const (
 	privilegeAll       = "all"
 	privilegeCreate    = "create"
@@ -57,9 +50,10 @@ const (
 	identifierResourceType = "resource_type"
 )
 
+// grantIdentityAttributes are the attributes identifying a grant.
+var grantIdentityAttributes = []string{identifierPrivilege, identifierGrantee, identifierResourceType, identifierKeyspaceName, identifierFunctionName, identifierTableName, identifierRoleName, identifierMbeanName, identifierMbeanPattern}
+
 var (
-	templateDelete, _           = template.New("delete_grant").Parse(deleteGrantRawTemplate)
-	templateCreate, _           = template.New("create_grant").Parse(createGrantRawTemplate)
 	validIdentifierRegex, _     = regexp.Compile(`^[^"]{1,256}$`)
 	validTableNameRegex, _      = regexp.Compile(`^[a-zA-Z0-9][a-zA-Z0-9_]{0,255}`)
 	allPrivileges               = []string{privilegeSelect, privilegeCreate, privilegeAlter, privilegeDrop, privilegeModify, privilegeAuthorize, privilegeDescribe, privilegeExecute}
@@ -107,6 +101,53 @@ type Grant struct {
 	Identifier   string
 }
 
+// grantIdentifier renders the name of the resource of a grant: mbeans are
+// named by string literals and functions keep their argument types.
+func grantIdentifier(grant *Grant) string {
+	switch grant.ResourceType {
+	case resourceMbean, resourceMbeans:
+		return cql.Literal(grant.Identifier)
+	case resourceFunction:
+		if i := strings.Index(grant.Identifier, "("); i != -1 {
+			return cql.QuoteIdentifier(grant.Identifier[:i]) + grant.Identifier[i:]
+		}
+	}
+	return cql.QuoteIdentifier(grant.Identifier)
+}
+
+// grantResource renders the resource of a GRANT or REVOKE statement, e.g.
+// table "app"."events".
+func grantResource(grant *Grant) string {
+	parts := []string{grant.ResourceType}
+	switch {
+	case grant.Keyspace != "" && grant.Identifier != "":
+		parts = append(parts, cql.QuoteIdentifier(grant.Keyspace)+"."+grantIdentifier(grant))
+	case grant.Keyspace != "":
+		parts = append(parts, cql.QuoteIdentifier(grant.Keyspace))
+	case grant.Identifier != "":
+		parts = append(parts, grantIdentifier(grant))
+	}
+	return strings.Join(parts, " ")
+}
+
+func generateGrantQueryString(grant *Grant) string {
+	return fmt.Sprintf(`GRANT %s ON %s TO %s`, grant.Privilege, grantResource(grant), cql.QuoteIdentifier(grant.Grantee))
+}
+
+func generateRevokeQueryString(grant *Grant) string {
+	return fmt.Sprintf(`REVOKE %s ON %s FROM %s`, grant.Privilege, grantResource(grant), cql.QuoteIdentifier(grant.Grantee))
+}
+
+// grantPermissionsResource returns the name of the resource of a grant in
+// the role_permissions table, e.g. data/app/events.
+func grantPermissionsResource(grant *Grant) string {
+	resource := "data/" + grant.Keyspace
+	if grant.Keyspace != "" && grant.Identifier != "" {
+		resource += "/"
+	}
+	return resource + grant.Identifier
+}
+
 func validIdentifier(i interface{}, path cty.Path, identifierName string, regularExpression *regexp.Regexp) diag.Diagnostics {
 	identifier := i.(string)
 	if identifierName != "" && !regularExpression.MatchString(identifier) {
@@ -129,6 +170,13 @@ func resourceCassandraGrant() *schema.Resource {
 		ReadContext:   resourceGrantRead,
 		UpdateContext: resourceGrantUpdate,
 		DeleteContext: resourceGrantDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importStateWithIdentity(grantIdentityAttributes, grantID, resourceGrantImport),
+		},
+		Identity: stringIdentity(
+			[]string{identifierPrivilege, identifierGrantee, identifierResourceType},
+			[]string{identifierKeyspaceName, identifierFunctionName, identifierTableName, identifierRoleName, identifierMbeanName, identifierMbeanPattern},
+		),
 		Schema: map[string]*schema.Schema{
 			identifierPrivilege: {
 				Type:        schema.TypeString,
@@ -308,44 +356,22 @@ func parseData(d *schema.ResourceData) (*Grant, error) {
 	return &Grant{privilege, resourceType, grantee, keyspaceName, identifier}, nil
 }
 
-func resourceGrantExists(d *schema.ResourceData, meta interface{}) (bool, error) {
+func resourceGrantExists(ctx context.Context, d *schema.ResourceData, meta interface{}) (bool, error) {
 	grant, err := parseData(d)
 	if err != nil {
 		return false, err
 	}
 
 	providerConfig := meta.(*ProviderConfig)
-	cluster := providerConfig.Cluster
-
-	session, sessionCreationError := cluster.CreateSession()
-	if sessionCreationError != nil {
-		return false, sessionCreationError
-	}
-	defer session.Close()
-
-	var buffer bytes.Buffer
-	tmpl, err := template.New("read_grant").Parse(templateReadGrant)
+	executor, err := providerConfig.sharedExecutor()
 	if err != nil {
 		return false, err
 	}
-	data := struct {
-		*Grant
-		SystemKeyspace string
-	}{
-		Grant:          grant,
-		SystemKeyspace: providerConfig.SystemKeyspaceName,
-	}
-	if err := tmpl.Execute(&buffer, data); err != nil {
-		return false, err
-	}
-	query := buffer.String()
+	defer executor.Close()
 
-	iter := session.Query(query).Iter()
-	rowCount := iter.NumRows()
-	if err := iter.Close(); err != nil {
-		return false, err
-	}
-	return rowCount > 0, nil
+	return providerConfig.permissionCache.hasPermissions(grant.Grantee, grantPermissionsResource(grant), func(role string) (map[string]bool, error) {
+		return queryRolePermissionResources(ctx, executor, providerConfig.authKeyspace(ctx, executor), role)
+	})
 }
 
 func resourceGrantCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -356,30 +382,27 @@ func resourceGrantCreate(ctx context.Context, d *schema.ResourceData, meta inter
 	}
 
 	providerConfig := meta.(*ProviderConfig)
-	cluster := providerConfig.Cluster
-
-	session, sessionCreationError := cluster.CreateSession()
-	if sessionCreationError != nil {
-		return diag.FromErr(sessionCreationError)
-	}
-	defer session.Close()
-
-	var buffer bytes.Buffer
-	if err := templateCreate.Execute(&buffer, grant); err != nil {
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
 		return diag.FromErr(err)
 	}
-	query := buffer.String()
+	defer executor.Close()
+
+	query := generateGrantQueryString(grant)
 	log.Printf("Executing query %v", query)
-	if err := session.Query(query).Exec(); err != nil {
+	if err := executor.Exec(ctx, query); err != nil {
 		return diag.FromErr(err)
 	}
 	d.SetId(hash(fmt.Sprintf("%+v", grant)))
-	diags = append(diags, resourceGrantRead(ctx, d, meta)...)
+	if err := setIdentity(d, grantIdentityAttributes...); err != nil {
+		return diag.FromErr(err)
+	}
+	diags = append(diags, warnAfterCreate(resourceGrantRead(ctx, d, meta))...)
 	return diags
 }
 
 func resourceGrantRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	exists, err := resourceGrantExists(d, meta)
+	exists, err := resourceGrantExists(ctx, d, meta)
 	var diags diag.Diagnostics
 	if err != nil {
 		return diag.FromErr(err)
@@ -403,31 +426,46 @@ func resourceGrantRead(ctx context.Context, d *schema.ResourceData, meta interfa
 		identifierName := resourceTypeToIdentifier[grant.ResourceType]
 		d.Set(identifierName, grant.Identifier)
 	}
+	if err := setIdentity(d, grantIdentityAttributes...); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
-func resourceGrantDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+// grantID derives the ID of a grant from its attributes.
+func grantID(d *schema.ResourceData) (string, error) {
 	grant, err := parseData(d)
-	var diags diag.Diagnostics
 	if err != nil {
-		return diag.FromErr(err)
+		return "", err
 	}
+	return hash(fmt.Sprintf("%+v", grant)), nil
+}
 
-	var buffer bytes.Buffer
-	if err := templateDelete.Execute(&buffer, grant); err != nil {
+// resourceGrantImport checks that an imported grant has its attributes set,
+// which its hashed ID cannot be parsed back into.
+func resourceGrantImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, err := parseData(d); err != nil {
+		return nil, fmt.Errorf("grants can only be imported by identity, e.g. with an import block giving privilege, grantee and resource_type: %w", err)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceGrantDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	grant, err := parseData(d)
+	var diags diag.Diagnostics
+	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	providerConfig := meta.(*ProviderConfig)
-	cluster := providerConfig.Cluster
-	session, err := cluster.CreateSession()
+	executor, err := providerConfig.newExecutor()
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	defer session.Close()
+	defer executor.Close()
 
-	query := buffer.String()
-	if err := session.Query(query).Exec(); err != nil {
+	query := generateRevokeQueryString(grant)
+	if err := executor.Exec(ctx, query); err != nil {
 		return diag.FromErr(err)
 	}
 	return diags
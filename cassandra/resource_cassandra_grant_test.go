@@ -1,10 +1,13 @@
 package cassandra
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"reflect"
 	"testing"
 
+	"github.com/gocql/gocql"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -31,16 +34,15 @@ func testAccPreCheckNoArgs() {
 func testAccCassandraGrantConfig(mode string) string {
 	return fmt.Sprintf(`
 provider "cassandra" {
-  host = "127.0.0.1"
   mode = "%s"
 }
 
 resource "cassandra_grant" "test" {
   privilege      = "select"
-  grantee        = "test_user"
+  grantee        = "tf_acc_user"
   resource_type  = "table"
-  keyspace_name  = "test_keyspace"
-  table_name     = "test_table"
+  keyspace_name  = "tf_acc_keyspace"
+  table_name     = "tf_acc_table"
 }
 `, mode)
 }
@@ -65,7 +67,7 @@ func testAccCassandraGrantExists(resourceKey string) resource.TestCheckFunc {
 		}
 		defer session.Close()
 
-		exists, err := resourceGrantExists(d, pc)
+		exists, err := resourceGrantExists(context.Background(), d, pc)
 		if err != nil {
 			return err
 		}
@@ -92,7 +94,7 @@ func testAccCassandraGrantDestroy(s *terraform.State) error {
 		}
 		attrs := convertStringMapToInterface(rs.Primary.Attributes)
 		d := schema.TestResourceDataRaw(nil, resourceCassandraGrant().Schema, attrs)
-		exists, err := resourceGrantExists(d, pc)
+		exists, err := resourceGrantExists(context.Background(), d, pc)
 		if err != nil {
 			return err
 		}
@@ -115,7 +117,7 @@ func TestAccCassandraGrant_basicCassandra(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					testAccCassandraGrantExists("cassandra_grant.test"),
 					resource.TestCheckResourceAttr("cassandra_grant.test", "privilege", "select"),
-					resource.TestCheckResourceAttr("cassandra_grant.test", "grantee", "test_user"),
+					resource.TestCheckResourceAttr("cassandra_grant.test", "grantee", "tf_acc_user"),
 				),
 			},
 		},
@@ -125,7 +127,7 @@ func TestAccCassandraGrant_basicCassandra(t *testing.T) {
 // TestAccCassandraGrant_basicScylla tests the cassandra_grant resource with provider mode "scylla".
 func TestAccCassandraGrant_basicScylla(t *testing.T) {
 	resource.Test(t, resource.TestCase{
-		PreCheck:          testAccPreCheckNoArgs,
+		PreCheck:          func() { testAccPreCheckNoArgs(); testAccPreCheckServer(t, "scylla", "scylla-6.2") },
 		ProviderFactories: testAccProviderFactories,
 		CheckDestroy:      testAccCassandraGrantDestroy,
 		Steps: []resource.TestStep{
@@ -134,9 +136,105 @@ func TestAccCassandraGrant_basicScylla(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					testAccCassandraGrantExists("cassandra_grant.test"),
 					resource.TestCheckResourceAttr("cassandra_grant.test", "privilege", "select"),
-					resource.TestCheckResourceAttr("cassandra_grant.test", "grantee", "test_user"),
+					resource.TestCheckResourceAttr("cassandra_grant.test", "grantee", "tf_acc_user"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccCassandraGrant_azureManagedInstance tests the cassandra_grant resource
+// with provider mode "azure_managed_instance", whose management account may
+// not read the system_auth tables.
+func TestAccCassandraGrant_azureManagedInstance(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheckNoArgs(); testAccPreCheckAzureManagedInstance(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCassandraGrantDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCassandraGrantConfig(modeAzureManagedInstance),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCassandraGrantExists("cassandra_grant.test"),
+					resource.TestCheckResourceAttr("cassandra_grant.test", "privilege", "select"),
+					resource.TestCheckResourceAttr("cassandra_grant.test", "grantee", "tf_acc_user"),
 				),
 			},
 		},
 	})
 }
+
+func TestGenerateGrantQueryString(t *testing.T) {
+	cases := []struct {
+		grant    Grant
+		expected string
+	}{
+		{Grant{privilegeSelect, resourceTable, "app", "ks", "Events"}, `GRANT select ON table "ks"."Events" TO "app"`},
+		{Grant{privilegeCreate, resourceAllKeyspaces, `o"brien`, "", ""}, `GRANT create ON all keyspaces TO "o""brien"`},
+		{Grant{privilegeExecute, resourceFunction, "app", "ks", "avg(int, int)"}, `GRANT execute ON function "ks"."avg"(int, int) TO "app"`},
+		{Grant{privilegeSelect, resourceMbean, "ops", "", "org.apache.cassandra.db:type=Tables"}, `GRANT select ON mbean 'org.apache.cassandra.db:type=Tables' TO "ops"`},
+		{Grant{privilegeAlter, resourceRole, "admin", "", "app"}, `GRANT alter ON role "app" TO "admin"`},
+	}
+	for _, c := range cases {
+		grant := c.grant
+		if actual := generateGrantQueryString(&grant); actual != c.expected {
+			t.Fatalf("expected %s, got %s", c.expected, actual)
+		}
+	}
+
+	grant := Grant{privilegeModify, resourceKeyspace, "app", "ks", ""}
+	if actual := generateRevokeQueryString(&grant); actual != `REVOKE modify ON keyspace "ks" FROM "app"` {
+		t.Fatalf("unexpected revoke %s", actual)
+	}
+	if actual := grantPermissionsResource(&Grant{privilegeSelect, resourceTable, "app", "ks", "events"}); actual != "data/ks/events" {
+		t.Fatalf("unexpected permissions resource %s", actual)
+	}
+}
+
+func TestResourceGrantLifecycle(t *testing.T) {
+	executor := newMockCQLExecutor()
+	d := testResourceDataWithIdentity(t, resourceCassandraGrant(), map[string]interface{}{
+		identifierPrivilege:    privilegeSelect,
+		identifierResourceType: resourceTable,
+		identifierGrantee:      "app",
+		identifierKeyspaceName: "ks",
+		identifierTableName:    "events",
+	})
+	executor.rows[`SELECT resource FROM system_auth.role_permissions WHERE role = ? [app]`] = []map[string]interface{}{
+		{"resource": "data/ks"},
+		{"resource": "data/ks/events"},
+	}
+
+	if diags := resourceGrantCreate(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if diags := resourceGrantDelete(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	expected := []string{`GRANT select ON table "ks"."events" TO "app"`, `REVOKE select ON table "ks"."events" FROM "app"`}
+	if !reflect.DeepEqual(executor.executed, expected) {
+		t.Fatalf("expected %v, got %v", expected, executor.executed)
+	}
+
+	executor.rows = map[string][]map[string]interface{}{}
+	if diags := resourceGrantRead(context.Background(), d, executor.providerConfig()); !diags.HasError() {
+		t.Fatal("expected reading a revoked grant to fail")
+	}
+}
+
+func TestQueryRolePermissionResources_listPermissions(t *testing.T) {
+	executor := newMockCQLExecutor()
+	executor.errors[`SELECT resource FROM system_auth.role_permissions WHERE role = ?`] = testRequestError{gocql.ErrCodeUnauthorized, "User ops has no SELECT permission"}
+	executor.rows[`LIST ALL PERMISSIONS OF 'app' NORECURSIVE`] = []map[string]interface{}{
+		{"role": "app", "resource": "<table ks.events>", "permission": "SELECT"},
+		{"role": "app", "resource": "<all keyspaces>", "permission": "CREATE"},
+	}
+
+	resources, err := queryRolePermissionResources(context.Background(), executor, "system_auth", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resources["data/ks/events"] || !resources["data"] {
+		t.Fatalf("expected the listed data resources, got %v", resources)
+	}
+}
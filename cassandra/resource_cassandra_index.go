@@ -0,0 +1,468 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+	"github.com/konradotto/terraform-provider-cassandra/cqlschema"
+)
+
+const (
+	indexTypeSecondary = "secondary"
+	indexTypeSAI       = "sai"
+	indexTypeSASI      = "sasi"
+	indexTypeCustom    = "custom"
+)
+
+var (
+	// indexClasses maps the custom index types to the class passed to USING.
+	indexClasses = map[string]string{
+		indexTypeSAI:  "StorageAttachedIndex",
+		indexTypeSASI: "org.apache.cassandra.index.sasi.SASIIndex",
+	}
+
+	saiSimilarityFunctions = []string{"COSINE", "DOT_PRODUCT", "EUCLIDEAN"}
+
+	indexTargetKinds = []string{"KEYS", "VALUES", "ENTRIES", "FULL"}
+)
+
+func resourceCassandraIndex() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Create and Delete secondary indexes on table columns",
+		CreateContext: resourceIndexCreate,
+		ReadContext:   resourceIndexRead,
+		DeleteContext: resourceIndexDelete,
+		CustomizeDiff: resourceIndexCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceIndexImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"keyspace": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Keyspace of the table",
+			},
+			"table": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Name of the indexed table",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"column": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Name of the indexed column",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"target_kind": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  fmt.Sprintf("Part of a collection column to index - one of %s. KEYS, VALUES and ENTRIES apply to maps, VALUES also to lists and sets and FULL to frozen collections. Indexes the column itself when omitted", strings.Join(indexTargetKinds, ", ")),
+				ValidateFunc: validation.StringInSlice(indexTargetKinds, true),
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				Description:  "Name of the index, unique within the keyspace. Generated by the cluster when omitted, e.g. table_column_idx",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"index_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      indexTypeSecondary,
+				Description:  fmt.Sprintf("Implementation of the index - one of %s (built-in secondary index), %s (Storage-Attached Index, Cassandra 5.0+ and Astra), %s (SSTable-Attached Secondary Index) or %s (the class given in using_class)", indexTypeSecondary, indexTypeSAI, indexTypeSASI, indexTypeCustom),
+				ValidateFunc: validation.StringInSlice([]string{indexTypeSecondary, indexTypeSAI, indexTypeSASI, indexTypeCustom}, false),
+			},
+			"using_class": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  fmt.Sprintf("Fully qualified Java class implementing the index, e.g. com.stratio.cassandra.lucene.Index - requires index_type %s", indexTypeCustom),
+				ValidateFunc: validation.StringLenBetween(1, 1024),
+			},
+			"options": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: fmt.Sprintf("Options of a %s or %s index, e.g. mode and analyzer_class for SASI or the options defined by using_class", indexTypeSASI, indexTypeCustom),
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"sai": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Description: fmt.Sprintf("Options of a Storage-Attached Index - requires index_type %s", indexTypeSAI),
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"case_sensitive": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							Description: "Match text values case-sensitively",
+						},
+						"normalize": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Apply Unicode normalization to text values before indexing",
+						},
+						"ascii": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Fold non-ASCII characters of text values to their ASCII equivalents",
+						},
+						"similarity_function": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Description:  fmt.Sprintf("Similarity function of an index on a vector column - one of %s", strings.Join(saiSimilarityFunctions, ", ")),
+							ValidateFunc: validation.StringInSlice(saiSimilarityFunctions, true),
+						},
+					},
+				},
+			},
+			"quote_identifiers": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Double-quote the index, table and column names in generated CQL, keeping their case. Set to false to use unquoted, case-insensitive identifiers",
+			},
+		},
+	}
+}
+
+// Index holds everything needed to render the DDL of a cassandra_index.
+type Index struct {
+	Keyspace string
+	Table    string
+	Column   string
+	Kind     string
+	Name     string
+	Type     string
+	Class    string
+	Options  map[string]string
+
+	QuoteIdentifiers bool
+}
+
+// table returns a Table for the indexed table, used to render identifiers.
+func (i *Index) table() *Table {
+	return &Table{Keyspace: i.Keyspace, Name: i.Table, QuoteIdentifiers: i.QuoteIdentifiers}
+}
+
+func parseIndexData(d attributeGetter) *Index {
+	return &Index{
+		Keyspace: d.Get("keyspace").(string),
+		Table:    d.Get("table").(string),
+		Column:   d.Get("column").(string),
+		Kind:     strings.ToUpper(d.Get("target_kind").(string)),
+		Name:     d.Get("name").(string),
+		Type:     d.Get("index_type").(string),
+		Class:    d.Get("using_class").(string),
+		Options:  indexOptions(d),
+
+		QuoteIdentifiers: d.Get("quote_identifiers").(bool),
+	}
+}
+
+// indexOptions returns the options passed to WITH OPTIONS for the index_type.
+func indexOptions(d attributeGetter) map[string]string {
+	if d.Get("index_type").(string) == indexTypeSAI {
+		return renderSAIOptions(d.Get("sai").([]interface{}))
+	}
+	return mapToStringMap(d.Get("options"))
+}
+
+// renderSAIOptions returns the options of a Storage-Attached Index that differ
+// from the defaults, so indexes on non-text columns get no text analysis options.
+func renderSAIOptions(blocks []interface{}) map[string]string {
+	options := map[string]string{}
+	if len(blocks) == 0 || blocks[0] == nil {
+		return options
+	}
+	block := blocks[0].(map[string]interface{})
+	if !block["case_sensitive"].(bool) {
+		options["case_sensitive"] = "false"
+	}
+	if block["normalize"].(bool) {
+		options["normalize"] = "true"
+	}
+	if block["ascii"].(bool) {
+		options["ascii"] = "true"
+	}
+	if function := block["similarity_function"].(string); function != "" {
+		options["similarity_function"] = strings.ToUpper(function)
+	}
+	return options
+}
+
+// flattenSAIOptions is the inverse of renderSAIOptions for the options stored
+// in system_schema.indexes. It returns no block when all options are defaults.
+func flattenSAIOptions(options map[string]string) []interface{} {
+	block := map[string]interface{}{
+		"case_sensitive":      !strings.EqualFold(options["case_sensitive"], "false"),
+		"normalize":           strings.EqualFold(options["normalize"], "true"),
+		"ascii":               strings.EqualFold(options["ascii"], "true"),
+		"similarity_function": strings.ToUpper(options["similarity_function"]),
+	}
+	if len(renderSAIOptions([]interface{}{block})) == 0 {
+		return []interface{}{}
+	}
+	return []interface{}{block}
+}
+
+// indexTypeFromClass maps the class_name option of an index to its index_type.
+func indexTypeFromClass(class string) string {
+	if class == "" {
+		return indexTypeSecondary
+	}
+	name := class[strings.LastIndex(class, ".")+1:]
+	for indexType, indexClass := range indexClasses {
+		if name == indexClass[strings.LastIndex(indexClass, ".")+1:] {
+			return indexType
+		}
+	}
+	return indexTypeCustom
+}
+
+func resourceIndexCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if mode := meta.(*ProviderConfig).Mode; mode == modeAWSKeyspaces {
+		return fmt.Errorf("secondary indexes are not supported in %s mode", mode)
+	}
+	indexType := d.Get("index_type").(string)
+	if indexType != indexTypeSAI && len(d.Get("sai").([]interface{})) > 0 {
+		return fmt.Errorf("sai can only be set with index_type %s", indexTypeSAI)
+	}
+	if indexType != indexTypeSASI && indexType != indexTypeCustom && len(d.Get("options").(map[string]interface{})) > 0 {
+		return fmt.Errorf("options can only be set with index_type %s or %s", indexTypeSASI, indexTypeCustom)
+	}
+	if class := d.Get("using_class").(string); (indexType == indexTypeCustom) != (class != "") {
+		return fmt.Errorf("using_class must be set if and only if index_type is %s", indexTypeCustom)
+	}
+	if mode := meta.(*ProviderConfig).Mode; indexType == indexTypeSASI && mode == modeScylla {
+		return fmt.Errorf("%s indexes are not supported in %s mode", indexTypeSASI, mode)
+	}
+	if indexType == indexTypeSAI && d.Id() == "" {
+		return meta.(*ProviderConfig).requireCapability(ctx, "supports_sai", fmt.Sprintf("index_type %s", indexTypeSAI))
+	}
+	return nil
+}
+
+func generateCreateIndexQueryString(index *Index) string {
+	table := index.table()
+	name := ""
+	if index.Name != "" {
+		name = table.identifier(index.Name) + " "
+	}
+	target := table.identifier(index.Column)
+	if index.Kind != "" {
+		target = fmt.Sprintf("%s(%s)", index.Kind, target)
+	}
+
+	class, ok := indexClasses[index.Type]
+	if index.Type == indexTypeCustom {
+		class, ok = index.Class, true
+	}
+	if !ok {
+		return fmt.Sprintf(`CREATE INDEX %sON %s (%s)`, name, table.qualifiedName(), target)
+	}
+
+	query := fmt.Sprintf(`CREATE CUSTOM INDEX %sON %s (%s) USING %s`, name, table.qualifiedName(), target, cql.Literal(class))
+	if len(index.Options) > 0 {
+		query += " WITH OPTIONS = " + cql.Map(index.Options)
+	}
+	return query
+}
+
+func generateDropIndexQueryString(index *Index) string {
+	return fmt.Sprintf(`DROP INDEX %s.%s`, index.table().identifier(index.Keyspace), index.table().identifier(index.Name))
+}
+
+// parseIndexTarget splits the target option of an index as stored in
+// system_schema.indexes, e.g. keys("userId"), into its target_kind and column.
+// Case-sensitive column names are stored quoted.
+func parseIndexTarget(target string) (string, string) {
+	kind := ""
+	for _, k := range indexTargetKinds {
+		prefix := strings.ToLower(k) + "("
+		if strings.HasPrefix(target, prefix) && strings.HasSuffix(target, ")") {
+			kind, target = k, target[len(prefix):len(target)-1]
+			break
+		}
+	}
+	if len(target) >= 2 && strings.HasPrefix(target, `"`) && strings.HasSuffix(target, `"`) {
+		target = strings.ReplaceAll(target[1:len(target)-1], `""`, `"`)
+	}
+	return kind, target
+}
+
+// sameIndexTargetKind compares a configured target_kind with the stored one.
+// Indexes on the column of a list or set are stored as values(column).
+func sameIndexTargetKind(configured, existing string) bool {
+	return configured == existing || configured == "" && existing == "VALUES"
+}
+
+// lookupIndexName returns the stored name of the index on the target of index,
+// used to find the name the cluster generated for an unnamed index.
+func lookupIndexName(ctx context.Context, executor CQLExecutor, index *Index) (string, error) {
+	table := index.table()
+	indexes, err := cqlschema.ReadIndexes(ctx, executor, table.metadataName(index.Keyspace), table.metadataName(index.Table))
+	if err != nil {
+		return "", err
+	}
+	for _, existing := range indexes {
+		if kind, column := parseIndexTarget(existing.Options["target"]); column == table.metadataName(index.Column) && sameIndexTargetKind(index.Kind, kind) {
+			return existing.Name, nil
+		}
+	}
+	return "", fmt.Errorf("index on %s (%s) was created but cannot be found", table.qualifiedName(), index.Column)
+}
+
+func parseIndexID(id string) (string, string, error) {
+	parts := strings.SplitN(id, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("unexpected format of ID (%s), expected keyspace.index", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+func resourceIndexImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	keyspaceName, name, err := parseIndexID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.Set("keyspace", keyspaceName)
+	d.Set("name", name)
+	d.Set("index_type", indexTypeSecondary)
+	d.Set("quote_identifiers", true)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceIndexCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	index := parseIndexData(d)
+	table := index.table()
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := generateCreateIndexQueryString(index)
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Look up the name the cluster picked when none was configured.
+	name := table.metadataName(index.Name)
+	if name == "" {
+		var err error
+		if name, err = lookupIndexName(ctx, executor, index); err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("name", name)
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", index.Keyspace, name))
+	diags = append(diags, warnAfterCreate(resourceIndexRead(ctx, d, meta))...)
+	return diags
+}
+
+func resourceIndexRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	index := parseIndexData(d)
+	table := index.table()
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	indexes, err := cqlschema.ReadIndexes(ctx, executor, table.metadataName(index.Keyspace), "")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var existing *cqlschema.Index
+	for _, candidate := range indexes {
+		if candidate.Name == table.metadataName(index.Name) {
+			existing = candidate
+		}
+	}
+	if existing == nil {
+		log.Printf("Index '%s' in '%s' no longer exists", index.Name, index.Keyspace)
+		d.SetId("")
+		return nil
+	}
+
+	// Keep the configured spelling of names unless they actually differ from
+	// the ones stored by the cluster.
+	if table.metadataName(index.Table) != existing.Table {
+		d.Set("table", existing.Table)
+	}
+	kind, column := parseIndexTarget(existing.Options["target"])
+	if table.metadataName(index.Column) != column {
+		d.Set("column", column)
+	}
+	if !sameIndexTargetKind(index.Kind, kind) {
+		d.Set("target_kind", kind)
+	}
+	class := existing.Options["class_name"]
+	indexType := indexTypeFromClass(class)
+	d.Set("index_type", indexType)
+	if indexType == indexTypeCustom && !sameTableOptionValue("class", index.Class, class) {
+		d.Set("using_class", class)
+	}
+	if indexType == indexTypeSAI && !reflect.DeepEqual(renderSAIOptions(flattenSAIOptions(existing.Options)), index.Options) {
+		d.Set("sai", flattenSAIOptions(existing.Options))
+	}
+	if (indexType == indexTypeSASI || indexType == indexTypeCustom) && !reflect.DeepEqual(cqlschema.CustomIndexOptions(existing.Options), index.Options) {
+		d.Set("options", cqlschema.CustomIndexOptions(existing.Options))
+	}
+	return diags
+}
+
+func resourceIndexDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	index := parseIndexData(d)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := generateDropIndexQueryString(index)
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
@@ -0,0 +1,174 @@
+package cassandra
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestGenerateIndexQueryStrings(t *testing.T) {
+	index := &Index{Keyspace: "some_keyspace", Table: "Users", Column: "email", QuoteIdentifiers: true}
+
+	expected := `CREATE INDEX ON "some_keyspace"."Users" ("email")`
+	if query := generateCreateIndexQueryString(index); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+
+	index.Name = "users_by_email"
+	expected = `CREATE INDEX "users_by_email" ON "some_keyspace"."Users" ("email")`
+	if query := generateCreateIndexQueryString(index); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+	expected = `DROP INDEX "some_keyspace"."users_by_email"`
+	if query := generateDropIndexQueryString(index); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+}
+
+func TestParseIndexTarget(t *testing.T) {
+	for target, expected := range map[string][2]string{
+		"email":            {"", "email"},
+		`"userEmail"`:      {"", "userEmail"},
+		`"say ""hi"""`:     {"", `say "hi"`},
+		"keys(attributes)": {"KEYS", "attributes"},
+		`entries("Tags")`:  {"ENTRIES", "Tags"},
+		"full(frozen_set)": {"FULL", "frozen_set"},
+	} {
+		if kind, column := parseIndexTarget(target); kind != expected[0] || column != expected[1] {
+			t.Fatalf("expected %v for target %q, got %q, %q", expected, target, kind, column)
+		}
+	}
+}
+
+func TestGenerateIndexQueryStringTargetKind(t *testing.T) {
+	index := &Index{Keyspace: "some_keyspace", Table: "users", Column: "Attributes", Kind: "KEYS", QuoteIdentifiers: true}
+
+	expected := `CREATE INDEX ON "some_keyspace"."users" (KEYS("Attributes"))`
+	if query := generateCreateIndexQueryString(index); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+}
+
+func TestGenerateSAIIndexQueryString(t *testing.T) {
+	index := &Index{
+		Keyspace:         "some_keyspace",
+		Table:            "products",
+		Column:           "embedding",
+		Name:             "products_ann",
+		Type:             indexTypeSAI,
+		Options:          renderSAIOptions([]interface{}{map[string]interface{}{"case_sensitive": true, "normalize": false, "ascii": false, "similarity_function": "cosine"}}),
+		QuoteIdentifiers: true,
+	}
+
+	expected := `CREATE CUSTOM INDEX "products_ann" ON "some_keyspace"."products" ("embedding") USING 'StorageAttachedIndex' WITH OPTIONS = {'similarity_function':'COSINE'}`
+	if query := generateCreateIndexQueryString(index); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+
+	index.Options = nil
+	expected = `CREATE CUSTOM INDEX "products_ann" ON "some_keyspace"."products" ("embedding") USING 'StorageAttachedIndex'`
+	if query := generateCreateIndexQueryString(index); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+}
+
+func TestFlattenSAIOptions(t *testing.T) {
+	if blocks := flattenSAIOptions(map[string]string{"target": "name", "class_name": "org.apache.cassandra.index.sai.StorageAttachedIndex"}); len(blocks) != 0 {
+		t.Fatalf("expected no block for default options, got %v", blocks)
+	}
+
+	options := map[string]string{"case_sensitive": "false", "normalize": "true"}
+	rendered := renderSAIOptions(flattenSAIOptions(options))
+	if len(rendered) != 2 || rendered["case_sensitive"] != "false" || rendered["normalize"] != "true" {
+		t.Fatalf("expected options to round-trip, got %v", rendered)
+	}
+
+	if indexType := indexTypeFromClass("org.apache.cassandra.index.sai.StorageAttachedIndex"); indexType != indexTypeSAI {
+		t.Fatalf("expected the SAI class to map to %s, got %q", indexTypeSAI, indexType)
+	}
+}
+
+func TestGenerateSASIIndexQueryString(t *testing.T) {
+	index := &Index{
+		Keyspace: "some_keyspace",
+		Table:    "users",
+		Column:   "name",
+		Name:     "users_by_name",
+		Type:     indexTypeSASI,
+		Options: map[string]string{
+			"mode":           "CONTAINS",
+			"analyzer_class": "org.apache.cassandra.index.sasi.analyzer.NonTokenizingAnalyzer",
+			"case_sensitive": "false",
+		},
+	}
+
+	expected := `CREATE CUSTOM INDEX users_by_name ON some_keyspace.users (name) USING 'org.apache.cassandra.index.sasi.SASIIndex' WITH OPTIONS = {'analyzer_class':'org.apache.cassandra.index.sasi.analyzer.NonTokenizingAnalyzer', 'case_sensitive':'false', 'mode':'CONTAINS'}`
+	if query := generateCreateIndexQueryString(index); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+
+	if indexType := indexTypeFromClass("org.apache.cassandra.index.sasi.SASIIndex"); indexType != indexTypeSASI {
+		t.Fatalf("expected the SASI class to map to %s, got %q", indexTypeSASI, indexType)
+	}
+}
+
+func TestGenerateCustomIndexQueryString(t *testing.T) {
+	index := &Index{
+		Keyspace: "some_keyspace",
+		Table:    "tweets",
+		Column:   "body",
+		Name:     "tweets_lucene",
+		Type:     indexTypeCustom,
+		Class:    "com.stratio.cassandra.lucene.Index",
+		Options:  map[string]string{"refresh_seconds": "1"},
+	}
+
+	expected := `CREATE CUSTOM INDEX tweets_lucene ON some_keyspace.tweets (body) USING 'com.stratio.cassandra.lucene.Index' WITH OPTIONS = {'refresh_seconds':'1'}`
+	if query := generateCreateIndexQueryString(index); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+
+	if indexType := indexTypeFromClass(index.Class); indexType != indexTypeCustom {
+		t.Fatalf("expected an unknown class to map to %s, got %q", indexTypeCustom, indexType)
+	}
+	if indexType := indexTypeFromClass(""); indexType != indexTypeSecondary {
+		t.Fatalf("expected no class to map to %s, got %q", indexTypeSecondary, indexType)
+	}
+}
+
+func TestResourceIndexLifecycle(t *testing.T) {
+	executor := newMockCQLExecutor()
+	d := schema.TestResourceDataRaw(t, resourceCassandraIndex().Schema, map[string]interface{}{
+		"keyspace": "app",
+		"table":    "events",
+		"column":   "day",
+	})
+	generated := []map[string]interface{}{
+		{"index_name": "events_day_idx", "table_name": "events", "kind": "COMPOSITES", "options": map[string]string{"target": "day"}},
+	}
+	executor.rows[`SELECT index_name, table_name, kind, options FROM system_schema.indexes WHERE keyspace_name = ? AND table_name = ? [app events]`] = generated
+	executor.rows[`SELECT index_name, table_name, kind, options FROM system_schema.indexes WHERE keyspace_name = ? [app]`] = generated
+
+	if diags := resourceIndexCreate(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if d.Id() != "app.events_day_idx" || d.Get("name").(string) != "events_day_idx" {
+		t.Fatalf("expected the generated name to be looked up, got ID %s", d.Id())
+	}
+	if diags := resourceIndexDelete(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(executor.executed) != 2 || !strings.HasPrefix(executor.executed[0], "CREATE INDEX ") || !strings.HasPrefix(executor.executed[1], "DROP INDEX ") {
+		t.Fatalf("expected CREATE INDEX and DROP INDEX, got %v", executor.executed)
+	}
+
+	executor.rows = map[string][]map[string]interface{}{}
+	if diags := resourceIndexRead(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if d.Id() != "" {
+		t.Fatal("expected the index to be gone")
+	}
+}
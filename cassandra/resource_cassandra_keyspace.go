@@ -6,14 +6,17 @@ import (
 	"log"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
-	"time"
 
 	"github.com/gocql/gocql"
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+	"github.com/konradotto/terraform-provider-cassandra/cqlschema"
 )
 
 const (
@@ -26,18 +29,44 @@ var (
 		true:  "CREATE",
 		false: "ALTER",
 	}
+
+	// systemKeyspaces are internal to Cassandra, ScyllaDB or Amazon Keyspaces
+	// and must never be managed, let alone dropped.
+	systemKeyspaces = []string{
+		"system",
+		"system_auth",
+		"system_distributed",
+		"system_distributed_everywhere",
+		"system_schema",
+		"system_traces",
+		"system_views",
+		"system_virtual_schema",
+		"system_schema_mcs",
+		"system_multiregion_info",
+	}
 )
 
+func isSystemKeyspace(name string) bool {
+	for _, systemKeyspace := range systemKeyspaces {
+		if strings.EqualFold(name, systemKeyspace) {
+			return true
+		}
+	}
+	return false
+}
+
 func resourceCassandraKeyspace() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
 		Description:   "Manage Keyspaces within your cassandra cluster",
 		CreateContext: resourceKeyspaceCreate,
 		ReadContext:   resourceKeyspaceRead,
 		UpdateContext: resourceKeyspaceUpdate,
 		DeleteContext: resourceKeyspaceDelete,
+		CustomizeDiff: resourceKeyspaceCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStateWithIdentity([]string{"name"}, nameID, resourceKeyspaceImport),
 		},
+		Identity: stringIdentity([]string{"name"}, nil),
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -56,12 +85,12 @@ func resourceCassandraKeyspace() *schema.Resource {
 							},
 						}
 					}
-					if name == "system" {
+					if isSystemKeyspace(name) {
 						return diag.Diagnostics{
 							{
 								Severity:      diag.Error,
-								Summary:       "Cannot manage 'system' keyspace",
-								Detail:        "cannot manage 'system' keyspace, it is internal to Cassandra",
+								Summary:       fmt.Sprintf("Cannot manage '%s' keyspace", name),
+								Detail:        fmt.Sprintf("cannot manage '%s' keyspace, it is internal to Cassandra", name),
 								AttributePath: path,
 							},
 						}
@@ -71,101 +100,342 @@ func resourceCassandraKeyspace() *schema.Resource {
 			},
 			"replication_strategy": {
 				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "Keyspace replication strategy - must be one of SimpleStrategy or NetworkTopologyStrategy",
+				Optional:     true,
+				ExactlyOneOf: []string{"replication_strategy", "replication_strategy_class"},
+				Description:  "Keyspace replication strategy - must be one of SimpleStrategy or NetworkTopologyStrategy. Changes are applied in place with ALTER KEYSPACE",
 				ValidateFunc: validation.StringInSlice([]string{"SimpleStrategy", "NetworkTopologyStrategy", "SingleRegionStrategy"}, false),
 			},
+			"replication_strategy_class": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Replication strategy class passed through as-is, e.g. a custom or EverywhereStrategy class. Alternative to replication_strategy",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
 			"strategy_options": {
-				Type:        schema.TypeMap,
-				Required:    true,
-				Description: "strategy options used with replication strategy",
+				Type:         schema.TypeMap,
+				Optional:     true,
+				ExactlyOneOf: []string{"strategy_options", "datacenters"},
+				Description:  "strategy options used with replication strategy, e.g. { dc1 = \"3\" } or { dc1 = \"3/1\" } for 3 replicas of which 1 is transient (Cassandra 4.0+)",
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
-				StateFunc: func(v interface{}) string {
-					strategyOptions := v.(map[string]interface{})
-					keys := make([]string, 0, len(strategyOptions))
-					for key, value := range strategyOptions {
-						strValue := value.(string)
-						keys = append(keys, fmt.Sprintf("%q=%q", key, strValue))
-					}
-					sort.Strings(keys)
-					return hash(strings.Join(keys, ", "))
+			},
+			"datacenters": {
+				Type:             schema.TypeMap,
+				Optional:         true,
+				Description:      "Replication factor per datacenter, e.g. { dc1 = \"3\", dc2 = \"3\" } or { dc1 = \"3/1\" } for 3 replicas of which 1 is transient (Cassandra 4.0+) - requires NetworkTopologyStrategy. Alternative to strategy_options",
+				ValidateDiagFunc: validation.MapValueMatch(replicationFactorRegex, "must be a number of replicas or replicas/transient replicas, e.g. 3 or 3/1"),
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
 				},
 			},
 			"durable_writes": {
 				Type:        schema.TypeBool,
 				Optional:    true,
-				Description: "Enable or disable durable writes - disabling is not recommended",
+				Description: "Enable or disable durable writes - disabling is not recommended. Changes are applied in place with ALTER KEYSPACE",
 				Default:     true,
 			},
+			"tablets": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Description: "Tablets settings of the keyspace - only supported in scylla mode",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"enabled": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							ForceNew:    true,
+							Default:     true,
+							Description: "Use tablets instead of vnodes for the tables of the keyspace",
+						},
+						"initial": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ForceNew:     true,
+							Description:  "Initial number of tablets per table, chosen by ScyllaDB if unset",
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+					},
+				},
+			},
+			"deletion_protection": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Prevent the keyspace from being dropped - must be set to false and applied before the keyspace can be destroyed",
+			},
+			"allow_existing": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Adopt an existing keyspace with the same name instead of failing, altering its replication and durable writes to match",
+			},
+			"force_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Drop the keyspace even if it still contains tables",
+			},
+			"repair_required": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the most recent apply altered the replication of the keyspace, so a full repair is needed to re-replicate existing data",
+			},
+			"validate_topology": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Check during plan that every NetworkTopologyStrategy datacenter exists and has at least as many nodes as its replication factor",
+			},
+			"tags": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Resource tags of the keyspace - only supported in aws_keyspaces mode",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"quote_identifiers": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Double-quote the keyspace name in generated CQL, keeping its case. Set to false to use an unquoted, case-insensitive identifier",
+			},
 		},
 	}
+	resource.SchemaVersion = 1
+	resource.StateUpgraders = []schema.StateUpgrader{{
+		Version: 0,
+		Type:    resource.CoreConfigSchema().ImpliedType(),
+		Upgrade: resourceKeyspaceStateUpgradeV0,
+	}}
+	return resource
+}
+
+// resourceKeyspaceStateUpgradeV0 keeps the keyspaces of state version 0 that
+// do not record quote_identifiers case-sensitive, as their names were quoted
+// whenever their case required it.
+func resourceKeyspaceStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if _, ok := rawState["quote_identifiers"].(bool); !ok {
+		rawState["quote_identifiers"] = true
+	}
+	return rawState, nil
+}
+
+// keyspaceTable returns a Table for the keyspace name, used to render it.
+func keyspaceTable(name string, d attributeGetter) *Table {
+	return &Table{Keyspace: name, QuoteIdentifiers: d.Get("quote_identifiers").(bool)}
+}
+
+// validateKeyspaceQuoteIdentifiersChange refuses to change quote_identifiers
+// of an existing keyspace if that changes the name it is stored under, e.g.
+// for a keyspace created unquoted as Analytics, which Cassandra stored as
+// analytics.
+func validateKeyspaceQuoteIdentifiersChange(old, new *Table) error {
+	if old.metadataName(new.Keyspace) == new.metadataName(new.Keyspace) {
+		return nil
+	}
+	return fmt.Errorf("changing quote_identifiers of keyspace %s would address it by a different name than %s, which it is stored under. Set quote_identifiers = %t to keep managing the keyspace", new.Keyspace, old.metadataName(new.Keyspace), old.QuoteIdentifiers)
+}
+
+// keyspaceReplicationStrategy returns the configured replication strategy,
+// preferring a passed-through replication_strategy_class.
+func keyspaceReplicationStrategy(d attributeGetter) string {
+	if class, ok := d.Get("replication_strategy_class").(string); ok && class != "" {
+		return class
+	}
+	return d.Get("replication_strategy").(string)
+}
+
+// keyspaceStrategyOptions returns the replication options of a keyspace,
+// taken from strategy_options or the datacenters map.
+func keyspaceStrategyOptions(d attributeGetter) map[string]interface{} {
+	if datacenters := d.Get("datacenters").(map[string]interface{}); len(datacenters) > 0 {
+		return datacenters
+	}
+	return d.Get("strategy_options").(map[string]interface{})
+}
+
+// parseDatacenters converts the replication options read from the cluster into
+// a datacenters map, keeping the replication factors as stored, e.g. 3/1.
+func parseDatacenters(strategyOptions map[string]string) map[string]string {
+	datacenters := make(map[string]string, len(strategyOptions))
+	for key, value := range strategyOptions {
+		if key != "replication_factor" {
+			datacenters[key] = value
+		}
+	}
+	return datacenters
+}
+
+// clusterDatacenterNodeCounts returns the number of nodes per datacenter as
+// seen by the coordinator in system.local and system.peers.
+func clusterDatacenterNodeCounts(ctx context.Context, executor CQLExecutor) (map[string]int, error) {
+	nodeCounts := map[string]int{}
+	for _, query := range []string{`SELECT data_center FROM system.local`, `SELECT data_center FROM system.peers`} {
+		rows, err := executor.Select(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			datacenter, _ := row["data_center"].(string)
+			nodeCounts[datacenter]++
+		}
+	}
+	return nodeCounts, nil
+}
+
+// validateReplicationTopology checks NetworkTopologyStrategy options against
+// the nodes per datacenter. Replication factors may use the transient
+// replication notation, e.g. 3/1.
+func validateReplicationTopology(strategyOptions map[string]interface{}, nodeCounts map[string]int) error {
+	datacenters := make([]string, 0, len(strategyOptions))
+	for datacenter := range strategyOptions {
+		datacenters = append(datacenters, datacenter)
+	}
+	sort.Strings(datacenters)
+
+	for _, datacenter := range datacenters {
+		if datacenter == "replication_factor" {
+			continue
+		}
+		value := strategyOptions[datacenter].(string)
+		replicationFactor, _, err := parseReplicationFactor(value)
+		if err != nil {
+			return fmt.Errorf("invalid replication factor %s for datacenter %s: %s", value, datacenter, err)
+		}
+		nodeCount, ok := nodeCounts[datacenter]
+		if !ok && replicationFactor > 0 {
+			return fmt.Errorf("datacenter %s does not exist in the cluster", datacenter)
+		}
+		if replicationFactor > nodeCount {
+			return fmt.Errorf("replication factor %d of datacenter %s exceeds its %d node(s)", replicationFactor, datacenter, nodeCount)
+		}
+	}
+	return nil
+}
+
+// renderKeyspaceTablets renders the ScyllaDB tablets options as a CQL map
+// literal, or returns an empty string if tablets are not configured.
+func renderKeyspaceTablets(d attributeGetter) string {
+	tablets := d.Get("tablets").([]interface{})
+	if len(tablets) == 0 || tablets[0] == nil {
+		return ""
+	}
+
+	settings := tablets[0].(map[string]interface{})
+	options := []string{fmt.Sprintf(`'enabled': %t`, settings["enabled"].(bool))}
+	if initial := settings["initial"].(int); settings["enabled"].(bool) && initial > 0 {
+		options = append(options, fmt.Sprintf(`'initial': %d`, initial))
+	}
+	return fmt.Sprintf("{%s}", strings.Join(options, ", "))
 }
 
-func generateCreateOrUpdateKeyspaceQueryString(name string, create bool, replicationStrategy string, strategyOptions map[string]interface{}, durableWrites bool) (string, error) {
+// keyspaceMatchesMetadata reports whether an existing keyspace already has the
+// configured replication and durable writes.
+func keyspaceMatchesMetadata(replicationStrategy string, strategyOptions map[string]interface{}, durableWrites bool, keyspaceMetadata *gocql.KeyspaceMetadata) bool {
+	strategyClass, existingOptions := cqlschema.Replication(keyspaceMetadata)
+	if (strategyClass != replicationStrategy && keyspaceMetadata.StrategyClass != replicationStrategy) || keyspaceMetadata.DurableWrites != durableWrites || len(existingOptions) != len(strategyOptions) {
+		return false
+	}
+	for key, value := range strategyOptions {
+		if existingOptions[key] != value.(string) {
+			return false
+		}
+	}
+	return true
+}
+
+func generateCreateOrUpdateKeyspaceQueryString(keyspace *Table, create bool, replicationStrategy string, strategyOptions map[string]interface{}, durableWrites bool, tags map[string]string) (string, error) {
 	if len(strategyOptions) == 0 {
 		return "", fmt.Errorf("must specify strategy options - see https://docs.datastax.com/en/cql/3.3/cql/cql_reference/cqlCreateKeyspace.html")
 	}
 
-	query := fmt.Sprintf(`%s KEYSPACE %s WITH REPLICATION = { 'class' : '%s'`, boolToAction[create], name, replicationStrategy)
-	for key, value := range strategyOptions {
-		query += fmt.Sprintf(`, '%s' : '%s'`, key, value.(string))
+	query := fmt.Sprintf(`%s KEYSPACE %s WITH REPLICATION = %s`, boolToAction[create], keyspace.identifier(keyspace.Keyspace), cqlschema.RenderReplication(replicationStrategy, mapToStringMap(strategyOptions)))
+	query += fmt.Sprintf(` AND DURABLE_WRITES = %t`, durableWrites)
+	if create && len(tags) > 0 {
+		query += fmt.Sprintf(` AND TAGS = %s`, cql.Map(tags))
 	}
-	query += fmt.Sprintf(` } AND DURABLE_WRITES = %t`, durableWrites)
 	log.Println("query", query)
 	return query, nil
 }
 
 func resourceKeyspaceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	name := d.Get("name").(string)
-	replicationStrategy := d.Get("replication_strategy").(string)
-	strategyOptions := d.Get("strategy_options").(map[string]interface{})
+	replicationStrategy := keyspaceReplicationStrategy(d)
+	strategyOptions := keyspaceStrategyOptions(d)
 	durableWrites := d.Get("durable_writes").(bool)
 	var diags diag.Diagnostics
 
-	query, err := generateCreateOrUpdateKeyspaceQueryString(name, true, replicationStrategy, strategyOptions, durableWrites)
+	providerConfig := meta.(*ProviderConfig)
+	tags := map[string]string{}
+	if providerConfig.Mode == modeAWSKeyspaces {
+		tags = mapToStringMap(d.Get("tags"))
+	}
+
+	keyspace := keyspaceTable(name, d)
+	query, err := generateCreateOrUpdateKeyspaceQueryString(keyspace, true, replicationStrategy, strategyOptions, durableWrites, tags)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-
-	providerConfig := meta.(*ProviderConfig)
-	cluster := providerConfig.Cluster
-	start := time.Now()
-	session, sessionCreateError := cluster.CreateSession()
-	elapsed := time.Since(start)
-	log.Printf("Getting a session took %s", elapsed)
-	if sessionCreateError != nil {
-		return diag.FromErr(sessionCreateError)
+	if tablets := renderKeyspaceTablets(d); tablets != "" {
+		query += fmt.Sprintf(` AND TABLETS = %s`, tablets)
 	}
-	defer session.Close()
 
-	err = session.Query(query).Exec()
+	executor, err := providerConfig.newExecutor()
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	defer executor.Close()
+
+	repairRequired := false
+	if d.Get("allow_existing").(bool) {
+		keyspaceMetadata, err := executor.KeyspaceMetadata(keyspace.metadataName(name))
+		if err != nil && err != gocql.ErrKeyspaceDoesNotExist {
+			return diag.FromErr(err)
+		}
+		if err == nil {
+			log.Printf("Adopting existing keyspace '%s'", name)
+			query = ""
+			if !keyspaceMatchesMetadata(replicationStrategy, strategyOptions, durableWrites, keyspaceMetadata) {
+				query, err = generateCreateOrUpdateKeyspaceQueryString(keyspace, false, replicationStrategy, strategyOptions, durableWrites, nil)
+				if err != nil {
+					return diag.FromErr(err)
+				}
+				repairRequired = true
+				diags = append(diags, repairRequiredDiagnostic(name))
+			}
+		}
+	}
+
+	if query != "" {
+		err = executor.ExecSchemaChange(ctx, query, defaultSchemaChangeTimeout)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
 
 	d.SetId(name)
-	diags = append(diags, resourceKeyspaceRead(ctx, d, meta)...)
+	d.Set("repair_required", repairRequired)
+	if err := setIdentity(d, "name"); err != nil {
+		return diag.FromErr(err)
+	}
+	diags = append(diags, warnAfterCreate(resourceKeyspaceRead(ctx, d, meta))...)
 	return diags
 }
 
 func resourceKeyspaceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	name := d.Id()
 	providerConfig := meta.(*ProviderConfig)
-	cluster := providerConfig.Cluster
 	var diags diag.Diagnostics
 
-	start := time.Now()
-	session, sessionCreateError := cluster.CreateSession()
-	elapsed := time.Since(start)
-	log.Printf("Getting a session took %s", elapsed)
-	if sessionCreateError != nil {
-		return diag.FromErr(sessionCreateError)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
 	}
-	defer session.Close()
+	defer executor.Close()
 
-	keyspaceMetadata, err := session.KeyspaceMetadata(name)
+	keyspaceMetadata, err := executor.KeyspaceMetadata(keyspaceTable(name, d).metadataName(name))
 	if err == gocql.ErrKeyspaceDoesNotExist {
 		d.SetId("")
 		return nil
@@ -173,35 +443,121 @@ func resourceKeyspaceRead(ctx context.Context, d *schema.ResourceData, meta inte
 		return diag.FromErr(err)
 	}
 
-	strategyOptions := make(map[string]string)
-	for key, value := range keyspaceMetadata.StrategyOptions {
-		strategyOptions[key] = value.(string)
+	strategyClass, strategyOptions := cqlschema.Replication(keyspaceMetadata)
+	d.Set("name", name)
+	if err := setIdentity(d, "name"); err != nil {
+		return diag.FromErr(err)
+	}
+	if class := d.Get("replication_strategy_class").(string); class != "" {
+		if class != strategyClass && class != keyspaceMetadata.StrategyClass {
+			d.Set("replication_strategy_class", keyspaceMetadata.StrategyClass)
+		}
+	} else {
+		d.Set("replication_strategy", strategyClass)
 	}
+	d.Set("durable_writes", keyspaceMetadata.DurableWrites)
+	if len(d.Get("datacenters").(map[string]interface{})) > 0 {
+		d.Set("datacenters", parseDatacenters(strategyOptions))
+	} else {
+		d.Set("strategy_options", strategyOptions)
+	}
+	return diags
+}
+
+// resourceKeyspaceImport reads the replication and durable_writes of the
+// keyspace named by the import id from the keyspaces table of the schema
+// keyspace. Arguments the cluster does not store are set to their defaults,
+// so importing a keyspace configured with them plans no changes.
+func resourceKeyspaceImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	name := d.Id()
+	if !keyspaceRegex.MatchString(name) {
+		return nil, fmt.Errorf("invalid keyspace name %s - must match %s", name, keyspaceLiteralPattern)
+	}
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return nil, err
+	}
+	defer executor.Close()
+
+	rows, err := executor.Select(ctx, fmt.Sprintf(`SELECT replication, durable_writes FROM %s.keyspaces WHERE keyspace_name = ?`, providerConfig.schemaKeyspace()), name)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("keyspace %s does not exist", name)
+	}
+	replication, _ := rows[0]["replication"].(map[string]string)
+	durableWrites, _ := rows[0]["durable_writes"].(bool)
 
-	strategyClass := strings.TrimPrefix(keyspaceMetadata.StrategyClass, "org.apache.cassandra.locator.")
+	if err := setSchemaDefaults(d, resourceCassandraKeyspace().Schema); err != nil {
+		return nil, err
+	}
+	strategyOptions := make(map[string]string, len(replication))
+	for key, value := range replication {
+		if key != "class" {
+			strategyOptions[key] = value
+		}
+	}
 	d.Set("name", name)
-	d.Set("replication_strategy", strategyClass)
-	d.Set("durable_writes", keyspaceMetadata.DurableWrites)
+	d.Set("replication_strategy", strings.TrimPrefix(replication["class"], "org.apache.cassandra.locator."))
 	d.Set("strategy_options", strategyOptions)
-	return diags
+	d.Set("durable_writes", durableWrites)
+	return []*schema.ResourceData{d}, nil
+}
+
+// setSchemaDefaults sets the attributes of attributes that have a default to
+// it, as creating a resource does for arguments left out of its
+// configuration.
+func setSchemaDefaults(d *schema.ResourceData, attributes map[string]*schema.Schema) error {
+	for key, attribute := range attributes {
+		if attribute.Default == nil {
+			continue
+		}
+		if err := d.Set(key, attribute.Default); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func resourceKeyspaceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	name := d.Get("name").(string)
-	providerConfig := meta.(*ProviderConfig)
-	cluster := providerConfig.Cluster
 	var diags diag.Diagnostics
 
-	start := time.Now()
-	session, sessionCreateError := cluster.CreateSession()
-	elapsed := time.Since(start)
-	log.Printf("Getting a session took %s", elapsed)
-	if sessionCreateError != nil {
-		return diag.FromErr(sessionCreateError)
+	if isSystemKeyspace(name) {
+		return diag.Errorf("refusing to drop keyspace %s: it is internal to the cluster", name)
+	}
+	if d.Get("deletion_protection").(bool) {
+		return diag.Errorf("cannot drop keyspace %s: deletion_protection is enabled - set it to false and apply before destroying", name)
 	}
-	defer session.Close()
 
-	err := session.Query(fmt.Sprintf(`DROP KEYSPACE %s`, name)).Exec()
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	keyspace := keyspaceTable(name, d)
+	if !d.Get("force_destroy").(bool) {
+		keyspaceMetadata, err := executor.KeyspaceMetadata(keyspace.metadataName(name))
+		if err == gocql.ErrKeyspaceDoesNotExist {
+			log.Printf("Keyspace %s was already dropped", name)
+			d.SetId("")
+			return diags
+		} else if err != nil {
+			return diag.FromErr(err)
+		}
+		if tables, _ := flattenKeyspaceTables(keyspaceMetadata.Tables); len(tables) > 0 {
+			return diag.Errorf("cannot drop keyspace %s: it still contains tables %s - remove them or set force_destroy = true", name, strings.Join(tables, ", "))
+		}
+	}
+
+	// force_destroy skips the existence check, so a keyspace dropped outside
+	// of Terraform is only tolerated by IF EXISTS.
+	err = executor.ExecSchemaChange(ctx, fmt.Sprintf(`DROP KEYSPACE IF EXISTS %s`, keyspace.identifier(name)), defaultSchemaChangeTimeout)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -210,31 +566,187 @@ func resourceKeyspaceDelete(ctx context.Context, d *schema.ResourceData, meta in
 
 func resourceKeyspaceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	name := d.Get("name").(string)
-	replicationStrategy := d.Get("replication_strategy").(string)
-	strategyOptions := d.Get("strategy_options").(map[string]interface{})
+	replicationStrategy := keyspaceReplicationStrategy(d)
+	strategyOptions := keyspaceStrategyOptions(d)
 	durableWrites := d.Get("durable_writes").(bool)
 	var diags diag.Diagnostics
 
-	query, err := generateCreateOrUpdateKeyspaceQueryString(name, false, replicationStrategy, strategyOptions, durableWrites)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
 	providerConfig := meta.(*ProviderConfig)
-	cluster := providerConfig.Cluster
-	start := time.Now()
-	session, sessionCreateError := cluster.CreateSession()
-	elapsed := time.Since(start)
-	log.Printf("Getting a session took %s", elapsed)
-	if sessionCreateError != nil {
-		return diag.FromErr(sessionCreateError)
+	keyspace := keyspaceTable(name, d)
+	queries := []string{}
+	if d.HasChanges("replication_strategy", "replication_strategy_class", "strategy_options", "datacenters", "durable_writes") {
+		query, err := generateCreateOrUpdateKeyspaceQueryString(keyspace, false, replicationStrategy, strategyOptions, durableWrites, nil)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		queries = append(queries, query)
+	}
+	if providerConfig.Mode == modeAWSKeyspaces && d.HasChange("tags") {
+		oldTags, newTags := d.GetChange("tags")
+		queries = append(queries, generateAlterTagsQueryStrings("KEYSPACE "+keyspace.identifier(name), mapToStringMap(oldTags), mapToStringMap(newTags))...)
 	}
-	defer session.Close()
 
-	err = session.Query(query).Exec()
+	executor, err := providerConfig.newExecutor()
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	defer executor.Close()
+
+	for _, query := range queries {
+		log.Printf("Executing query: %s", query)
+		if err := executor.ExecSchemaChange(ctx, query, defaultSchemaChangeTimeout); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	repairRequired := d.HasChanges("replication_strategy", "replication_strategy_class", "strategy_options", "datacenters")
+	if repairRequired {
+		diags = append(diags, repairRequiredDiagnostic(name))
+	}
+	d.Set("repair_required", repairRequired)
 	diags = append(diags, resourceKeyspaceRead(ctx, d, meta)...)
 	return diags
 }
+
+// repairRequiredDiagnostic reminds operators that altering the replication of
+// a keyspace does not move existing data to its new replicas.
+func repairRequiredDiagnostic(name string) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  "Replication of keyspace changed",
+		Detail:   fmt.Sprintf("The replication of keyspace %s was altered in place. Run a full repair (nodetool repair --full %s) so existing data is streamed to its new replicas.", name, name),
+	}
+}
+
+// customizeRepairRequired plans repair_required for updates: true when the
+// replication changes, false for any other change.
+func customizeRepairRequired(d *schema.ResourceDiff) error {
+	if d.Id() == "" {
+		return nil
+	}
+	if d.HasChanges("replication_strategy", "replication_strategy_class", "strategy_options", "datacenters") {
+		return d.SetNew("repair_required", true)
+	}
+	if d.Get("repair_required").(bool) && len(d.GetChangedKeysPrefix("")) > 0 {
+		return d.SetNew("repair_required", false)
+	}
+	return nil
+}
+
+// validateKeyspaceDatacenters checks that a datacenters map is only used with
+// NetworkTopologyStrategy. Its replication factors are checked by
+// validateTransientReplication, like those of strategy_options.
+func validateKeyspaceDatacenters(d attributeGetter) error {
+	if len(d.Get("datacenters").(map[string]interface{})) == 0 {
+		return nil
+	}
+	if replicationStrategy := keyspaceReplicationStrategy(d); replicationStrategy != "NetworkTopologyStrategy" {
+		return fmt.Errorf("datacenters requires replication_strategy NetworkTopologyStrategy, got %s", replicationStrategy)
+	}
+	return nil
+}
+
+// parseReplicationFactor parses a replication factor, either a number of
+// replicas or the transient replication notation N/T of Cassandra 4.0, N
+// replicas of which T are transient. It returns the replicas and the
+// transient replicas.
+func parseReplicationFactor(value string) (int, int, error) {
+	parts := strings.SplitN(value, "/", 2)
+	replicas, err := strconv.Atoi(parts[0])
+	if err != nil || replicas < 0 {
+		return 0, 0, fmt.Errorf("expected a number of replicas or replicas/transient replicas, e.g. 3/1")
+	}
+	if len(parts) == 1 {
+		return replicas, 0, nil
+	}
+	transientReplicas, err := strconv.Atoi(parts[1])
+	if err != nil || transientReplicas < 0 {
+		return 0, 0, fmt.Errorf("expected a number of transient replicas after /")
+	}
+	if transientReplicas >= replicas {
+		return 0, 0, fmt.Errorf("at least one of the %d replicas must be a full replica", replicas)
+	}
+	return replicas, transientReplicas, nil
+}
+
+// validateTransientReplication checks the replication factors of
+// SimpleStrategy and NetworkTopologyStrategy keyspaces, requiring a cluster
+// with transient replication enabled when they declare transient replicas.
+func validateTransientReplication(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if replicationStrategy := keyspaceReplicationStrategy(d); replicationStrategy != "SimpleStrategy" && replicationStrategy != "NetworkTopologyStrategy" {
+		return nil
+	}
+	if !d.NewValueKnown("strategy_options") || !d.NewValueKnown("datacenters") {
+		return nil
+	}
+	if d.Id() != "" && !d.HasChanges("replication_strategy", "replication_strategy_class", "strategy_options", "datacenters") {
+		return nil
+	}
+
+	strategyOptions := keyspaceStrategyOptions(d)
+	keys := make([]string, 0, len(strategyOptions))
+	for key := range strategyOptions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	transient := ""
+	for _, key := range keys {
+		value := strategyOptions[key].(string)
+		_, transientReplicas, err := parseReplicationFactor(value)
+		if err != nil {
+			return fmt.Errorf("invalid replication factor %s of %s: %s", value, key, err)
+		}
+		if transientReplicas > 0 && transient == "" {
+			transient = fmt.Sprintf("replication factor %s of %s", value, key)
+		}
+	}
+	if transient == "" {
+		return nil
+	}
+	return meta.(*ProviderConfig).requireCapability(ctx, "supports_transient_replication", transient)
+}
+
+func resourceKeyspaceCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if meta.(*ProviderConfig).Mode != modeAWSKeyspaces && len(d.Get("tags").(map[string]interface{})) > 0 {
+		return fmt.Errorf("tags are only supported in %s mode", modeAWSKeyspaces)
+	}
+	if meta.(*ProviderConfig).Mode != modeScylla && len(d.Get("tablets").([]interface{})) > 0 {
+		return fmt.Errorf("tablets are only supported in %s mode", modeScylla)
+	}
+	if err := validateKeyspaceDatacenters(d); err != nil {
+		return err
+	}
+	if d.Id() != "" && d.HasChange("quote_identifiers") {
+		name := d.Get("name").(string)
+		if err := validateKeyspaceQuoteIdentifiersChange(keyspaceTable(name, oldValueGetter{d}), keyspaceTable(name, d)); err != nil {
+			return err
+		}
+	}
+	if err := validateTransientReplication(ctx, d, meta); err != nil {
+		return err
+	}
+	if err := customizeRepairRequired(d); err != nil {
+		return err
+	}
+
+	if !d.Get("validate_topology").(bool) || keyspaceReplicationStrategy(d) != "NetworkTopologyStrategy" {
+		return nil
+	}
+	if !d.NewValueKnown("strategy_options") || !d.NewValueKnown("datacenters") {
+		return nil
+	}
+	if d.Id() != "" && !d.HasChanges("replication_strategy", "replication_strategy_class", "strategy_options", "datacenters", "validate_topology") {
+		return nil
+	}
+	executor, err := meta.(*ProviderConfig).newExecutor()
+	if err != nil {
+		return err
+	}
+	defer executor.Close()
+
+	nodeCounts, err := clusterDatacenterNodeCounts(ctx, executor)
+	if err != nil {
+		return fmt.Errorf("cannot read the cluster topology: %s", err)
+	}
+	return validateReplicationTopology(keyspaceStrategyOptions(d), nodeCounts)
+}
@@ -0,0 +1,174 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+	"github.com/konradotto/terraform-provider-cassandra/cqlschema"
+)
+
+func resourceCassandraKeyspaceReplication() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Manage only the replication of a keyspace created elsewhere, e.g. by an application migration tool. Destroying the resource leaves the replication in place",
+		CreateContext: resourceKeyspaceReplicationCreate,
+		ReadContext:   resourceKeyspaceReplicationRead,
+		UpdateContext: resourceKeyspaceReplicationUpdate,
+		DeleteContext: resourceKeyspaceReplicationDelete,
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if err := validateKeyspaceDatacenters(d); err != nil {
+				return err
+			}
+			if err := validateTransientReplication(ctx, d, meta); err != nil {
+				return err
+			}
+			return customizeRepairRequired(d)
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"keyspace": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the keyspace whose replication is managed",
+				ValidateFunc: func(i interface{}, k string) ([]string, []error) {
+					if name := i.(string); isSystemKeyspace(name) {
+						return nil, []error{fmt.Errorf("%s: cannot manage '%s' keyspace, it is internal to Cassandra", k, name)}
+					}
+					return nil, nil
+				},
+			},
+			"replication_strategy": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Keyspace replication strategy - must be one of SimpleStrategy or NetworkTopologyStrategy",
+				ValidateFunc: validation.StringInSlice([]string{"SimpleStrategy", "NetworkTopologyStrategy", "SingleRegionStrategy"}, false),
+			},
+			"strategy_options": {
+				Type:         schema.TypeMap,
+				Optional:     true,
+				ExactlyOneOf: []string{"strategy_options", "datacenters"},
+				Description:  "strategy options used with replication strategy, e.g. { dc1 = \"3\" } or { dc1 = \"3/1\" } for 3 replicas of which 1 is transient (Cassandra 4.0+)",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"datacenters": {
+				Type:             schema.TypeMap,
+				Optional:         true,
+				Description:      "Replication factor per datacenter, e.g. { dc1 = \"3\", dc2 = \"3\" } or { dc1 = \"3/1\" } for 3 replicas of which 1 is transient (Cassandra 4.0+) - requires NetworkTopologyStrategy. Alternative to strategy_options",
+				ValidateDiagFunc: validation.MapValueMatch(replicationFactorRegex, "must be a number of replicas or replicas/transient replicas, e.g. 3 or 3/1"),
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"repair_required": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the most recent apply altered the replication of the keyspace, so a full repair is needed to re-replicate existing data",
+			},
+		},
+	}
+}
+
+func generateAlterKeyspaceReplicationQueryString(name string, replicationStrategy string, strategyOptions map[string]interface{}) (string, error) {
+	if len(strategyOptions) == 0 {
+		return "", fmt.Errorf("must specify strategy options - see https://docs.datastax.com/en/cql/3.3/cql/cql_reference/cqlCreateKeyspace.html")
+	}
+	return fmt.Sprintf(`ALTER KEYSPACE %s WITH REPLICATION = %s`, cql.Identifier(name), cqlschema.RenderReplication(replicationStrategy, mapToStringMap(strategyOptions))), nil
+}
+
+func alterKeyspaceReplication(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("keyspace").(string)
+	var diags diag.Diagnostics
+
+	query, err := generateAlterKeyspaceReplicationQueryString(name, d.Get("replication_strategy").(string), keyspaceStrategyOptions(d))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	keyspaceMetadata, err := executor.KeyspaceMetadata(name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if keyspaceMatchesMetadata(d.Get("replication_strategy").(string), keyspaceStrategyOptions(d), keyspaceMetadata.DurableWrites, keyspaceMetadata) {
+		log.Printf("Replication of keyspace '%s' already matches", name)
+		d.Set("repair_required", false)
+		return diags
+	}
+
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, defaultSchemaChangeTimeout); err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("repair_required", true)
+	return append(diags, repairRequiredDiagnostic(name))
+}
+
+func resourceKeyspaceReplicationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	diags := alterKeyspaceReplication(ctx, d, meta)
+	if diags.HasError() {
+		return diags
+	}
+
+	d.SetId(d.Get("keyspace").(string))
+	return append(diags, warnAfterCreate(resourceKeyspaceReplicationRead(ctx, d, meta))...)
+}
+
+func resourceKeyspaceReplicationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Id()
+	providerConfig := meta.(*ProviderConfig)
+	var diags diag.Diagnostics
+
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	keyspaceMetadata, err := executor.KeyspaceMetadata(name)
+	if err == gocql.ErrKeyspaceDoesNotExist {
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return diag.FromErr(err)
+	}
+
+	strategyClass, strategyOptions := cqlschema.Replication(keyspaceMetadata)
+	d.Set("keyspace", name)
+	d.Set("replication_strategy", strategyClass)
+	if len(d.Get("datacenters").(map[string]interface{})) > 0 {
+		d.Set("datacenters", parseDatacenters(strategyOptions))
+	} else {
+		d.Set("strategy_options", strategyOptions)
+	}
+	return diags
+}
+
+func resourceKeyspaceReplicationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	diags := alterKeyspaceReplication(ctx, d, meta)
+	if diags.HasError() {
+		return diags
+	}
+	return append(diags, resourceKeyspaceReplicationRead(ctx, d, meta)...)
+}
+
+func resourceKeyspaceReplicationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("Leaving replication of keyspace '%s' in place", d.Get("keyspace").(string))
+	return nil
+}
@@ -0,0 +1,18 @@
+package cassandra
+
+import "testing"
+
+func TestGenerateAlterKeyspaceReplicationQueryString(t *testing.T) {
+	query, err := generateAlterKeyspaceReplicationQueryString("some_keyspace", "NetworkTopologyStrategy", map[string]interface{}{"dc2": "3", "dc1": "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "ALTER KEYSPACE some_keyspace WITH REPLICATION = { 'class' : 'NetworkTopologyStrategy', 'dc1' : '3', 'dc2' : '3' }"
+	if query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+
+	if _, err := generateAlterKeyspaceReplicationQueryString("some_keyspace", "SimpleStrategy", map[string]interface{}{}); err == nil {
+		t.Fatal("expected an error without strategy options")
+	}
+}
@@ -1,17 +1,23 @@
 package cassandra
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gocql/gocql"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccCassandraKeyspace_basic(t *testing.T) {
-	keyspace := "some_keyspace"
+	keyspace := testAccResourcePrefix + "keyspace"
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -37,8 +43,34 @@ func TestAccCassandraKeyspace_basic(t *testing.T) {
 	})
 }
 
+func TestAccCassandraKeyspace_import(t *testing.T) {
+	keyspace := "imported_keyspace"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCassandraKeyspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCassandraKeyspaceConfigImport(keyspace),
+				Check:  testAccCassandraKeyspaceExists("cassandra_keyspace.keyspace"),
+			},
+			{
+				ResourceName:      "cassandra_keyspace.keyspace",
+				ImportStateId:     keyspace,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config:   testAccCassandraKeyspaceConfigImport(keyspace),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
 func TestAccCassandraKeyspace_broken(t *testing.T) {
-	keyspace := "some_keyspace"
+	keyspace := testAccResourcePrefix + "keyspace"
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -53,6 +85,38 @@ func TestAccCassandraKeyspace_broken(t *testing.T) {
 	})
 }
 
+func TestGenerateCreateOrUpdateKeyspaceQueryString_tags(t *testing.T) {
+	query, err := generateCreateOrUpdateKeyspaceQueryString(&Table{Keyspace: "some_keyspace", QuoteIdentifiers: true}, true, "SingleRegionStrategy", map[string]interface{}{}, true, map[string]string{"team": "data", "env": "prod"})
+	if err == nil {
+		t.Fatalf("expected an error without strategy options, got %q", query)
+	}
+
+	query, err = generateCreateOrUpdateKeyspaceQueryString(&Table{Keyspace: "some_keyspace", QuoteIdentifiers: true}, true, "SimpleStrategy", map[string]interface{}{"replication_factor": "1"}, true, map[string]string{"team": "data", "env": "prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `CREATE KEYSPACE "some_keyspace" WITH REPLICATION = { 'class' : 'SimpleStrategy', 'replication_factor' : '1' } AND DURABLE_WRITES = true AND TAGS = {'env':'prod', 'team':'data'}`
+	if query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+}
+
+func TestGenerateAlterTagsQueryStrings(t *testing.T) {
+	queries := generateAlterTagsQueryStrings("KEYSPACE some_keyspace", map[string]string{"team": "data", "env": "dev"}, map[string]string{"team": "data", "owner": "ops"})
+	expected := []string{
+		"ALTER KEYSPACE some_keyspace DROP TAGS {'env':'dev'}",
+		"ALTER KEYSPACE some_keyspace ADD TAGS {'owner':'ops'}",
+	}
+	if len(queries) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, queries)
+	}
+	for i := range expected {
+		if queries[i] != expected[i] {
+			t.Fatalf("expected %q, got %q", expected[i], queries[i])
+		}
+	}
+}
+
 func testAccCassandraKeyspaceConfigBasic(keyspace string) string {
 	return fmt.Sprintf(`
 resource "cassandra_keyspace" "keyspace" {
@@ -65,6 +129,19 @@ resource "cassandra_keyspace" "keyspace" {
 `, keyspace)
 }
 
+func testAccCassandraKeyspaceConfigImport(keyspace string) string {
+	return fmt.Sprintf(`
+resource "cassandra_keyspace" "keyspace" {
+    name                 = "%s"
+    replication_strategy = "NetworkTopologyStrategy"
+    strategy_options     = {
+      datacenter1 = 1
+    }
+    durable_writes       = false
+}
+`, keyspace)
+}
+
 func testAccCassandraKeyspaceConfigBroken(keyspace string) string {
 	return fmt.Sprintf(`
 resource "cassandra_keyspace" "keyspace" {
@@ -125,3 +202,495 @@ func testAccCassandraKeyspaceExists(resourceKey string) resource.TestCheckFunc {
 		return nil
 	}
 }
+
+func TestGenerateCreateOrUpdateKeyspaceQueryString_alterReplication(t *testing.T) {
+	query, err := generateCreateOrUpdateKeyspaceQueryString(&Table{Keyspace: "some_keyspace", QuoteIdentifiers: true}, false, "NetworkTopologyStrategy", map[string]interface{}{"dc2": "3", "dc1": "3"}, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `ALTER KEYSPACE "some_keyspace" WITH REPLICATION = { 'class' : 'NetworkTopologyStrategy', 'dc1' : '3', 'dc2' : '3' } AND DURABLE_WRITES = true`
+	if query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+}
+
+func TestKeyspaceStrategyOptions_datacenters(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCassandraKeyspace().Schema, map[string]interface{}{
+		"name":                 "some_keyspace",
+		"replication_strategy": "NetworkTopologyStrategy",
+		"datacenters":          map[string]interface{}{"dc1": "3", "dc2": "2/1"},
+	})
+
+	query, err := generateCreateOrUpdateKeyspaceQueryString(&Table{Keyspace: "some_keyspace", QuoteIdentifiers: true}, true, "NetworkTopologyStrategy", keyspaceStrategyOptions(d), true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `CREATE KEYSPACE "some_keyspace" WITH REPLICATION = { 'class' : 'NetworkTopologyStrategy', 'dc1' : '3', 'dc2' : '2/1' } AND DURABLE_WRITES = true`
+	if query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+
+	datacenters := parseDatacenters(map[string]string{"dc1": "3", "dc2": "2/1", "replication_factor": "3"})
+	if len(datacenters) != 2 || datacenters["dc1"] != "3" || datacenters["dc2"] != "2/1" {
+		t.Fatalf("unexpected datacenters %v", datacenters)
+	}
+}
+
+func TestKeyspaceDatacenters_validation(t *testing.T) {
+	validate := resourceCassandraKeyspace().Schema["datacenters"].ValidateDiagFunc
+	for value, valid := range map[string]bool{"3": true, "3/1": true, "x": false, "3/": false, "-1": false} {
+		diags := validate(map[string]interface{}{"dc1": value}, cty.Path{cty.GetAttrStep{Name: "datacenters"}})
+		if diags.HasError() == valid {
+			t.Fatalf("%s: expected valid %t, got %v", value, valid, diags)
+		}
+	}
+}
+
+func TestGenerateCreateOrUpdateKeyspaceQueryString_durableWrites(t *testing.T) {
+	query, err := generateCreateOrUpdateKeyspaceQueryString(&Table{Keyspace: "some_keyspace", QuoteIdentifiers: true}, false, "SimpleStrategy", map[string]interface{}{"replication_factor": "1"}, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `ALTER KEYSPACE "some_keyspace" WITH REPLICATION = { 'class' : 'SimpleStrategy', 'replication_factor' : '1' } AND DURABLE_WRITES = false`
+	if query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+}
+
+func TestGenerateCreateOrUpdateKeyspaceQueryString_quotedName(t *testing.T) {
+	query, err := generateCreateOrUpdateKeyspaceQueryString(&Table{Keyspace: "Analytics", QuoteIdentifiers: true}, true, "SimpleStrategy", map[string]interface{}{"replication_factor": "1"}, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `CREATE KEYSPACE "Analytics" WITH REPLICATION = { 'class' : 'SimpleStrategy', 'replication_factor' : '1' } AND DURABLE_WRITES = true`
+	if query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+}
+
+func TestSetSchemaDefaults(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCassandraKeyspace().Schema, map[string]interface{}{})
+	if err := setSchemaDefaults(d, resourceCassandraKeyspace().Schema); err != nil {
+		t.Fatal(err)
+	}
+	if durableWrites, ok := d.GetOk("durable_writes"); !ok || !durableWrites.(bool) {
+		t.Fatalf("expected durable_writes to default to true, got %v", durableWrites)
+	}
+	if _, ok := d.GetOk("tags"); ok {
+		t.Fatal("expected tags without a default to stay unset")
+	}
+}
+
+func TestValidateReplicationTopology(t *testing.T) {
+	nodeCounts := map[string]int{"dc1": 3, "dc2": 1}
+
+	if err := validateReplicationTopology(map[string]interface{}{"dc1": "3", "dc2": "1"}, nodeCounts); err != nil {
+		t.Fatalf("expected replication to fit the topology, got %s", err)
+	}
+	if err := validateReplicationTopology(map[string]interface{}{"dc1": "3/1"}, nodeCounts); err != nil {
+		t.Fatalf("expected transient replication to be accepted, got %s", err)
+	}
+	if err := validateReplicationTopology(map[string]interface{}{"dc3": "1"}, nodeCounts); err == nil {
+		t.Fatal("expected an unknown datacenter to be rejected")
+	}
+	if err := validateReplicationTopology(map[string]interface{}{"dc2": "3"}, nodeCounts); err == nil {
+		t.Fatal("expected a replication factor above the node count to be rejected")
+	}
+}
+
+func TestParseReplicationFactor(t *testing.T) {
+	for _, test := range []struct {
+		value             string
+		replicas          int
+		transientReplicas int
+		valid             bool
+	}{
+		{"3", 3, 0, true},
+		{"0", 0, 0, true},
+		{"3/1", 3, 1, true},
+		{"5/2", 5, 2, true},
+		{"3/0", 3, 0, true},
+		{"3/3", 0, 0, false},
+		{"1/2", 0, 0, false},
+		{"-1", 0, 0, false},
+		{"3/", 0, 0, false},
+		{"three", 0, 0, false},
+	} {
+		replicas, transientReplicas, err := parseReplicationFactor(test.value)
+		if (err == nil) != test.valid {
+			t.Fatalf("%s: expected valid %t, got %v", test.value, test.valid, err)
+		}
+		if replicas != test.replicas || transientReplicas != test.transientReplicas {
+			t.Fatalf("%s: expected %d/%d, got %d/%d", test.value, test.replicas, test.transientReplicas, replicas, transientReplicas)
+		}
+	}
+}
+
+func TestRenderKeyspaceTablets(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCassandraKeyspace().Schema, map[string]interface{}{
+		"name":                 "some_keyspace",
+		"replication_strategy": "NetworkTopologyStrategy",
+		"datacenters":          map[string]interface{}{"dc1": "3"},
+		"tablets":              []interface{}{map[string]interface{}{"enabled": true, "initial": 8}},
+	})
+	if tablets := renderKeyspaceTablets(d); tablets != "{'enabled': true, 'initial': 8}" {
+		t.Fatalf("unexpected tablets %q", tablets)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceCassandraKeyspace().Schema, map[string]interface{}{
+		"name":                 "some_keyspace",
+		"replication_strategy": "NetworkTopologyStrategy",
+		"datacenters":          map[string]interface{}{"dc1": "3"},
+	})
+	if tablets := renderKeyspaceTablets(d); tablets != "" {
+		t.Fatalf("expected no tablets, got %q", tablets)
+	}
+}
+
+func TestResourceKeyspaceDelete_guards(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCassandraKeyspace().Schema, map[string]interface{}{
+		"name":                 "some_keyspace",
+		"replication_strategy": "SimpleStrategy",
+		"strategy_options":     map[string]interface{}{"replication_factor": "1"},
+		"deletion_protection":  true,
+	})
+	if diags := resourceKeyspaceDelete(context.Background(), d, nil); !diags.HasError() {
+		t.Fatal("expected deletion_protection to prevent the keyspace from being dropped")
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceCassandraKeyspace().Schema, map[string]interface{}{
+		"name":                 "system_auth",
+		"replication_strategy": "SimpleStrategy",
+		"strategy_options":     map[string]interface{}{"replication_factor": "1"},
+	})
+	if diags := resourceKeyspaceDelete(context.Background(), d, nil); !diags.HasError() {
+		t.Fatal("expected a system keyspace never to be dropped")
+	}
+}
+
+func TestKeyspaceMatchesMetadata(t *testing.T) {
+	metadata := &gocql.KeyspaceMetadata{
+		Name:            "some_keyspace",
+		DurableWrites:   true,
+		StrategyClass:   "org.apache.cassandra.locator.SimpleStrategy",
+		StrategyOptions: map[string]interface{}{"replication_factor": "1"},
+	}
+
+	if !keyspaceMatchesMetadata("SimpleStrategy", map[string]interface{}{"replication_factor": "1"}, true, metadata) {
+		t.Fatal("expected the existing keyspace to match")
+	}
+	if keyspaceMatchesMetadata("SimpleStrategy", map[string]interface{}{"replication_factor": "3"}, true, metadata) {
+		t.Fatal("expected a different replication factor to be detected")
+	}
+	if keyspaceMatchesMetadata("SimpleStrategy", map[string]interface{}{"replication_factor": "1"}, false, metadata) {
+		t.Fatal("expected different durable writes to be detected")
+	}
+}
+
+func TestKeyspaceReplicationStrategy_customClass(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCassandraKeyspace().Schema, map[string]interface{}{
+		"name":                       "some_keyspace",
+		"replication_strategy_class": "org.apache.cassandra.locator.EverywhereStrategy",
+		"strategy_options":           map[string]interface{}{"dc1": "3/1"},
+	})
+
+	query, err := generateCreateOrUpdateKeyspaceQueryString(&Table{Keyspace: "some_keyspace", QuoteIdentifiers: true}, true, keyspaceReplicationStrategy(d), keyspaceStrategyOptions(d), true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `CREATE KEYSPACE "some_keyspace" WITH REPLICATION = { 'class' : 'org.apache.cassandra.locator.EverywhereStrategy', 'dc1' : '3/1' } AND DURABLE_WRITES = true`
+	if query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+}
+
+func TestResourceKeyspaceLifecycle(t *testing.T) {
+	executor := newMockCQLExecutor()
+	d := testResourceDataWithIdentity(t, resourceCassandraKeyspace(), map[string]interface{}{
+		"name":                 "app",
+		"replication_strategy": "SimpleStrategy",
+		"strategy_options":     map[string]interface{}{"replication_factor": "1"},
+	})
+	executor.keyspaces["app"] = &gocql.KeyspaceMetadata{
+		Name:            "app",
+		DurableWrites:   true,
+		StrategyClass:   "org.apache.cassandra.locator.SimpleStrategy",
+		StrategyOptions: map[string]interface{}{"replication_factor": "3"},
+	}
+
+	if diags := resourceKeyspaceCreate(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	expected := []string{`CREATE KEYSPACE "app" WITH REPLICATION = { 'class' : 'SimpleStrategy', 'replication_factor' : '1' } AND DURABLE_WRITES = true`}
+	if !reflect.DeepEqual(executor.executed, expected) {
+		t.Fatalf("expected %v, got %v", expected, executor.executed)
+	}
+	if d.Id() != "app" {
+		t.Fatalf("unexpected ID %s", d.Id())
+	}
+	if replicationFactor := d.Get("strategy_options.replication_factor").(string); replicationFactor != "3" {
+		t.Fatalf("expected the replication factor stored by the cluster to be read, got %s", replicationFactor)
+	}
+
+	executor.keyspaces["app"].Tables = map[string]*gocql.TableMetadata{"events": {Name: "events"}}
+	if diags := resourceKeyspaceDelete(context.Background(), d, executor.providerConfig()); !diags.HasError() {
+		t.Fatal("expected a keyspace with tables not to be dropped")
+	}
+
+	delete(executor.keyspaces, "app")
+	if diags := resourceKeyspaceRead(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if d.Id() != "" {
+		t.Fatal("expected the keyspace to be gone")
+	}
+	if len(executor.executed) != 1 || executor.closed != 4 {
+		t.Fatalf("expected no more statements and every executor closed, got %v and %d closed", executor.executed, executor.closed)
+	}
+}
+
+func TestResourceKeyspaceCreate_allowExisting(t *testing.T) {
+	executor := newMockCQLExecutor()
+	d := testResourceDataWithIdentity(t, resourceCassandraKeyspace(), map[string]interface{}{
+		"name":                 "app",
+		"replication_strategy": "SimpleStrategy",
+		"strategy_options":     map[string]interface{}{"replication_factor": "3"},
+		"allow_existing":       true,
+	})
+	executor.keyspaces["app"] = &gocql.KeyspaceMetadata{
+		Name:            "app",
+		DurableWrites:   true,
+		StrategyClass:   "org.apache.cassandra.locator.SimpleStrategy",
+		StrategyOptions: map[string]interface{}{"replication_factor": "3"},
+	}
+
+	if diags := resourceKeyspaceCreate(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(executor.executed) != 0 {
+		t.Fatalf("expected a matching keyspace to be adopted as is, got %v", executor.executed)
+	}
+	if d.Get("repair_required").(bool) {
+		t.Fatal("expected no repair to be required")
+	}
+}
+
+func TestResourceKeyspaceImport(t *testing.T) {
+	executor := newMockCQLExecutor()
+	executor.rows[`SELECT replication, durable_writes FROM system_schema.keyspaces WHERE keyspace_name = ? [app]`] = []map[string]interface{}{{
+		"replication":    map[string]string{"class": "org.apache.cassandra.locator.NetworkTopologyStrategy", "datacenter1": "3"},
+		"durable_writes": false,
+	}}
+
+	d := schema.TestResourceDataRaw(t, resourceCassandraKeyspace().Schema, map[string]interface{}{})
+	d.SetId("app")
+	if _, err := resourceKeyspaceImport(context.Background(), d, executor.providerConfig()); err != nil {
+		t.Fatal(err)
+	}
+	if strategy := d.Get("replication_strategy").(string); strategy != "NetworkTopologyStrategy" {
+		t.Fatalf("unexpected replication_strategy %s", strategy)
+	}
+	if replicationFactor := d.Get("strategy_options.datacenter1").(string); replicationFactor != "3" {
+		t.Fatalf("unexpected replication factor %s", replicationFactor)
+	}
+	if d.Get("durable_writes").(bool) {
+		t.Fatal("expected durable_writes to be read")
+	}
+
+	d.SetId("missing")
+	if _, err := resourceKeyspaceImport(context.Background(), d, executor.providerConfig()); err == nil {
+		t.Fatal("expected importing a missing keyspace to fail")
+	}
+}
+
+func TestResourceKeyspaceImport_awsKeyspaces(t *testing.T) {
+	executor := newMockCQLExecutor()
+	executor.rows[`SELECT replication, durable_writes FROM system_schema_mcs.keyspaces WHERE keyspace_name = ? [app]`] = []map[string]interface{}{{
+		"replication":    map[string]string{"class": "org.apache.cassandra.locator.SingleRegionStrategy"},
+		"durable_writes": true,
+	}}
+	providerConfig := executor.providerConfig()
+	providerConfig.Mode = modeAWSKeyspaces
+
+	d := schema.TestResourceDataRaw(t, resourceCassandraKeyspace().Schema, map[string]interface{}{})
+	d.SetId("app")
+	if _, err := resourceKeyspaceImport(context.Background(), d, providerConfig); err != nil {
+		t.Fatal(err)
+	}
+	if strategy := d.Get("replication_strategy").(string); strategy != "SingleRegionStrategy" {
+		t.Fatalf("unexpected replication_strategy %s", strategy)
+	}
+}
+
+func TestResourceKeyspaceDelete_alreadyDropped(t *testing.T) {
+	executor := newMockCQLExecutor()
+	d := schema.TestResourceDataRaw(t, resourceCassandraKeyspace().Schema, map[string]interface{}{
+		"name":                 "app",
+		"replication_strategy": "SimpleStrategy",
+		"strategy_options":     map[string]interface{}{"replication_factor": "1"},
+	})
+	d.SetId("app")
+
+	if diags := resourceKeyspaceDelete(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if d.Id() != "" || len(executor.executed) != 0 {
+		t.Fatalf("expected the dropped keyspace to be forgotten without statements, got %v", executor.executed)
+	}
+}
+
+func TestResourceKeyspaceDelete_forceDestroy(t *testing.T) {
+	executor := newMockCQLExecutor()
+	d := schema.TestResourceDataRaw(t, resourceCassandraKeyspace().Schema, map[string]interface{}{
+		"name":                 "app",
+		"replication_strategy": "SimpleStrategy",
+		"strategy_options":     map[string]interface{}{"replication_factor": "1"},
+		"force_destroy":        true,
+	})
+	d.SetId("app")
+
+	if diags := resourceKeyspaceDelete(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(executor.executed) != 1 || executor.executed[0] != `DROP KEYSPACE IF EXISTS "app"` {
+		t.Fatalf("expected the keyspace to be dropped if it still exists, got %v", executor.executed)
+	}
+}
+
+// TestMixedCaseKeyspaceAndTable creates a keyspace MyKs with a table Events
+// and checks that the keyspace, table, column, index and table options
+// resources address them by the same stored names, whether quote_identifiers
+// keeps their case or folds it.
+func TestMixedCaseKeyspaceAndTable(t *testing.T) {
+	for _, test := range []struct {
+		quoteIdentifiers bool
+		keyspace         string
+		table            string
+		column           string
+		expected         []string
+	}{
+		{
+			quoteIdentifiers: true,
+			keyspace:         "MyKs",
+			table:            "Events",
+			column:           "Day",
+			expected: []string{
+				`CREATE KEYSPACE "MyKs" WITH REPLICATION = { 'class' : 'SimpleStrategy', 'replication_factor' : '1' } AND DURABLE_WRITES = true`,
+				`CREATE TABLE "MyKs"."Events" ("Id" uuid, "Day" date, PRIMARY KEY (("Id")))`,
+				`CREATE INDEX ON "MyKs"."Events" ("Day")`,
+			},
+		},
+		{
+			quoteIdentifiers: false,
+			keyspace:         "myks",
+			table:            "events",
+			column:           "day",
+			expected: []string{
+				`CREATE KEYSPACE MyKs WITH REPLICATION = { 'class' : 'SimpleStrategy', 'replication_factor' : '1' } AND DURABLE_WRITES = true`,
+				`CREATE TABLE MyKs.Events (Id uuid, Day date, PRIMARY KEY ((Id)))`,
+				`CREATE INDEX ON MyKs.Events (Day)`,
+			},
+		},
+	} {
+		executor := newMockCQLExecutor()
+		providerConfig := executor.providerConfig()
+		executor.keyspaces[test.keyspace] = &gocql.KeyspaceMetadata{
+			Name:            test.keyspace,
+			DurableWrites:   true,
+			StrategyClass:   "org.apache.cassandra.locator.SimpleStrategy",
+			StrategyOptions: map[string]interface{}{"replication_factor": "1"},
+			Tables: map[string]*gocql.TableMetadata{test.table: {
+				Name:    test.table,
+				Columns: map[string]*gocql.ColumnMetadata{test.column: {Name: test.column, Validator: "date", Kind: gocql.ColumnRegular}},
+			}},
+		}
+		indexes := []map[string]interface{}{{"index_name": "events_day_idx", "table_name": test.table, "kind": "COMPOSITES", "options": map[string]string{"target": test.column}}}
+		executor.rows[fmt.Sprintf("SELECT index_name, table_name, kind, options FROM system_schema.indexes WHERE keyspace_name = ? AND table_name = ? [%s %s]", test.keyspace, test.table)] = indexes
+		executor.rows[fmt.Sprintf("SELECT index_name, table_name, kind, options FROM system_schema.indexes WHERE keyspace_name = ? [%s]", test.keyspace)] = indexes
+		executor.rows[fmt.Sprintf("SELECT comment, default_time_to_live, gc_grace_seconds, speculative_retry, caching, compaction, compression FROM system_schema.tables WHERE keyspace_name = ? AND table_name = ? [%s %s]", test.keyspace, test.table)] = []map[string]interface{}{{"comment": "events"}}
+
+		keyspace := testResourceDataWithIdentity(t, resourceCassandraKeyspace(), map[string]interface{}{
+			"name":                 "MyKs",
+			"replication_strategy": "SimpleStrategy",
+			"strategy_options":     map[string]interface{}{"replication_factor": "1"},
+			"quote_identifiers":    test.quoteIdentifiers,
+		})
+		if diags := resourceKeyspaceCreate(context.Background(), keyspace, providerConfig); diags.HasError() || keyspace.Id() != "MyKs" {
+			t.Fatalf("expected keyspace MyKs to be created and read, got ID %q and %v", keyspace.Id(), diags)
+		}
+
+		table := &Table{
+			Keyspace:         "MyKs",
+			Name:             "Events",
+			Columns:          []TableColumn{{Name: "Id", Type: "uuid"}, {Name: "Day", Type: "date"}},
+			RowKeys:          []string{"Id"},
+			QuoteIdentifiers: test.quoteIdentifiers,
+		}
+		query, err := generateCreateTableQueryString(table)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := executor.Exec(context.Background(), query); err != nil {
+			t.Fatal(err)
+		}
+		if err := waitForTableVisible(context.Background(), executor, table, time.Second); err != nil {
+			t.Fatalf("expected table Events to be visible: %s", err)
+		}
+
+		column := schema.TestResourceDataRaw(t, resourceCassandraTableColumn().Schema, map[string]interface{}{
+			"keyspace":          "MyKs",
+			"table":             "Events",
+			"name":              "Day",
+			"type":              "date",
+			"quote_identifiers": test.quoteIdentifiers,
+		})
+		column.SetId("MyKs.Events.Day")
+		if diags := resourceTableColumnRead(context.Background(), column, providerConfig); diags.HasError() || column.Id() == "" {
+			t.Fatalf("expected column Day to be found, got %v", diags)
+		}
+
+		index := schema.TestResourceDataRaw(t, resourceCassandraIndex().Schema, map[string]interface{}{
+			"keyspace":          "MyKs",
+			"table":             "Events",
+			"column":            "Day",
+			"quote_identifiers": test.quoteIdentifiers,
+		})
+		if diags := resourceIndexCreate(context.Background(), index, providerConfig); diags.HasError() || index.Id() != "MyKs.events_day_idx" {
+			t.Fatalf("expected index on Day to be found, got ID %q and %v", index.Id(), diags)
+		}
+
+		options := schema.TestResourceDataRaw(t, resourceCassandraTableOptions().Schema, map[string]interface{}{
+			"keyspace":          "MyKs",
+			"table":             "Events",
+			"quote_identifiers": test.quoteIdentifiers,
+		})
+		options.SetId("MyKs.Events")
+		if diags := resourceTableOptionsRead(context.Background(), options, providerConfig); diags.HasError() || options.Id() == "" || options.Get("comment").(string) != "events" {
+			t.Fatalf("expected the options of Events to be read, got %v", diags)
+		}
+
+		if !reflect.DeepEqual(executor.executed, test.expected) {
+			t.Fatalf("quote_identifiers = %t: expected %q, got %q", test.quoteIdentifiers, test.expected, executor.executed)
+		}
+	}
+}
+
+func TestResourceKeyspaceStateUpgradeV0(t *testing.T) {
+	state, err := resourceKeyspaceStateUpgradeV0(context.Background(), map[string]interface{}{"name": "Analytics"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state["quote_identifiers"] != true {
+		t.Fatalf("expected a keyspace without quote_identifiers to keep its case, got %v", state["quote_identifiers"])
+	}
+}
+
+func TestValidateKeyspaceQuoteIdentifiersChange(t *testing.T) {
+	if err := validateKeyspaceQuoteIdentifiersChange(&Table{Keyspace: "app", QuoteIdentifiers: true}, &Table{Keyspace: "app"}); err != nil {
+		t.Fatalf("expected a lower-case keyspace to be unquoted in place, got %s", err)
+	}
+	err := validateKeyspaceQuoteIdentifiersChange(&Table{Keyspace: "Analytics", QuoteIdentifiers: true}, &Table{Keyspace: "Analytics"})
+	if err == nil || !strings.Contains(err.Error(), "than Analytics,") {
+		t.Fatalf("expected unquoting a keyspace stored as Analytics to be refused, got %v", err)
+	}
+}
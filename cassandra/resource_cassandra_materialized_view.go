@@ -0,0 +1,351 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/konradotto/terraform-provider-cassandra/cqlschema"
+)
+
+func resourceCassandraMaterializedView() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Create and Delete Materialized Views of Tables",
+		CreateContext: resourceMaterializedViewCreate,
+		ReadContext:   resourceMaterializedViewRead,
+		UpdateContext: resourceMaterializedViewUpdate,
+		DeleteContext: resourceMaterializedViewDelete,
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			providerConfig := meta.(*ProviderConfig)
+			if mode := providerConfig.Mode; mode == modeAWSKeyspaces {
+				return fmt.Errorf("materialized views are not supported in %s mode", mode)
+			}
+			if d.Id() == "" {
+				return providerConfig.requireCapability(ctx, "supports_mvs", "cassandra_materialized_view")
+			}
+			return nil
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceMaterializedViewImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"keyspace": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Keyspace of the base table, the view is created within it",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Name of the view - must contain between 1 and 256 characters",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"base_table": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Name of the table the view selects from",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"columns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Columns of the base table selected into the view. Primary key columns are always included. Selects all columns when empty",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"partition_keys": {
+				Type:        schema.TypeList,
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				Description: "Columns of the partition key of the view, in order",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"clustering_keys": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Clustering columns of the view, in order. Must include every primary key column of the base table not used in partition_keys",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"where_clause": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Filter of the view, without the WHERE keyword. Defaults to an IS NOT NULL restriction on every primary key column of the view",
+			},
+			"options": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Table options of the view as CQL literals, e.g. { comment = \"'by email'\", gc_grace_seconds = \"3600\" }. Options are applied but not read back",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"quote_identifiers": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Double-quote view, table and column names in generated CQL, keeping their case. Set to false to use unquoted, case-insensitive identifiers",
+			},
+		},
+	}
+}
+
+// MaterializedView holds everything needed to render the DDL of a cassandra_materialized_view.
+type MaterializedView struct {
+	Keyspace       string
+	Name           string
+	BaseTable      string
+	Columns        []string
+	PartitionKeys  []string
+	ClusteringKeys []string
+	WhereClause    string
+	Options        map[string]string
+
+	QuoteIdentifiers bool
+}
+
+// table returns a Table for the view itself, used to render identifiers.
+func (v *MaterializedView) table() *Table {
+	return &Table{Keyspace: v.Keyspace, Name: v.Name, QuoteIdentifiers: v.QuoteIdentifiers}
+}
+
+func (v *MaterializedView) keys() []string {
+	return append(append([]string{}, v.PartitionKeys...), v.ClusteringKeys...)
+}
+
+func parseMaterializedViewData(d attributeGetter) *MaterializedView {
+	return &MaterializedView{
+		Keyspace:       d.Get("keyspace").(string),
+		Name:           d.Get("name").(string),
+		BaseTable:      d.Get("base_table").(string),
+		Columns:        listToArray(d.Get("columns")),
+		PartitionKeys:  listToArray(d.Get("partition_keys")),
+		ClusteringKeys: listToArray(d.Get("clustering_keys")),
+		WhereClause:    d.Get("where_clause").(string),
+		Options:        mapToStringMap(d.Get("options")),
+
+		QuoteIdentifiers: d.Get("quote_identifiers").(bool),
+	}
+}
+
+func generateCreateMaterializedViewQueryString(view *MaterializedView) string {
+	table := view.table()
+
+	selection := "*"
+	if len(view.Columns) > 0 {
+		selection = strings.Join(table.identifiers(view.Columns), ", ")
+	}
+
+	where := view.WhereClause
+	if where == "" {
+		restrictions := make([]string, 0, len(view.keys()))
+		for _, key := range table.identifiers(view.keys()) {
+			restrictions = append(restrictions, key+" IS NOT NULL")
+		}
+		where = strings.Join(restrictions, " AND ")
+	}
+
+	primaryKey := fmt.Sprintf("PRIMARY KEY ((%s)", strings.Join(table.identifiers(view.PartitionKeys), ", "))
+	if len(view.ClusteringKeys) > 0 {
+		primaryKey += ", " + strings.Join(table.identifiers(view.ClusteringKeys), ", ")
+	}
+
+	query := fmt.Sprintf(`CREATE MATERIALIZED VIEW %s AS SELECT %s FROM %s.%s WHERE %s %s)`,
+		table.qualifiedName(), selection, table.identifier(view.Keyspace), table.identifier(view.BaseTable), where, primaryKey)
+	if len(view.Options) > 0 {
+		query += " WITH " + renderTableProperties(view.Options)
+	}
+	return query
+}
+
+func generateDropMaterializedViewQueryString(view *MaterializedView) string {
+	return fmt.Sprintf(`DROP MATERIALIZED VIEW %s`, view.table().qualifiedName())
+}
+
+// normalizeWhereClause makes a WHERE clause comparable with the one stored in
+// system_schema.views, which drops quotes and rewrites whitespace and keywords.
+func normalizeWhereClause(clause string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.ReplaceAll(clause, `"`, ""))), " ")
+}
+
+func sameStringList(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func metadataNames(table *Table, names []string) []string {
+	ret := make([]string, 0, len(names))
+	for _, name := range names {
+		ret = append(ret, table.metadataName(name))
+	}
+	return ret
+}
+
+// selectedColumnsMatch tells whether the configured selection, which may or
+// may not repeat the primary key columns, yields the columns of the view.
+func selectedColumnsMatch(table *Table, configured []string, keys []string, existing []string) bool {
+	selected := map[string]bool{}
+	for _, name := range metadataNames(table, configured) {
+		selected[name] = true
+	}
+	for _, name := range keys {
+		delete(selected, name)
+	}
+	names := make([]string, 0, len(selected))
+	for name := range selected {
+		names = append(names, name)
+	}
+	return sameStringSet(names, existing)
+}
+
+func parseMaterializedViewID(id string) (string, string, error) {
+	parts := strings.SplitN(id, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("unexpected format of ID (%s), expected keyspace.view", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+func resourceMaterializedViewImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	keyspaceName, name, err := parseMaterializedViewID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.Set("keyspace", keyspaceName)
+	d.Set("name", name)
+	d.Set("quote_identifiers", true)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceMaterializedViewCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	view := parseMaterializedViewData(d)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := generateCreateMaterializedViewQueryString(view)
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", view.Keyspace, view.Name))
+	diags = append(diags, warnAfterCreate(resourceMaterializedViewRead(ctx, d, meta))...)
+	return diags
+}
+
+func resourceMaterializedViewRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	view := parseMaterializedViewData(d)
+	table := view.table()
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	existing, err := cqlschema.ReadMaterializedView(ctx, executor, table.metadataName(view.Keyspace), table.metadataName(view.Name))
+	if err == gocql.ErrNotFound {
+		log.Printf("Materialized view '%s' in '%s' no longer exists", view.Name, view.Keyspace)
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Keep the configured spelling of names and clauses unless they actually
+	// differ from the definition stored by the cluster.
+	if table.metadataName(view.BaseTable) != existing.BaseTable {
+		d.Set("base_table", existing.BaseTable)
+	}
+	if !sameStringList(metadataNames(table, view.PartitionKeys), existing.PartitionKeys) {
+		d.Set("partition_keys", existing.PartitionKeys)
+	}
+	if !sameStringList(metadataNames(table, view.ClusteringKeys), existing.ClusteringKeys) {
+		d.Set("clustering_keys", existing.ClusteringKeys)
+	}
+	if existing.IncludeAllColumns {
+		if len(view.Columns) > 0 {
+			d.Set("columns", []string{})
+		}
+	} else if !selectedColumnsMatch(table, view.Columns, append(existing.PartitionKeys, existing.ClusteringKeys...), existing.Columns) {
+		d.Set("columns", existing.Columns)
+	}
+	if view.WhereClause != "" && normalizeWhereClause(view.WhereClause) != normalizeWhereClause(existing.WhereClause) {
+		d.Set("where_clause", existing.WhereClause)
+	}
+	return diags
+}
+
+func resourceMaterializedViewUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	view := parseMaterializedViewData(d)
+
+	if d.HasChange("options") && len(view.Options) > 0 {
+		executor, err := providerConfig.newExecutor()
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		defer executor.Close()
+
+		query := fmt.Sprintf(`ALTER MATERIALIZED VIEW %s WITH %s`, view.table().qualifiedName(), renderTableProperties(view.Options))
+		log.Printf("Executing query: %s", query)
+		if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	diags = append(diags, resourceMaterializedViewRead(ctx, d, meta)...)
+	return diags
+}
+
+func resourceMaterializedViewDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	view := parseMaterializedViewData(d)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := generateDropMaterializedViewQueryString(view)
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
@@ -0,0 +1,55 @@
+package cassandra
+
+import "testing"
+
+func TestGenerateCreateMaterializedViewQueryString(t *testing.T) {
+	view := &MaterializedView{
+		Keyspace:         "some_keyspace",
+		Name:             "usersByEmail",
+		BaseTable:        "users",
+		Columns:          []string{"name"},
+		PartitionKeys:    []string{"email"},
+		ClusteringKeys:   []string{"id"},
+		Options:          map[string]string{"comment": "'by email'"},
+		QuoteIdentifiers: true,
+	}
+
+	expected := `CREATE MATERIALIZED VIEW "some_keyspace"."usersByEmail" AS SELECT "name" FROM "some_keyspace"."users" WHERE "email" IS NOT NULL AND "id" IS NOT NULL PRIMARY KEY (("email"), "id") WITH comment = 'by email'`
+	if query := generateCreateMaterializedViewQueryString(view); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+
+	view.Columns = nil
+	view.Options = nil
+	view.WhereClause = `"email" IS NOT NULL AND "id" > 0`
+	expected = `CREATE MATERIALIZED VIEW "some_keyspace"."usersByEmail" AS SELECT * FROM "some_keyspace"."users" WHERE "email" IS NOT NULL AND "id" > 0 PRIMARY KEY (("email"), "id")`
+	if query := generateCreateMaterializedViewQueryString(view); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+
+	expected = `DROP MATERIALIZED VIEW "some_keyspace"."usersByEmail"`
+	if query := generateDropMaterializedViewQueryString(view); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+}
+
+func TestNormalizeWhereClause(t *testing.T) {
+	if normalizeWhereClause(`"email" IS NOT NULL  AND id IS NOT NULL`) != normalizeWhereClause("email is not null AND id is not null") {
+		t.Fatal("expected where clauses differing in quoting, case and whitespace to match")
+	}
+}
+
+func TestSelectedColumnsMatch(t *testing.T) {
+	table := &Table{QuoteIdentifiers: true}
+	keys := []string{"email", "id"}
+
+	if !selectedColumnsMatch(table, []string{"email", "name"}, keys, []string{"name"}) {
+		t.Fatal("expected primary key columns in the selection to be ignored")
+	}
+	if selectedColumnsMatch(table, []string{"name"}, keys, []string{"name", "age"}) {
+		t.Fatal("expected an added column to be reported")
+	}
+	if !selectedColumnsMatch(&Table{}, []string{"Name"}, keys, []string{"name"}) {
+		t.Fatal("expected unquoted names to match case-insensitively")
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"time"
+	"regexp"
+	"strings"
 
-	"github.com/gocql/gocql"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
 )
 
 func resourceCassandraRole() *schema.Resource {
@@ -20,8 +22,9 @@ func resourceCassandraRole() *schema.Resource {
 		UpdateContext: resourceRoleUpdate,
 		DeleteContext: resourceRoleDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStateWithIdentity([]string{"name"}, nameID, nil),
 		},
+		Identity: stringIdentity([]string{"name"}, nil),
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:         schema.TypeString,
@@ -43,30 +46,97 @@ func resourceCassandraRole() *schema.Resource {
 				Description: "Enable login for the role",
 			},
 			"password": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				ForceNew:         true,
+				ExactlyOneOf:     []string{"password", "hashed_password"},
+				ValidateFunc:     validation.StringLenBetween(40, 512),
+				DiffSuppressFunc: suppressPasswordOfStoredHash,
+			},
+			"hashed_password": {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
 				Sensitive:    true,
 				ForceNew:     true,
-				ValidateFunc: validation.StringLenBetween(40, 512),
+				ExactlyOneOf: []string{"password", "hashed_password"},
+				Description:  "bcrypt hash of the password, e.g. from the bcrypt_hash function, so the plaintext password is not sent to the cluster. Requires support for HASHED PASSWORD, e.g. Cassandra 5.0",
+				ValidateFunc: validation.StringMatch(bcryptHashRegex, "must be a bcrypt hash, e.g. $2a$10$..."),
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return new == "" && passwordMatchesHash(d.Get("password").(string), old)
+				},
 			},
 		},
 	}
 }
 
-func readRole(session *gocql.Session, name string, systemKeyspace string) (string, bool, bool, string, error) {
+var bcryptHashRegex = regexp.MustCompile(`^\$2[abxy]?\$[0-9]{2}\$[./A-Za-z0-9]{53}$`)
+
+// suppressPasswordOfStoredHash suppresses setting the password of an imported
+// role, whose state only holds the salted hash read from the cluster in
+// hashed_password, when the configured password matches that hash.
+func suppressPasswordOfStoredHash(k, old, new string, d *schema.ResourceData) bool {
+	storedHash, _ := d.GetChange("hashed_password")
+	return old == "" && passwordMatchesHash(new, storedHash.(string))
+}
+
+// generateRoleQueryString renders a CREATE or ALTER ROLE statement, setting
+// SUPERUSER only when superUser is not nil and the password only when one of
+// password and hashedPassword is set.
+func generateRoleQueryString(action string, name string, password string, hashedPassword string, login bool, superUser *bool) string {
+	options := []string{}
+	if hashedPassword != "" {
+		options = append(options, "HASHED PASSWORD = "+cql.Literal(hashedPassword))
+	} else if password != "" {
+		options = append(options, "PASSWORD = "+cql.Literal(password))
+	}
+	options = append(options, fmt.Sprintf("LOGIN = %v", login))
+	if superUser != nil {
+		options = append(options, fmt.Sprintf("SUPERUSER = %v", *superUser))
+	}
+	return fmt.Sprintf(`%s ROLE %s WITH %s`, action, cql.Literal(name), strings.Join(options, " AND "))
+}
+
+func readRole(ctx context.Context, executor CQLExecutor, name string, systemKeyspace string) (string, bool, bool, string, error) {
 	tableName := fmt.Sprintf("%s.roles", systemKeyspace)
 	query := fmt.Sprintf("SELECT role, can_login, is_superuser, salted_hash FROM %s WHERE role = ?", tableName)
-	iter := session.Query(query, name).Iter()
-	defer iter.Close()
-
-	var (
-		role        string
-		canLogin    bool
-		isSuperUser bool
-		saltedHash  string
-	)
-	if iter.Scan(&role, &canLogin, &isSuperUser, &saltedHash) {
-		return role, canLogin, isSuperUser, saltedHash, nil
+	// Amazon Keyspaces stores no password hashes.
+	if systemKeyspace == keyspacesSchemaKeyspace {
+		query = fmt.Sprintf("SELECT role, can_login, is_superuser FROM %s WHERE role = ?", tableName)
+	}
+	rows, err := executor.Select(ctx, query, name)
+	if err != nil {
+		if classifyQueryError(err) != queryErrorUnauthorized {
+			return "", false, false, "", err
+		}
+		log.Printf("Reading %s failed (%s), listing roles instead", tableName, err)
+		return listRole(ctx, executor, name)
+	}
+	if len(rows) == 0 {
+		return "", false, false, "", fmt.Errorf("cannot read role with name %s", name)
+	}
+	role, _ := rows[0]["role"].(string)
+	canLogin, _ := rows[0]["can_login"].(bool)
+	isSuperUser, _ := rows[0]["is_superuser"].(bool)
+	saltedHash, _ := rows[0]["salted_hash"].(string)
+	return role, canLogin, isSuperUser, saltedHash, nil
+}
+
+// listRole reads a role with LIST ROLES, which needs the DESCRIBE permission
+// on all roles instead of access to the roles table. Non-superuser accounts,
+// e.g. the management account of Azure Managed Instance for Apache
+// Cassandra, often lack the latter. The salted hash is not listed.
+func listRole(ctx context.Context, executor CQLExecutor, name string) (string, bool, bool, string, error) {
+	rows, err := executor.Select(ctx, `LIST ROLES`)
+	if err != nil {
+		return "", false, false, "", err
+	}
+	for _, row := range rows {
+		if role, _ := row["role"].(string); role == name {
+			login, _ := row["login"].(bool)
+			super, _ := row["super"].(bool)
+			return role, login, super, "", nil
+		}
 	}
 	return "", false, false, "", fmt.Errorf("cannot read role with name %s", name)
 }
@@ -76,28 +146,33 @@ func resourceRoleCreateOrUpdate(ctx context.Context, d *schema.ResourceData, met
 	superUser := d.Get("super_user").(bool)
 	login := d.Get("login").(bool)
 	password := d.Get("password").(string)
+	hashedPassword := d.Get("hashed_password").(string)
 	var diags diag.Diagnostics
 
 	providerConfig := meta.(*ProviderConfig)
-	cluster := providerConfig.Cluster
-
-	start := time.Now()
-	session, err := cluster.CreateSession()
-	elapsed := time.Since(start)
-	log.Printf("Getting a session took %s", elapsed)
+	executor, err := providerConfig.newExecutor()
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	defer session.Close()
+	defer executor.Close()
 
 	action := "CREATE"
 	if !createRole {
 		action = "ALTER"
+		// Changing the password replaces the role. The hash of an imported
+		// role need not be set again, which needs HASHED PASSWORD support.
+		hashedPassword = ""
+	}
+	// Only superusers may set SUPERUSER, even to false, so it is left out
+	// unless needed to let other roles manage non-superuser roles, e.g. the
+	// management account of Azure Managed Instance for Apache Cassandra.
+	var superUserOption *bool
+	if createRole && superUser || !createRole && d.HasChange("super_user") {
+		superUserOption = &superUser
 	}
-	query := fmt.Sprintf(`%s ROLE '%s' WITH PASSWORD = '%s' AND LOGIN = %v AND SUPERUSER = %v`,
-		action, name, password, login, superUser)
+	query := generateRoleQueryString(action, name, password, hashedPassword, login, superUserOption)
 	log.Printf("Executing query: %s", query)
-	if err := session.Query(query).Exec(); err != nil {
+	if err := executor.Exec(ctx, query); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -106,8 +181,12 @@ func resourceRoleCreateOrUpdate(ctx context.Context, d *schema.ResourceData, met
 	d.Set("super_user", superUser)
 	d.Set("login", login)
 	d.Set("password", password)
+	d.Set("hashed_password", hashedPassword)
+	if err := setIdentity(d, "name"); err != nil {
+		return diag.FromErr(err)
+	}
 
-	diags = append(diags, resourceRoleRead(ctx, d, meta)...)
+	diags = append(diags, warnAfterCreate(resourceRoleRead(ctx, d, meta))...)
 	return diags
 }
 
@@ -120,25 +199,29 @@ func resourceRoleRead(ctx context.Context, d *schema.ResourceData, meta interfac
 	var diags diag.Diagnostics
 
 	providerConfig := meta.(*ProviderConfig)
-	cluster := providerConfig.Cluster
-
-	start := time.Now()
-	session, err := cluster.CreateSession()
-	elapsed := time.Since(start)
-	log.Printf("Getting a session took %s", elapsed)
+	executor, err := providerConfig.sharedExecutor()
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	defer session.Close()
+	defer executor.Close()
 
-	_role, login, superUser, _, err := readRole(session, name, providerConfig.SystemKeyspaceName)
+	_role, login, superUser, saltedHash, err := readRole(ctx, executor, name, providerConfig.authKeyspace(ctx, executor))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	// Imported roles have no password in state. Keep the salted hash, so a
+	// configured password can be compared with it instead of replacing the
+	// role.
+	if d.Get("password").(string) == "" && d.Get("hashed_password").(string) == "" {
+		d.Set("hashed_password", saltedHash)
+	}
 	d.Set("name", _role)
 	d.Set("super_user", superUser)
 	d.Set("login", login)
+	if err := setIdentity(d, "name"); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
@@ -147,19 +230,14 @@ func resourceRoleDelete(ctx context.Context, d *schema.ResourceData, meta interf
 	var diags diag.Diagnostics
 
 	providerConfig := meta.(*ProviderConfig)
-	cluster := providerConfig.Cluster
-
-	start := time.Now()
-	session, err := cluster.CreateSession()
-	elapsed := time.Since(start)
-	log.Printf("Getting a session took %s", elapsed)
+	executor, err := providerConfig.newExecutor()
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	defer session.Close()
+	defer executor.Close()
 
-	query := fmt.Sprintf(`DROP ROLE '%s'`, name)
-	if err := session.Query(query).Exec(); err != nil {
+	query := fmt.Sprintf(`DROP ROLE %s`, cql.Literal(name))
+	if err := executor.Exec(ctx, query); err != nil {
 		return diag.FromErr(err)
 	}
 	return diags
@@ -1,16 +1,19 @@
 package cassandra
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"regexp"
 	"testing"
 
+	"github.com/gocql/gocql"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccCassandraRole_basic(t *testing.T) {
-	name := "user"
+	name := testAccResourcePrefix + "user"
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -56,13 +59,11 @@ resource "cassandra_role" "user" {
 
 func testAccCassandraRoleDestroy(s *terraform.State) error {
 	pc := testAccProvider.Meta().(*ProviderConfig)
-	cluster := pc.Cluster
-	session, sessionCreateError := cluster.CreateSession()
-
-	if sessionCreateError != nil {
-		return sessionCreateError
+	executor, err := pc.newExecutor()
+	if err != nil {
+		return err
 	}
-	defer session.Close()
+	defer executor.Close()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cassandra_role" {
@@ -70,7 +71,7 @@ func testAccCassandraRoleDestroy(s *terraform.State) error {
 		}
 
 		name := rs.Primary.Attributes["name"]
-		_, _, _, _, err := readRole(session, name, pc.Mode)
+		_, _, _, _, err := readRole(context.Background(), executor, name, pc.authKeyspace(context.Background(), executor))
 		if err != nil {
 			return nil
 		}
@@ -89,17 +90,126 @@ func testAccCassandraRoleExists(resourceKey string) resource.TestCheckFunc {
 			return fmt.Errorf("no ID is set")
 		}
 		pc := testAccProvider.Meta().(*ProviderConfig)
-		cluster := pc.Cluster
-		session, sessionCreateError := cluster.CreateSession()
-		if sessionCreateError != nil {
-			return sessionCreateError
+		executor, err := pc.newExecutor()
+		if err != nil {
+			return err
 		}
-		defer session.Close()
+		defer executor.Close()
 
-		_, _, _, _, err := readRole(session, rs.Primary.ID, pc.Mode)
+		_, _, _, _, err = readRole(context.Background(), executor, rs.Primary.ID, pc.authKeyspace(context.Background(), executor))
 		if err != nil {
 			return err
 		}
 		return nil
 	}
 }
+
+func TestGenerateRoleQueryString(t *testing.T) {
+	superUser := false
+	cases := []struct {
+		action         string
+		password       string
+		hashedPassword string
+		superUser      *bool
+		expected       string
+	}{
+		{"CREATE", "secret", "", nil, `CREATE ROLE 'app' WITH PASSWORD = 'secret' AND LOGIN = true`},
+		{"ALTER", "secret", "", &superUser, `ALTER ROLE 'app' WITH PASSWORD = 'secret' AND LOGIN = true AND SUPERUSER = false`},
+		{"ALTER", "", "", nil, `ALTER ROLE 'app' WITH LOGIN = true`},
+		{"CREATE", "", "$2a$10$hash", nil, `CREATE ROLE 'app' WITH HASHED PASSWORD = '$2a$10$hash' AND LOGIN = true`},
+	}
+	for _, c := range cases {
+		if actual := generateRoleQueryString(c.action, "app", c.password, c.hashedPassword, true, c.superUser); actual != c.expected {
+			t.Fatalf("expected %s, got %s", c.expected, actual)
+		}
+	}
+}
+
+func TestRolePasswordDiffOfImportedRole(t *testing.T) {
+	password := "correct horse battery staple correct horse"
+	hash, err := bcryptHash(password, "kVQ1mEf2xQ0eTjqS8gHnVe", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := &terraform.InstanceState{ID: "app", Attributes: map[string]string{
+		"id":              "app",
+		"name":            "app",
+		"login":           "true",
+		"super_user":      "false",
+		"hashed_password": hash,
+	}}
+
+	diff, err := resourceCassandraRole().Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":     "app",
+		"password": password,
+	}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != nil && !diff.Empty() {
+		t.Fatalf("expected no diff for the password of the stored hash, got %v", diff.Attributes)
+	}
+
+	diff, err = resourceCassandraRole().Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":     "app",
+		"password": password + " changed",
+	}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil || !diff.RequiresNew() {
+		t.Fatal("expected another password to replace the role")
+	}
+}
+
+func TestResourceRoleLifecycle(t *testing.T) {
+	executor := newMockCQLExecutor()
+	password := "a-password-of-at-least-forty-characters!"
+	d := testResourceDataWithIdentity(t, resourceCassandraRole(), map[string]interface{}{
+		"name":     "app",
+		"password": password,
+	})
+	executor.rows[`SELECT role, can_login, is_superuser, salted_hash FROM system_auth.roles WHERE role = ? [app]`] = []map[string]interface{}{
+		{"role": "app", "can_login": true, "is_superuser": false, "salted_hash": "$2a$10$stored"},
+	}
+
+	if diags := resourceRoleCreate(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if identity, err := d.Identity(); err != nil || identity.Get("name").(string) != "app" {
+		t.Fatalf("expected the role to be identified by its name, got %v", err)
+	}
+	if diags := resourceRoleDelete(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	expected := []string{
+		`CREATE ROLE 'app' WITH PASSWORD = '` + password + `' AND LOGIN = true`,
+		`DROP ROLE 'app'`,
+	}
+	if !reflect.DeepEqual(executor.executed, expected) {
+		t.Fatalf("expected %v, got %v", expected, executor.executed)
+	}
+	if hashedPassword := d.Get("hashed_password").(string); hashedPassword != "" {
+		t.Fatalf("expected the hash of a role with a password to stay unset, got %s", hashedPassword)
+	}
+}
+
+func TestReadRole_listRoles(t *testing.T) {
+	executor := newMockCQLExecutor()
+	executor.errors[`SELECT role, can_login, is_superuser, salted_hash FROM system_auth.roles WHERE role = ?`] = testRequestError{gocql.ErrCodeUnauthorized, "User app has no SELECT permission on <table system_auth.roles>"}
+	executor.rows[`LIST ROLES`] = []map[string]interface{}{
+		{"role": "other", "login": true, "super": true},
+		{"role": "app", "login": true, "super": false},
+	}
+
+	role, login, superUser, saltedHash, err := readRole(context.Background(), executor, "app", "system_auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if role != "app" || !login || superUser || saltedHash != "" {
+		t.Fatalf("unexpected role %s, login %t, superuser %t, salted hash %q", role, login, superUser, saltedHash)
+	}
+	if _, _, _, _, err := readRole(context.Background(), executor, "missing", "system_auth"); err == nil {
+		t.Fatal("expected reading a missing role to fail")
+	}
+}
@@ -0,0 +1,260 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+)
+
+func resourceCassandraRowLevelAccess() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Manage DataStax Enterprise row-level access control (RLAC) of a table: the column rows are filtered on and the rows each role may access",
+		CreateContext: resourceRowLevelAccessCreate,
+		ReadContext:   resourceRowLevelAccessRead,
+		UpdateContext: resourceRowLevelAccessUpdate,
+		DeleteContext: resourceRowLevelAccessDelete,
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if mode := meta.(*ProviderConfig).Mode; mode != modeCassandra {
+				return fmt.Errorf("row-level access control requires DataStax Enterprise and is not supported in %s mode", mode)
+			}
+			return nil
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"keyspace": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Keyspace of the table",
+			},
+			"table": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Name of the restricted table",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"column": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Text column of the partition key that rows are filtered on",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"grant": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Rows a role may access, selected by the value of the filtering column",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"role": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "Role granted access",
+							ValidateFunc: validation.StringLenBetween(1, 256),
+						},
+						"filtering_data": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Value of the filtering column of the rows the role may access",
+						},
+						"permissions": {
+							Type:        schema.TypeSet,
+							Required:    true,
+							MinItems:    1,
+							Description: fmt.Sprintf("Permissions on the rows - %s and/or %s", privilegeSelect, privilegeModify),
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.StringInSlice([]string{privilegeSelect, privilegeModify}, false),
+							},
+						},
+					},
+				},
+			},
+			"quote_identifiers": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Double-quote the table and column names in generated CQL, keeping their case. Set to false to use unquoted, case-insensitive identifiers",
+			},
+		},
+	}
+}
+
+// RowGrant is a permission on the rows of a restricted table matching filtering data.
+type RowGrant struct {
+	Role          string
+	FilteringData string
+	Permission    string
+}
+
+func parseRowLevelAccessTable(d attributeGetter) *Table {
+	return &Table{
+		Keyspace:         d.Get("keyspace").(string),
+		Name:             d.Get("table").(string),
+		QuoteIdentifiers: d.Get("quote_identifiers").(bool),
+	}
+}
+
+// parseRowGrants flattens the grant blocks into one RowGrant per permission,
+// sorted so generated statements are stable.
+func parseRowGrants(raw interface{}) []RowGrant {
+	grants := []RowGrant{}
+	set, ok := raw.(*schema.Set)
+	if !ok {
+		return grants
+	}
+	for _, elem := range set.List() {
+		grant := elem.(map[string]interface{})
+		for _, permission := range setToArray(grant["permissions"]) {
+			grants = append(grants, RowGrant{
+				Role:          grant["role"].(string),
+				FilteringData: grant["filtering_data"].(string),
+				Permission:    permission,
+			})
+		}
+	}
+	sort.Slice(grants, func(i, j int) bool {
+		return fmt.Sprint(grants[i]) < fmt.Sprint(grants[j])
+	})
+	return grants
+}
+
+func generateRestrictRowsQueryString(table *Table, column string) string {
+	return fmt.Sprintf(`RESTRICT ROWS ON %s USING %s`, table.qualifiedName(), table.identifier(column))
+}
+
+func generateUnrestrictRowsQueryString(table *Table) string {
+	return fmt.Sprintf(`UNRESTRICT ROWS ON %s`, table.qualifiedName())
+}
+
+func generateGrantRowsQueryString(table *Table, grant RowGrant) string {
+	return fmt.Sprintf(`GRANT %s ON %s ROWS IN %s TO %s`, strings.ToUpper(grant.Permission), cql.Literal(grant.FilteringData), table.qualifiedName(), cql.QuoteIdentifier(grant.Role))
+}
+
+func generateRevokeRowsQueryString(table *Table, grant RowGrant) string {
+	return fmt.Sprintf(`REVOKE %s ON %s ROWS IN %s FROM %s`, strings.ToUpper(grant.Permission), cql.Literal(grant.FilteringData), table.qualifiedName(), cql.QuoteIdentifier(grant.Role))
+}
+
+// rowGrantDifference returns the grants in a that are not in b.
+func rowGrantDifference(a, b []RowGrant) []RowGrant {
+	inB := map[RowGrant]bool{}
+	for _, grant := range b {
+		inB[grant] = true
+	}
+	ret := []RowGrant{}
+	for _, grant := range a {
+		if !inB[grant] {
+			ret = append(ret, grant)
+		}
+	}
+	return ret
+}
+
+func execRowLevelAccessQueries(ctx context.Context, meta interface{}, queries []string, timeout time.Duration) diag.Diagnostics {
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	for _, query := range queries {
+		log.Printf("Executing query: %s", query)
+		if err := executor.ExecSchemaChange(ctx, query, timeout); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
+
+func resourceRowLevelAccessCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	table := parseRowLevelAccessTable(d)
+	queries := []string{generateRestrictRowsQueryString(table, d.Get("column").(string))}
+	for _, grant := range parseRowGrants(d.Get("grant")) {
+		queries = append(queries, generateGrantRowsQueryString(table, grant))
+	}
+	if diags := execRowLevelAccessQueries(ctx, meta, queries, d.Timeout(schema.TimeoutCreate)); diags.HasError() {
+		return diags
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", table.Keyspace, table.Name))
+	diags = append(diags, warnAfterCreate(resourceRowLevelAccessRead(ctx, d, meta))...)
+	return diags
+}
+
+func resourceRowLevelAccessRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	table := parseRowLevelAccessTable(d)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	// The restriction and the row grants are dropped together with the table.
+	keyspaceMetadata, err := providerConfig.keyspaceMetadata(executor, table.metadataName(table.Keyspace))
+	if err == gocql.ErrKeyspaceDoesNotExist {
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return diag.FromErr(err)
+	}
+	if _, ok := keyspaceMetadata.Tables[table.metadataName(table.Name)]; !ok {
+		log.Printf("Table '%s' in '%s' no longer exists", table.Name, table.Keyspace)
+		d.SetId("")
+		return nil
+	}
+	return diags
+}
+
+func resourceRowLevelAccessUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	table := parseRowLevelAccessTable(d)
+	o, n := d.GetChange("grant")
+	old, new := parseRowGrants(o), parseRowGrants(n)
+
+	queries := []string{}
+	for _, grant := range rowGrantDifference(old, new) {
+		queries = append(queries, generateRevokeRowsQueryString(table, grant))
+	}
+	for _, grant := range rowGrantDifference(new, old) {
+		queries = append(queries, generateGrantRowsQueryString(table, grant))
+	}
+	if diags := execRowLevelAccessQueries(ctx, meta, queries, d.Timeout(schema.TimeoutUpdate)); diags.HasError() {
+		return diags
+	}
+
+	diags = append(diags, resourceRowLevelAccessRead(ctx, d, meta)...)
+	return diags
+}
+
+func resourceRowLevelAccessDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	table := parseRowLevelAccessTable(d)
+	queries := []string{}
+	for _, grant := range parseRowGrants(d.Get("grant")) {
+		queries = append(queries, generateRevokeRowsQueryString(table, grant))
+	}
+	queries = append(queries, generateUnrestrictRowsQueryString(table))
+	return execRowLevelAccessQueries(ctx, meta, queries, d.Timeout(schema.TimeoutDelete))
+}
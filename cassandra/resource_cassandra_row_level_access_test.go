@@ -0,0 +1,30 @@
+package cassandra
+
+import "testing"
+
+func TestGenerateRowLevelAccessQueryStrings(t *testing.T) {
+	table := &Table{Keyspace: "some_keyspace", Name: "accounts", QuoteIdentifiers: true}
+	grant := RowGrant{Role: "emea_support", FilteringData: "o'brien", Permission: privilegeSelect}
+
+	for query, expected := range map[string]string{
+		generateRestrictRowsQueryString(table, "region"): `RESTRICT ROWS ON "some_keyspace"."accounts" USING "region"`,
+		generateUnrestrictRowsQueryString(table):         `UNRESTRICT ROWS ON "some_keyspace"."accounts"`,
+		generateGrantRowsQueryString(table, grant):       `GRANT SELECT ON 'o''brien' ROWS IN "some_keyspace"."accounts" TO "emea_support"`,
+		generateRevokeRowsQueryString(table, grant):      `REVOKE SELECT ON 'o''brien' ROWS IN "some_keyspace"."accounts" FROM "emea_support"`,
+	} {
+		if query != expected {
+			t.Fatalf("expected %q, got %q", expected, query)
+		}
+	}
+}
+
+func TestRowGrantDifference(t *testing.T) {
+	selectEMEA := RowGrant{Role: "support", FilteringData: "EMEA", Permission: privilegeSelect}
+	modifyEMEA := RowGrant{Role: "support", FilteringData: "EMEA", Permission: privilegeModify}
+	selectAPAC := RowGrant{Role: "support", FilteringData: "APAC", Permission: privilegeSelect}
+
+	removed := rowGrantDifference([]RowGrant{selectEMEA, modifyEMEA}, []RowGrant{selectEMEA, selectAPAC})
+	if len(removed) != 1 || removed[0] != modifyEMEA {
+		t.Fatalf("expected only %v to be removed, got %v", modifyEMEA, removed)
+	}
+}
@@ -0,0 +1,356 @@
+package cassandra
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// migrationFilePattern matches versioned migration files, e.g. V2__add_users.cql.
+var migrationFilePattern = regexp.MustCompile(`^V([0-9]+)__(.+)\.cql$`)
+
+func resourceCassandraSchemaMigration() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Apply a directory of versioned CQL migration files in order, recording applied versions and checksums in a tracking table",
+		CreateContext: resourceSchemaMigrationCreate,
+		ReadContext:   resourceSchemaMigrationRead,
+		UpdateContext: resourceSchemaMigrationUpdate,
+		DeleteContext: resourceSchemaMigrationDelete,
+		CustomizeDiff: resourceSchemaMigrationCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"directory": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Directory holding the migration files, named V<version>__<description>.cql. Other files are ignored",
+			},
+			"keyspace": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Keyspace holding the tracking table. Migrations must qualify the tables they change with their keyspace",
+			},
+			"tracking_table": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "schema_migrations",
+				Description:  "Table recording the applied migrations, created if it does not exist",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"migrations": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "SHA-256 checksums of the applied migrations by version",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// Migration is a versioned migration file.
+type Migration struct {
+	Version     int
+	Description string
+	Checksum    string
+	Statements  []string
+}
+
+// readMigrations reads the migration files of a directory ordered by version.
+func readMigrations(directory string) ([]*Migration, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := []*Migration{}
+	seen := map[int]string{}
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid version in %s: %s", entry.Name(), err)
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", other, entry.Name(), version)
+		}
+		seen[version] = entry.Name()
+
+		content, err := os.ReadFile(filepath.Join(directory, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		checksum := sha256.Sum256(content)
+		migrations = append(migrations, &Migration{
+			Version:     version,
+			Description: strings.ReplaceAll(match[2], "_", " "),
+			Checksum:    hex.EncodeToString(checksum[:]),
+			Statements:  splitCQLStatements(string(content)),
+		})
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// splitCQLStatements splits a CQL script on the semicolons ending its
+// statements, skipping those inside string literals, quoted identifiers,
+// $$ function bodies and comments. Comments are dropped.
+func splitCQLStatements(script string) []string {
+	statements := []string{}
+	var current strings.Builder
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		rest := script[i:]
+		switch {
+		case strings.HasPrefix(rest, "--") || strings.HasPrefix(rest, "//"):
+			for i < len(script) && script[i] != '\n' {
+				i++
+			}
+			current.WriteByte('\n')
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				i = len(script)
+			} else {
+				i += end + 3
+			}
+			current.WriteByte(' ')
+		case strings.HasPrefix(rest, "$$"):
+			end := strings.Index(rest[2:], "$$")
+			if end < 0 {
+				end = len(rest) - 4
+			}
+			current.WriteString(rest[:end+4])
+			i += end + 3
+		case c == '\'' || c == '"':
+			// Quotes are escaped by doubling them, which this loop handles as
+			// two adjacent literals.
+			end := strings.IndexByte(rest[1:], c)
+			if end < 0 {
+				end = len(rest) - 2
+			}
+			current.WriteString(rest[:end+2])
+			i += end + 1
+		case c == ';':
+			flush()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+	return statements
+}
+
+func parseSchemaMigrationTable(d attributeGetter) *Table {
+	return &Table{
+		Keyspace:         d.Get("keyspace").(string),
+		Name:             d.Get("tracking_table").(string),
+		QuoteIdentifiers: true,
+	}
+}
+
+func generateCreateTrackingTableQueryString(table *Table) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (version int PRIMARY KEY, description text, checksum text, applied_at timestamp)`, table.qualifiedName())
+}
+
+// queryAppliedMigrations reads the checksums of the applied migrations by version.
+func queryAppliedMigrations(ctx context.Context, executor CQLExecutor, table *Table) (map[string]string, error) {
+	rows, err := executor.Select(ctx, fmt.Sprintf(`SELECT version, checksum FROM %s`, table.qualifiedName()))
+	if err != nil {
+		return nil, err
+	}
+
+	applied := map[string]string{}
+	for _, row := range rows {
+		version, _ := row["version"].(int)
+		checksum, _ := row["checksum"].(string)
+		applied[strconv.Itoa(version)] = checksum
+	}
+	return applied, nil
+}
+
+// pendingMigrations returns the migrations not applied yet. It fails when an
+// applied migration was changed since, as it would not be applied again.
+func pendingMigrations(migrations []*Migration, applied map[string]string) ([]*Migration, error) {
+	pending := []*Migration{}
+	for _, migration := range migrations {
+		checksum, ok := applied[strconv.Itoa(migration.Version)]
+		if !ok {
+			pending = append(pending, migration)
+			continue
+		}
+		if checksum != migration.Checksum {
+			return nil, fmt.Errorf("migration V%d (%s) was changed after it was applied: checksum %s, applied %s", migration.Version, migration.Description, migration.Checksum, checksum)
+		}
+	}
+	return pending, nil
+}
+
+func resourceSchemaMigrationCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("directory") {
+		return d.SetNewComputed("migrations")
+	}
+	migrations, err := readMigrations(d.Get("directory").(string))
+	if err != nil {
+		return err
+	}
+
+	applied := map[string]string{}
+	for version, checksum := range d.Get("migrations").(map[string]interface{}) {
+		applied[version] = checksum.(string)
+	}
+	pending, err := pendingMigrations(migrations, applied)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	planned := map[string]interface{}{}
+	for version, checksum := range applied {
+		planned[version] = checksum
+	}
+	for _, migration := range pending {
+		planned[strconv.Itoa(migration.Version)] = migration.Checksum
+	}
+	return d.SetNew("migrations", planned)
+}
+
+func applySchemaMigrations(ctx context.Context, d *schema.ResourceData, meta interface{}, timeout time.Duration) diag.Diagnostics {
+	providerConfig := meta.(*ProviderConfig)
+	table := parseSchemaMigrationTable(d)
+	migrations, err := readMigrations(d.Get("directory").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := generateCreateTrackingTableQueryString(table)
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, timeout); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := waitForTableVisible(ctx, executor, table, timeout); err != nil {
+		return diag.FromErr(err)
+	}
+
+	applied, err := queryAppliedMigrations(ctx, executor, table)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	pending, err := pendingMigrations(migrations, applied)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, migration := range pending {
+		log.Printf("Applying migration V%d (%s)", migration.Version, migration.Description)
+		for _, statement := range migration.Statements {
+			log.Printf("Executing query: %s", statement)
+			if err := executor.ExecSchemaChange(ctx, statement, timeout); err != nil {
+				return diag.Errorf("migration V%d (%s) failed: %s", migration.Version, migration.Description, err)
+			}
+		}
+		err := executor.Exec(ctx, fmt.Sprintf(`INSERT INTO %s (version, description, checksum, applied_at) VALUES (?, ?, ?, ?)`, table.qualifiedName()),
+			migration.Version, migration.Description, migration.Checksum, time.Now())
+		if err != nil {
+			return diag.Errorf("migration V%d (%s) was applied but could not be recorded: %s", migration.Version, migration.Description, err)
+		}
+	}
+	return nil
+}
+
+func resourceSchemaMigrationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if diags := applySchemaMigrations(ctx, d, meta, d.Timeout(schema.TimeoutCreate)); diags.HasError() {
+		return diags
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", d.Get("keyspace").(string), d.Get("tracking_table").(string)))
+	diags = append(diags, warnAfterCreate(resourceSchemaMigrationRead(ctx, d, meta))...)
+	return diags
+}
+
+func resourceSchemaMigrationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	table := parseSchemaMigrationTable(d)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	keyspaceMetadata, err := executor.KeyspaceMetadata(table.metadataName(table.Keyspace))
+	if err == gocql.ErrKeyspaceDoesNotExist {
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return diag.FromErr(err)
+	}
+	if _, ok := keyspaceMetadata.Tables[table.metadataName(table.Name)]; !ok {
+		log.Printf("Tracking table '%s' in '%s' no longer exists", table.Name, table.Keyspace)
+		d.SetId("")
+		return nil
+	}
+
+	applied, err := queryAppliedMigrations(ctx, executor, table)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("migrations", applied)
+	return diags
+}
+
+func resourceSchemaMigrationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if diags := applySchemaMigrations(ctx, d, meta, d.Timeout(schema.TimeoutUpdate)); diags.HasError() {
+		return diags
+	}
+
+	diags = append(diags, resourceSchemaMigrationRead(ctx, d, meta)...)
+	return diags
+}
+
+func resourceSchemaMigrationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("Leaving migrations recorded in '%s' in place", d.Id())
+	return nil
+}
@@ -0,0 +1,120 @@
+package cassandra
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestSplitCQLStatements(t *testing.T) {
+	script := `-- create the users table
+CREATE TABLE ks.users (id uuid PRIMARY KEY, name text); /* inline; comment */
+INSERT INTO ks.users (id, name) VALUES (uuid(), 'it''s; fine');
+CREATE FUNCTION ks.fn(x int) RETURNS NULL ON NULL INPUT RETURNS int LANGUAGE java AS $$ return x; $$;
+// trailing comment without a statement
+`
+	expected := []string{
+		"CREATE TABLE ks.users (id uuid PRIMARY KEY, name text)",
+		"INSERT INTO ks.users (id, name) VALUES (uuid(), 'it''s; fine')",
+		"CREATE FUNCTION ks.fn(x int) RETURNS NULL ON NULL INPUT RETURNS int LANGUAGE java AS $$ return x; $$",
+	}
+	if statements := splitCQLStatements(script); !reflect.DeepEqual(statements, expected) {
+		t.Fatalf("expected %q, got %q", expected, statements)
+	}
+}
+
+func TestReadMigrations(t *testing.T) {
+	directory := t.TempDir()
+	files := map[string]string{
+		"V10__add_index.cql":   "CREATE INDEX ON ks.users (name);",
+		"V2__create_users.cql": "CREATE TABLE ks.users (id uuid PRIMARY KEY, name text);",
+		"README.md":            "not a migration",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(directory, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	migrations, err := readMigrations(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) != 2 || migrations[0].Version != 2 || migrations[1].Version != 10 {
+		t.Fatalf("expected migrations V2 and V10 in order, got %+v", migrations)
+	}
+	if migrations[0].Description != "create users" || len(migrations[0].Statements) != 1 {
+		t.Fatalf("unexpected migration %+v", migrations[0])
+	}
+
+	pending, err := pendingMigrations(migrations, map[string]string{"2": migrations[0].Checksum})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].Version != 10 {
+		t.Fatalf("expected V10 to be pending, got %+v", pending)
+	}
+	if _, err := pendingMigrations(migrations, map[string]string{"2": "changed"}); err == nil {
+		t.Fatal("expected a changed migration to be reported")
+	}
+
+	if err := os.WriteFile(filepath.Join(directory, "V02__duplicate.cql"), []byte(""), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readMigrations(directory); err == nil {
+		t.Fatal("expected duplicate versions to be rejected")
+	}
+}
+
+func TestApplySchemaMigrations(t *testing.T) {
+	directory := t.TempDir()
+	files := map[string]string{
+		"V1__create_users.cql": "CREATE TABLE ks.users (id uuid PRIMARY KEY, name text);",
+		"V2__add_email.cql":    "ALTER TABLE ks.users ADD email text;",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(directory, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	migrations, err := readMigrations(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	executor := newMockCQLExecutor()
+	executor.keyspaces["ks"] = &gocql.KeyspaceMetadata{
+		Name:   "ks",
+		Tables: map[string]*gocql.TableMetadata{"schema_migrations": {Keyspace: "ks", Name: "schema_migrations"}},
+	}
+	executor.rows[`SELECT version, checksum FROM "ks"."schema_migrations"`] = []map[string]interface{}{
+		{"version": 1, "checksum": migrations[0].Checksum},
+	}
+	d := schema.TestResourceDataRaw(t, resourceCassandraSchemaMigration().Schema, map[string]interface{}{
+		"directory": directory,
+		"keyspace":  "ks",
+	})
+
+	if diags := applySchemaMigrations(context.Background(), d, executor.providerConfig(), time.Minute); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(executor.executed) != 3 {
+		t.Fatalf("expected the tracking table, V2 and its record, got %v", executor.executed)
+	}
+	if !strings.HasPrefix(executor.executed[0], `CREATE TABLE IF NOT EXISTS "ks"."schema_migrations" `) {
+		t.Fatalf("expected the tracking table to be created, got %s", executor.executed[0])
+	}
+	if executor.executed[1] != "ALTER TABLE ks.users ADD email text" {
+		t.Fatalf("expected only V2 to be applied, got %s", executor.executed[1])
+	}
+	if !strings.HasPrefix(executor.executed[2], `INSERT INTO "ks"."schema_migrations" (version, description, checksum, applied_at) VALUES (?, ?, ?, ?) [2 add email `+migrations[1].Checksum+` `) {
+		t.Fatalf("expected V2 to be recorded, got %s", executor.executed[2])
+	}
+}
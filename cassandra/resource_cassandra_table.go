@@ -1,26 +1,60 @@
 package cassandra
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/gocql/gocql"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"github.com/kristoiv/gocqltable"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+	"github.com/konradotto/terraform-provider-cassandra/cqlschema"
+)
+
+const (
+	keyspacesTableStatusActive  = "ACTIVE"
+	keyspacesTableStatusDeleted = "DELETED"
+
+	tableKeyPartition  = "partition"
+	tableKeyClustering = "clustering"
+
+	keyspacesThroughputModeOnDemand    = "PAY_PER_REQUEST"
+	keyspacesThroughputModeProvisioned = "PROVISIONED"
+)
+
+var (
+	attributeTypeToCQLType = map[string]string{
+		"S": "text",
+		"N": "decimal",
+		"B": "blob",
+	}
 )
 
 func resourceCassandraTableSpace() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
 		Description:   "Create and Delete Tables within Keyspaces",
 		CreateContext: resourceTableCreate,
 		ReadContext:   resourceTableRead,
+		UpdateContext: resourceTableUpdate,
 		DeleteContext: resourceTableDelete,
+		CustomizeDiff: resourceTableCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStateWithIdentity([]string{"keyspace", "name"}, nameID, nil),
+		},
+		Identity: stringIdentity([]string{"keyspace", "name"}, nil),
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -37,29 +71,65 @@ func resourceCassandraTableSpace() *schema.Resource {
 				Description: "Keyspace to create table within",
 			},
 			"attribute": {
-				Type: schema.TypeSet,
+				Type: schema.TypeList,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"name": {
 							Type:     schema.TypeString,
 							Required: true,
+							ForceNew: true,
 						},
 						"type": {
 							Type:         schema.TypeString,
 							Required:     true,
+							ForceNew:     true,
 							ValidateFunc: validation.StringInSlice([]string{"S", "N", "B"}, false),
 						},
+						"key": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ForceNew:     true,
+							Description:  fmt.Sprintf("Role of the column in the primary key - one of %s or %s. Alternative to row_keys and range_keys", tableKeyPartition, tableKeyClustering),
+							ValidateFunc: validation.StringInSlice([]string{tableKeyPartition, tableKeyClustering}, false),
+						},
+						"position": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ForceNew:     true,
+							Default:      0,
+							Description:  "Position of the column within its partition or clustering key, lowest first",
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						"mask": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							MaxItems:    1,
+							Description: "Dynamic data masking applied to the column (Cassandra 5.0+)",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"function": {
+										Type:         schema.TypeString,
+										Required:     true,
+										Description:  "Masking function, e.g. mask_default, mask_null, mask_replace, mask_inner, mask_outer, mask_hash or a UDF, optionally qualified with its keyspace",
+										ValidateFunc: validation.StringMatch(maskFunctionRegex, "must be a function name, optionally qualified with its keyspace, e.g. mask_inner or my_keyspace.my_mask"),
+									},
+									"args": {
+										Type:        schema.TypeList,
+										Optional:    true,
+										Description: "Arguments passed to the masking function as CQL literals - a quoted string, a number, a blob, true, false or null, e.g. `'redacted'` or `1`",
+										Elem: &schema.Schema{
+											Type:         schema.TypeString,
+											ValidateFunc: validation.StringMatch(maskArgumentRegex, "must be a CQL literal, e.g. 'redacted', 1, 0xcafe, true or null"),
+										},
+									},
+								},
+							},
+						},
 					},
 				},
-				Set: func(v interface{}) int {
-					var buf bytes.Buffer
-					m := v.(map[string]interface{})
-					buf.WriteString(fmt.Sprintf("%s-", m["name"].(string)))
-					return stringHashcode(buf.String())
-				},
-				Required:    true,
-				ForceNew:    true,
-				Description: "List of Row Keys",
+				DiffSuppressFunc: suppressAttributeReorder,
+				Required:         true,
+				Description:      "Columns of the table, rendered in declaration order",
 			},
 			"row_keys": {
 				Type:        schema.TypeSet,
@@ -77,46 +147,788 @@ func resourceCassandraTableSpace() *schema.Resource {
 				ForceNew:    true,
 				Description: "List of Range Keys",
 			},
+			"deletion_protection": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Prevent the table from being dropped - must be set to false and applied before the table can be destroyed",
+			},
+			"capacity_specification": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Read/write capacity of the table - only supported in aws_keyspaces mode",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"throughput_mode": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      keyspacesThroughputModeOnDemand,
+							Description:  fmt.Sprintf("One of %s or %s", keyspacesThroughputModeOnDemand, keyspacesThroughputModeProvisioned),
+							ValidateFunc: validation.StringInSlice([]string{keyspacesThroughputModeOnDemand, keyspacesThroughputModeProvisioned}, false),
+						},
+						"read_capacity_units": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  fmt.Sprintf("Read capacity units, required when throughput_mode is %s", keyspacesThroughputModeProvisioned),
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						"write_capacity_units": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  fmt.Sprintf("Write capacity units, required when throughput_mode is %s", keyspacesThroughputModeProvisioned),
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+					},
+				},
+			},
+			"twcs": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Compact the table with TimeWindowCompactionStrategy, which suits time series, e.g. rows written with a TTL. Not supported in aws_keyspaces mode. Do not set compaction in a cassandra_table_options of the same table as well. Removing the block leaves the compaction in place",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"compaction_window_unit": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "DAYS",
+							Description:  "Unit of the time windows - allowed values are MINUTES, HOURS and DAYS",
+							ValidateFunc: validation.StringInSlice(twcsWindowUnits, false),
+						},
+						"compaction_window_size": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      1,
+							Description:  "Number of units per time window. Aim for 20 to 30 windows over the lifetime of the rows",
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+					},
+				},
+			},
+			"point_in_time_recovery": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Enable point-in-time recovery - only supported in aws_keyspaces mode",
+			},
+			"tags": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Resource tags of the table - only supported in aws_keyspaces mode",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"quote_identifiers": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Double-quote keyspace, table and column names in generated CQL, keeping their case and allowing reserved words. Set to false to use unquoted, case-insensitive identifiers, as tables created before this attribute existed do",
+			},
+			"allow_existing": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Adopt an existing table with the same name instead of failing, provided its columns and keys match",
+			},
+			"require_empty_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Refuse to drop the table while it still contains rows, unless force is set",
+			},
+			"force": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Drop the table even if require_empty_on_destroy is set and the table contains rows",
+			},
+			"cql": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "CQL statement(s) executed for the most recent change to the table",
+			},
+			"compact_storage": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the table was created WITH COMPACT STORAGE. Such tables cannot be created by Cassandra 4.0 and later, so changes that would replace them are refused",
+			},
 		},
 	}
+
+	// Version 0 of the state has the same attributes, but tables in it were
+	// created before quote_identifiers defaulted to true.
+	resource.SchemaVersion = 1
+	resource.StateUpgraders = []schema.StateUpgrader{{
+		Version: 0,
+		Type:    resource.CoreConfigSchema().ImpliedType(),
+		Upgrade: resourceTableStateUpgradeV0,
+	}}
+	return resource
+}
+
+// resourceTableStateUpgradeV0 keeps the tables of state version 0 that do not
+// record quote_identifiers unquoted, as they were created with unquoted
+// identifiers, which Cassandra stored in lower case.
+func resourceTableStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if _, ok := rawState["quote_identifiers"].(bool); !ok {
+		rawState["quote_identifiers"] = false
+	}
+	return rawState, nil
+}
+
+// validateQuoteIdentifiersChange refuses to change quote_identifiers of an
+// existing table if that changes the names the table is stored under, e.g.
+// for a table created unquoted as Events, which Cassandra stored as events.
+func validateQuoteIdentifiersChange(old, new *Table) error {
+	names := []string{new.Keyspace, new.Name}
+	for _, column := range new.Columns {
+		names = append(names, column.Name)
+	}
+	changed := []string{}
+	for _, name := range names {
+		if old.metadataName(name) != new.metadataName(name) {
+			changed = append(changed, fmt.Sprintf("%s (stored as %s)", name, old.metadataName(name)))
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("changing quote_identifiers of table %s.%s would address %s by a different name. Set quote_identifiers = %t to keep managing the table, or rename them to the names they are stored under", new.Keyspace, new.Name, strings.Join(changed, ", "), old.QuoteIdentifiers)
+}
+
+// twcsWindowUnits are the units of the time windows of
+// TimeWindowCompactionStrategy.
+var twcsWindowUnits = []string{"MINUTES", "HOURS", "DAYS"}
+
+const twcsClass = "TimeWindowCompactionStrategy"
+
+// renderTWCSCompaction renders the twcs block as the compaction option map.
+func renderTWCSCompaction(twcs map[string]interface{}) string {
+	return cql.Map(map[string]string{
+		"class":                  twcsClass,
+		"compaction_window_unit": twcs["compaction_window_unit"].(string),
+		"compaction_window_size": strconv.Itoa(twcs["compaction_window_size"].(int)),
+	})
+}
+
+// flattenTWCSCompaction returns the twcs block of the compaction of a table
+// read from the cluster, or none if it uses another strategy.
+func flattenTWCSCompaction(compaction map[string]string) []interface{} {
+	class := compaction["class"]
+	if class != twcsClass && !strings.HasSuffix(class, "."+twcsClass) {
+		return []interface{}{}
+	}
+	twcs := map[string]interface{}{
+		"compaction_window_unit": "DAYS",
+		"compaction_window_size": 1,
+	}
+	if unit, ok := compaction["compaction_window_unit"]; ok {
+		twcs["compaction_window_unit"] = unit
+	}
+	if size, err := strconv.Atoi(compaction["compaction_window_size"]); err == nil {
+		twcs["compaction_window_size"] = size
+	}
+	return []interface{}{twcs}
+}
+
+// readCompactStorage reads whether a table by its stored name was created
+// WITH COMPACT STORAGE. Amazon Keyspaces has no such tables.
+func readCompactStorage(ctx context.Context, executor CQLExecutor, providerConfig *ProviderConfig, keyspace string, name string) (bool, error) {
+	if providerConfig.Mode == modeAWSKeyspaces {
+		return false, nil
+	}
+	flags, err := cqlschema.ReadTableFlags(ctx, executor, providerConfig.schemaKeyspace(), keyspace, name)
+	if err != nil {
+		return false, err
+	}
+	return cqlschema.CompactStorage(flags), nil
+}
+
+// Table holds everything needed to render the DDL of a cassandra_table.
+type Table struct {
+	Keyspace   string
+	Name       string
+	Columns    []TableColumn
+	RowKeys    []string
+	RangeKeys  []string
+	Properties map[string]string
+	Tags       map[string]string
+
+	QuoteIdentifiers bool
+}
+
+// identifier renders a table or column name for use in CQL.
+func (t *Table) identifier(name string) string {
+	if t.QuoteIdentifiers {
+		return cql.QuoteIdentifier(name)
+	}
+	return name
+}
+
+// metadataName returns the name under which Cassandra stores an identifier,
+// i.e. as-is when quoted and lower-cased otherwise.
+func (t *Table) metadataName(name string) string {
+	if t.QuoteIdentifiers {
+		return name
+	}
+	return strings.ToLower(name)
+}
+
+func (t *Table) identifiers(names []string) []string {
+	ret := make([]string, 0, len(names))
+	for _, name := range names {
+		ret = append(ret, t.identifier(name))
+	}
+	return ret
+}
+
+func (t *Table) qualifiedName() string {
+	return fmt.Sprintf("%s.%s", t.identifier(t.Keyspace), t.identifier(t.Name))
+}
+
+// TableColumn is a single column of a Table. Mask holds the rendered masking
+// function call, e.g. mask_inner(1, null), or is empty for unmasked columns.
+// Key and Position are set when the column declares its primary key role inline.
+type TableColumn struct {
+	Name     string
+	Type     string
+	Mask     string
+	Key      string
+	Position int
+}
+
+type attributeGetter interface {
+	Get(key string) interface{}
+}
+
+type attributeChangeGetter interface {
+	GetChange(key string) (interface{}, interface{})
+}
+
+// oldValueGetter exposes the prior values of a resource so they can be parsed
+// with the same helpers as the planned ones.
+type oldValueGetter struct {
+	d attributeChangeGetter
+}
+
+func (g oldValueGetter) Get(key string) interface{} {
+	o, _ := g.d.GetChange(key)
+	return o
+}
+
+func parseTableData(d attributeGetter, mode string) *Table {
+	table := &Table{
+		Keyspace:   d.Get("keyspace").(string),
+		Name:       d.Get("name").(string),
+		RowKeys:    setToArray(d.Get("row_keys")),
+		RangeKeys:  setToArray(d.Get("range_keys")),
+		Properties: map[string]string{},
+		Tags:       map[string]string{},
+
+		QuoteIdentifiers: d.Get("quote_identifiers").(bool),
+	}
+	for _, raw := range d.Get("attribute").([]interface{}) {
+		attribute := raw.(map[string]interface{})
+		column := TableColumn{
+			Name: attribute["name"].(string),
+			Type: attributeTypeToCQLType[attribute["type"].(string)],
+		}
+		if masks, ok := attribute["mask"].([]interface{}); ok && len(masks) > 0 && masks[0] != nil {
+			column.Mask = renderColumnMask(masks[0].(map[string]interface{}))
+		}
+		if key, ok := attribute["key"].(string); ok {
+			column.Key = key
+		}
+		if position, ok := attribute["position"].(int); ok {
+			column.Position = position
+		}
+		table.Columns = append(table.Columns, column)
+	}
+	table.RowKeys = append(table.RowKeys, inlineKeys(table.Columns, tableKeyPartition)...)
+	table.RangeKeys = append(table.RangeKeys, inlineKeys(table.Columns, tableKeyClustering)...)
+	if twcs, ok := d.Get("twcs").([]interface{}); ok && len(twcs) > 0 && twcs[0] != nil {
+		table.Properties["compaction"] = renderTWCSCompaction(twcs[0].(map[string]interface{}))
+	}
+	if mode == modeAWSKeyspaces {
+		table.Properties["CUSTOM_PROPERTIES"] = renderKeyspacesCustomProperties(d)
+		table.Tags = mapToStringMap(d.Get("tags"))
+	}
+	return table
+}
+
+// suppressAttributeReorder hides attribute diffs that only reorder the same
+// columns, e.g. state written while attribute was still a set.
+func suppressAttributeReorder(k, old, new string, d *schema.ResourceData) bool {
+	o, n := d.GetChange("attribute")
+	return sameStringSet(attributeSignatures(o.([]interface{})), attributeSignatures(n.([]interface{})))
+}
+
+func attributeSignatures(attributes []interface{}) []string {
+	signatures := make([]string, 0, len(attributes))
+	for _, attribute := range attributes {
+		signatures = append(signatures, fmt.Sprintf("%v", attribute))
+	}
+	return signatures
+}
+
+// inlineKeys returns the names of the columns declaring the given key role,
+// ordered by their position.
+func inlineKeys(columns []TableColumn, key string) []string {
+	keyColumns := []TableColumn{}
+	for _, column := range columns {
+		if column.Key == key {
+			keyColumns = append(keyColumns, column)
+		}
+	}
+	sort.SliceStable(keyColumns, func(i, j int) bool {
+		if keyColumns[i].Position != keyColumns[j].Position {
+			return keyColumns[i].Position < keyColumns[j].Position
+		}
+		return keyColumns[i].Name < keyColumns[j].Name
+	})
+
+	names := make([]string, 0, len(keyColumns))
+	for _, column := range keyColumns {
+		names = append(names, column.Name)
+	}
+	return names
+}
+
+// renderKeyspacesCustomProperties renders the Amazon Keyspaces specific table
+// settings as the map literal expected by WITH CUSTOM_PROPERTIES.
+func renderKeyspacesCustomProperties(d attributeGetter) string {
+	properties := []string{}
+	if specs := d.Get("capacity_specification").([]interface{}); len(specs) > 0 && specs[0] != nil {
+		spec := specs[0].(map[string]interface{})
+		capacityMode := `'throughput_mode':` + cql.Literal(spec["throughput_mode"].(string))
+		if spec["throughput_mode"].(string) == keyspacesThroughputModeProvisioned {
+			capacityMode += fmt.Sprintf(`, 'read_capacity_units':%d, 'write_capacity_units':%d`, spec["read_capacity_units"].(int), spec["write_capacity_units"].(int))
+		}
+		properties = append(properties, fmt.Sprintf(`'capacity_mode':{%s}`, capacityMode))
+	}
+
+	pointInTimeRecovery := "disabled"
+	if d.Get("point_in_time_recovery").(bool) {
+		pointInTimeRecovery = "enabled"
+	}
+	properties = append(properties, `'point_in_time_recovery':{'status':`+cql.Literal(pointInTimeRecovery)+`}`)
+
+	return fmt.Sprintf("{%s}", strings.Join(properties, ", "))
+}
+
+func renderTableProperties(properties map[string]string) string {
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	rendered := make([]string, 0, len(keys))
+	for _, key := range keys {
+		rendered = append(rendered, fmt.Sprintf("%s = %s", key, properties[key]))
+	}
+	return strings.Join(rendered, " AND ")
+}
+
+var (
+	// maskFunctionRegex matches the name of a masking function, optionally
+	// qualified with its keyspace.
+	maskFunctionRegex = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*\.)?[A-Za-z_][A-Za-z0-9_]*$`)
+	// maskArgumentRegex matches the CQL literals accepted as arguments of a
+	// masking function, so no other CQL can be injected into the DDL.
+	maskArgumentRegex = regexp.MustCompile(`^('(?:[^']|'')*'|-?[0-9]+(\.[0-9]+)?([eE][+-]?[0-9]+)?|0[xX][0-9A-Fa-f]*|(?i:true|false|null))$`)
+)
+
+// renderColumnMask renders the masking function call of a mask block. The
+// function name is rendered as an identifier and the arguments are validated
+// as CQL literals by the schema.
+func renderColumnMask(mask map[string]interface{}) string {
+	args := []string{}
+	for _, arg := range mask["args"].([]interface{}) {
+		args = append(args, arg.(string))
+	}
+	parts := strings.Split(mask["function"].(string), ".")
+	for i, part := range parts {
+		parts[i] = cql.Identifier(part)
+	}
+	return fmt.Sprintf("%s(%s)", strings.Join(parts, "."), strings.Join(args, ", "))
+}
+
+// validateTableKeys makes sure every row and range key refers to a declared
+// attribute and that the table has a partition key.
+func validateTableKeys(table *Table) error {
+	if len(table.RowKeys) == 0 {
+		return fmt.Errorf("table %s must have at least one row key", table.Name)
+	}
+
+	declared := make(map[string]bool, len(table.Columns))
+	for _, column := range table.Columns {
+		declared[table.metadataName(column.Name)] = true
+	}
+	for _, key := range table.RowKeys {
+		if !declared[table.metadataName(key)] {
+			return fmt.Errorf("row key %s is not a declared attribute of table %s", key, table.Name)
+		}
+	}
+	for _, key := range table.RangeKeys {
+		if !declared[table.metadataName(key)] {
+			return fmt.Errorf("range key %s is not a declared attribute of table %s", key, table.Name)
+		}
+	}
+	return nil
+}
+
+func generateCreateTableQueryString(table *Table) (string, error) {
+	if len(table.RowKeys) == 0 {
+		return "", fmt.Errorf("table %s must have at least one row key", table.Name)
+	}
+
+	fields := make([]string, 0, len(table.Columns)+1)
+	for _, column := range table.Columns {
+		field := fmt.Sprintf("%s %s", table.identifier(column.Name), column.Type)
+		if column.Mask != "" {
+			field += " MASKED WITH " + column.Mask
+		}
+		fields = append(fields, field)
+	}
+
+	primaryKey := fmt.Sprintf("PRIMARY KEY ((%s)", strings.Join(table.identifiers(table.RowKeys), ", "))
+	if len(table.RangeKeys) > 0 {
+		primaryKey += ", " + strings.Join(table.identifiers(table.RangeKeys), ", ")
+	}
+	fields = append(fields, primaryKey+")")
+
+	query := fmt.Sprintf(`CREATE TABLE %s (%s)`, table.qualifiedName(), strings.Join(fields, ", "))
+	properties := make(map[string]string, len(table.Properties)+1)
+	for key, value := range table.Properties {
+		properties[key] = value
+	}
+	if len(table.Tags) > 0 {
+		properties["TAGS"] = cql.Map(table.Tags)
+	}
+	if len(properties) > 0 {
+		query += " WITH " + renderTableProperties(properties)
+	}
+	log.Println("query", query)
+	return query, nil
+}
+
+// generateAlterTableQueryStrings returns the statements needed to move a table
+// from old to new for the changes that can be applied in place.
+func generateAlterTableQueryStrings(old, new *Table) []string {
+	oldColumns := make(map[string]TableColumn, len(old.Columns))
+	for _, column := range old.Columns {
+		oldColumns[column.Name] = column
+	}
+
+	queries := []string{}
+	for _, column := range new.Columns {
+		oldColumn, ok := oldColumns[column.Name]
+		if !ok || oldColumn.Mask == column.Mask {
+			continue
+		}
+		if column.Mask == "" {
+			queries = append(queries, fmt.Sprintf(`ALTER TABLE %s ALTER %s DROP MASKED`, new.qualifiedName(), new.identifier(column.Name)))
+		} else {
+			queries = append(queries, fmt.Sprintf(`ALTER TABLE %s ALTER %s MASKED WITH %s`, new.qualifiedName(), new.identifier(column.Name), column.Mask))
+		}
+	}
+
+	changedProperties := map[string]string{}
+	for key, value := range new.Properties {
+		if old.Properties[key] != value {
+			changedProperties[key] = value
+		}
+	}
+	if len(changedProperties) > 0 {
+		queries = append(queries, fmt.Sprintf(`ALTER TABLE %s WITH %s`, new.qualifiedName(), renderTableProperties(changedProperties)))
+	}
+
+	queries = append(queries, generateAlterTagsQueryStrings("TABLE "+new.qualifiedName(), old.Tags, new.Tags)...)
+	return queries
+}
+
+func normalizeCQLType(cqlType string) string {
+	cqlType = strings.ToLower(strings.ReplaceAll(cqlType, " ", ""))
+	if cqlType == "varchar" {
+		return "text"
+	}
+	return cqlType
+}
+
+func columnNames(columns []*gocql.ColumnMetadata) []string {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, column.Name)
+	}
+	return names
+}
+
+func sameStringSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]bool, len(a))
+	for _, value := range a {
+		seen[value] = true
+	}
+	for _, value := range b {
+		if !seen[value] {
+			return false
+		}
+	}
+	return true
+}
+
+// tableMatchesMetadata reports how an existing table differs from the
+// configured one, or nil if its columns and keys are identical.
+func tableMatchesMetadata(table *Table, metadata *gocql.TableMetadata) error {
+	rowKeys := make([]string, 0, len(table.RowKeys))
+	for _, key := range table.RowKeys {
+		rowKeys = append(rowKeys, table.metadataName(key))
+	}
+	rangeKeys := make([]string, 0, len(table.RangeKeys))
+	for _, key := range table.RangeKeys {
+		rangeKeys = append(rangeKeys, table.metadataName(key))
+	}
+
+	partitionKey := columnNames(metadata.PartitionKey)
+	if !sameStringSet(rowKeys, partitionKey) {
+		return fmt.Errorf("row keys %v do not match partition key %v", table.RowKeys, partitionKey)
+	}
+	clusteringColumns := columnNames(metadata.ClusteringColumns)
+	if !sameStringSet(rangeKeys, clusteringColumns) {
+		return fmt.Errorf("range keys %v do not match clustering columns %v", table.RangeKeys, clusteringColumns)
+	}
+	if len(table.Columns) != len(metadata.Columns) {
+		return fmt.Errorf("expected %d columns, found %d", len(table.Columns), len(metadata.Columns))
+	}
+	for _, column := range table.Columns {
+		existing, ok := metadata.Columns[table.metadataName(column.Name)]
+		if !ok {
+			return fmt.Errorf("column %s does not exist", column.Name)
+		}
+		existingType := cqlschema.ColumnType(existing)
+		if normalizeCQLType(existingType) != normalizeCQLType(column.Type) {
+			return fmt.Errorf("column %s has type %s, expected %s", column.Name, existingType, column.Type)
+		}
+	}
+	return nil
+}
+
+func tableHasRows(ctx context.Context, executor CQLExecutor, table *Table) (bool, error) {
+	rows, err := executor.Select(ctx, fmt.Sprintf(`SELECT * FROM %s LIMIT 1`, table.qualifiedName()))
+	if err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+// waitForKeyspacesTableStatus polls system_schema_mcs.tables until an Amazon
+// Keyspaces table reaches the target status. DDL on Keyspaces is asynchronous,
+// so the table is not usable (or fully gone) when CREATE/DROP returns.
+func waitForKeyspacesTableStatus(ctx context.Context, executor CQLExecutor, keyspace string, name string, target string, timeout time.Duration) error {
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{"CREATING", "UPDATING", "DELETING", "RESTORING"},
+		Target:  []string{target},
+		Refresh: refreshKeyspacesTableStatus(target, func() (string, error) {
+			rows, err := executor.Select(ctx, `SELECT status FROM system_schema_mcs.tables WHERE keyspace_name = ? AND table_name = ?`, keyspace, name)
+			if err != nil {
+				return "", err
+			}
+			if len(rows) == 0 {
+				return "", gocql.ErrNotFound
+			}
+			status, _ := rows[0]["status"].(string)
+			log.Printf("Table '%s' in '%s' has status %s", name, keyspace, status)
+			return status, nil
+		}),
+		Timeout:    timeout,
+		MinTimeout: 2 * time.Second,
+	}
+	_, err := stateConf.WaitForStateContext(ctx)
+	return err
+}
+
+// refreshKeyspacesTableStatus reports the status queried by status. A table
+// that is not listed is deleted when waiting for its deletion; otherwise it
+// may just not be listed yet after CREATE, so it is reported as not found,
+// which keeps waiting for up to NotFoundChecks refreshes.
+func refreshKeyspacesTableStatus(target string, status func() (string, error)) retry.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		value, err := status()
+		if err == gocql.ErrNotFound {
+			if target == keyspacesTableStatusDeleted {
+				return keyspacesTableStatusDeleted, keyspacesTableStatusDeleted, nil
+			}
+			return nil, "", nil
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		return value, value, nil
+	}
+}
+
+// waitForTableVisible blocks until the cluster agrees on the schema and the
+// table shows up in the keyspace metadata, so that resources depending on the
+// table (e.g. grants) do not race the schema propagation.
+func waitForTableVisible(ctx context.Context, executor CQLExecutor, table *Table, timeout time.Duration) error {
+	if err := executor.AwaitSchemaAgreement(ctx); err != nil {
+		log.Printf("Unable to await schema agreement: %s", err)
+	}
+
+	return retry.RetryContext(ctx, timeout, func() *retry.RetryError {
+		keyspaceMetadata, err := executor.KeyspaceMetadata(table.metadataName(table.Keyspace))
+		if err != nil {
+			return retry.RetryableError(err)
+		}
+		if _, ok := keyspaceMetadata.Tables[table.metadataName(table.Name)]; !ok {
+			return retry.RetryableError(fmt.Errorf("table %s is not visible yet", table.qualifiedName()))
+		}
+		return nil
+	})
+}
+
+// requireColumnCapabilities checks that the cluster supports the types and
+// masks of the columns of table.
+func requireColumnCapabilities(ctx context.Context, providerConfig *ProviderConfig, table *Table) error {
+	for _, column := range table.Columns {
+		if isVectorType(column.Type) {
+			if err := providerConfig.requireCapability(ctx, "supports_vectors", fmt.Sprintf("attribute %s of type %s", column.Name, column.Type)); err != nil {
+				return err
+			}
+		}
+		if column.Mask != "" {
+			if err := providerConfig.requireCapability(ctx, "supports_masking", fmt.Sprintf("mask of attribute %s", column.Name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func resourceTableCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	mode := meta.(*ProviderConfig).Mode
+	if mode != modeAWSKeyspaces {
+		if len(d.Get("capacity_specification").([]interface{})) > 0 || d.Get("point_in_time_recovery").(bool) {
+			return fmt.Errorf("capacity_specification and point_in_time_recovery are only supported in %s mode", modeAWSKeyspaces)
+		}
+		if len(d.Get("tags").(map[string]interface{})) > 0 {
+			return fmt.Errorf("tags are only supported in %s mode", modeAWSKeyspaces)
+		}
+	}
+	if specs := d.Get("capacity_specification").([]interface{}); len(specs) > 0 && specs[0] != nil {
+		spec := specs[0].(map[string]interface{})
+		if spec["throughput_mode"].(string) == keyspacesThroughputModeProvisioned && (spec["read_capacity_units"].(int) == 0 || spec["write_capacity_units"].(int) == 0) {
+			return fmt.Errorf("read_capacity_units and write_capacity_units must be set when throughput_mode is %s", keyspacesThroughputModeProvisioned)
+		}
+	}
+
+	if mode == modeAWSKeyspaces && len(d.Get("twcs").([]interface{})) > 0 {
+		return fmt.Errorf("twcs is not supported in %s mode", mode)
+	}
+
+	ddlAttributes := []string{"name", "keyspace", "attribute", "row_keys", "range_keys", "capacity_specification", "point_in_time_recovery", "tags", "twcs"}
+	for _, key := range ddlAttributes {
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("cql")
+		}
+	}
+	table := parseTableData(d, mode)
+	if len(setToArray(d.Get("row_keys"))) > 0 || len(setToArray(d.Get("range_keys"))) > 0 {
+		for _, column := range table.Columns {
+			if column.Key != "" {
+				return fmt.Errorf("attribute %s declares key = %q - use either row_keys/range_keys or inline key declarations, not both", column.Name, column.Key)
+			}
+		}
+	}
+	if err := validateTableKeys(table); err != nil {
+		return err
+	}
+	if d.Id() != "" && d.HasChange("quote_identifiers") {
+		if err := validateQuoteIdentifiersChange(parseTableData(oldValueGetter{d}, mode), table); err != nil {
+			return err
+		}
+	}
+	if d.Id() == "" || d.HasChange("attribute") {
+		if err := requireColumnCapabilities(ctx, meta.(*ProviderConfig), table); err != nil {
+			return err
+		}
+	}
+	if d.Id() != "" && !d.HasChanges(ddlAttributes...) {
+		return nil
+	}
+	if d.Id() != "" && d.Get("compact_storage").(bool) {
+		return fmt.Errorf("table %s.%s was created WITH COMPACT STORAGE, which the provider cannot alter and Cassandra 4.0 and later cannot create, so it is not replaced. Make name, keyspace, attribute, row_keys and range_keys match the table, or run ALTER TABLE ... DROP COMPACT STORAGE first", d.Get("keyspace").(string), d.Get("name").(string))
+	}
+
+	if d.Id() != "" && !d.HasChanges("name", "keyspace", "row_keys", "range_keys") {
+		queries := generateAlterTableQueryStrings(parseTableData(oldValueGetter{d}, mode), table)
+		if len(queries) > 0 {
+			return d.SetNew("cql", strings.Join(queries, ";\n"))
+		}
+	}
+
+	query, err := generateCreateTableQueryString(table)
+	if err != nil {
+		return err
+	}
+	return d.SetNew("cql", query)
 }
 
 func resourceTableCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	var err error
 	name := d.Get("name").(string)
 	keyspaceName := d.Get("keyspace").(string)
-	attributes := d.Get("attribute").(*schema.Set)
+	attributes := d.Get("attribute").([]interface{})
 	rowKeys := setToArray(d.Get("row_keys"))
 	rangeKeys := setToArray(d.Get("range_keys"))
 	var diags diag.Diagnostics
 
 	providerConfig := meta.(*ProviderConfig)
-	cluster := providerConfig.Cluster
-
-	start := time.Now()
-	session, sessionCreateError := cluster.CreateSession()
-	gocqltable.SetDefaultSession(session)
-	elapsed := time.Since(start)
-	log.Printf("Getting a session took %s", elapsed)
+	table := parseTableData(d, providerConfig.Mode)
+	query, err := generateCreateTableQueryString(table)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
-	if sessionCreateError != nil {
-		return diag.FromErr(sessionCreateError)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
 	}
-	defer session.Close()
+	defer executor.Close()
 
-	log.Printf("Creating table '%s' in '%s' with obj: %v ", name, keyspaceName, attributes)
+	var existing *gocql.TableMetadata
+	if d.Get("allow_existing").(bool) {
+		keyspaceMetadata, err := executor.KeyspaceMetadata(table.metadataName(keyspaceName))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		existing = keyspaceMetadata.Tables[table.metadataName(name)]
+	}
 
-	keyspace := gocqltable.NewKeyspace(keyspaceName)
-	resourceTable := keyspace.NewTable(
-		name,
-		rowKeys,
-		rangeKeys,
-		attributes,
-	)
+	if existing != nil {
+		if err := tableMatchesMetadata(table, existing); err != nil {
+			return diag.Errorf("cannot adopt existing table %s.%s: %s", keyspaceName, name, err)
+		}
+		log.Printf("Adopting existing table '%s' in '%s'", name, keyspaceName)
+	} else {
+		log.Printf("Creating table '%s' in '%s' with obj: %v ", name, keyspaceName, attributes)
 
-	err = resourceTable.Create()
-	if err != nil {
-		return diag.FromErr(err)
+		err = executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutCreate))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if providerConfig.ManagedBy != "" {
+			query := fmt.Sprintf(`ALTER TABLE %s WITH comment = %s`, table.qualifiedName(), cql.Literal(managedByMarker(providerConfig.ManagedBy)))
+			if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutCreate)); err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  fmt.Sprintf("table %s.%s is not stamped with managed_by", keyspaceName, name),
+					Detail:   err.Error(),
+				})
+			}
+		}
 	}
 
 	d.SetId(name)
@@ -125,40 +937,47 @@ func resourceTableCreate(ctx context.Context, d *schema.ResourceData, meta inter
 	d.Set("row_keys", rowKeys)
 	d.Set("range_keys", rangeKeys)
 	d.Set("attributes", attributes)
+	d.Set("cql", query)
+	if err := setIdentity(d, "keyspace", "name"); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
 
-	diags = append(diags, resourceTableRead(ctx, d, meta)...)
+	if providerConfig.Mode == modeAWSKeyspaces {
+		if err := waitForKeyspacesTableStatus(ctx, executor, table.metadataName(keyspaceName), table.metadataName(name), keyspacesTableStatusActive, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return append(diags, warnAfterCreate(diag.Errorf("error waiting for table %s.%s to become active: %s", keyspaceName, name, err))...)
+		}
+	} else if err := waitForTableVisible(ctx, executor, table, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return append(diags, warnAfterCreate(diag.Errorf("error waiting for table %s.%s to become visible: %s", keyspaceName, name, err))...)
+	}
+
+	diags = append(diags, warnAfterCreate(resourceTableRead(ctx, d, meta))...)
 	return diags
 }
 
 func resourceTableRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	name := d.Get("name").(string)
 	keyspaceName := d.Get("keyspace").(string)
-	attributes := d.Get("attribute").(*schema.Set)
+	attributes := d.Get("attribute").([]interface{})
 	rowKeys := setToArray(d.Get("row_keys"))
 	rangeKeys := setToArray(d.Get("range_keys"))
 	var diags diag.Diagnostics
 
 	providerConfig := meta.(*ProviderConfig)
-	cluster := providerConfig.Cluster
-
-	start := time.Now()
-	session, sessionCreateError := cluster.CreateSession()
-	elapsed := time.Since(start)
-	log.Printf("Getting a session took %s", elapsed)
-
-	if sessionCreateError != nil {
-		return diag.FromErr(sessionCreateError)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
 	}
-	defer session.Close()
+	defer executor.Close()
 
-	keyspaceMetadata, err := session.KeyspaceMetadata(keyspaceName)
+	table := parseTableData(d, providerConfig.Mode)
+	keyspaceMetadata, err := providerConfig.keyspaceMetadata(executor, table.metadataName(keyspaceName))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	tableExists := false
 	for _, tbl := range keyspaceMetadata.Tables {
-		if tbl.Name == name {
+		if tbl.Name == table.metadataName(name) {
 			log.Printf("Found table '%s' in '%s'", name, keyspaceName)
 			tableExists = true
 			break
@@ -166,7 +985,22 @@ func resourceTableRead(ctx context.Context, d *schema.ResourceData, meta interfa
 	}
 
 	d.SetId(name)
+	if err := setIdentity(d, "keyspace", "name"); err != nil {
+		return diag.FromErr(err)
+	}
 	if tableExists {
+		compactStorage, err := readCompactStorage(ctx, executor, providerConfig, keyspaceName, table.metadataName(name))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("compact_storage", compactStorage)
+		if len(d.Get("twcs").([]interface{})) > 0 {
+			options, err := cqlschema.ReadTableOptions(ctx, executor, providerConfig.schemaKeyspace(), keyspaceName, table.metadataName(name))
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			d.Set("twcs", flattenTWCSCompaction(options.Compaction))
+		}
 		d.Set("name", name)
 		d.Set("keyspace", keyspaceName)
 		d.Set("attributes", attributes)
@@ -177,41 +1011,80 @@ func resourceTableRead(ctx context.Context, d *schema.ResourceData, meta interfa
 	return diags
 }
 
+func resourceTableUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	table := parseTableData(d, providerConfig.Mode)
+	queries := generateAlterTableQueryStrings(parseTableData(oldValueGetter{d}, providerConfig.Mode), table)
+
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	for _, query := range queries {
+		log.Printf("Executing query: %s", query)
+		if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if len(queries) > 0 {
+		d.Set("cql", strings.Join(queries, ";\n"))
+	}
+
+	if len(queries) > 0 && providerConfig.Mode == modeAWSKeyspaces {
+		name := d.Get("name").(string)
+		keyspaceName := d.Get("keyspace").(string)
+		if err := waitForKeyspacesTableStatus(ctx, executor, table.metadataName(keyspaceName), table.metadataName(name), keyspacesTableStatusActive, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.Errorf("error waiting for table %s.%s to become active: %s", keyspaceName, name, err)
+		}
+	}
+
+	diags = append(diags, resourceTableRead(ctx, d, meta)...)
+	return diags
+}
+
 func resourceTableDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	name := d.Get("name").(string)
 	keyspaceName := d.Get("keyspace").(string)
-	attributes := d.Get("attribute").(*schema.Set)
-	rowKeys := setToArray(d.Get("row_keys"))
-	rangeKeys := setToArray(d.Get("range_keys"))
+	attributes := d.Get("attribute").([]interface{})
 	var diags diag.Diagnostics
 
-	providerConfig := meta.(*ProviderConfig)
-	cluster := providerConfig.Cluster
+	if d.Get("deletion_protection").(bool) {
+		return diag.Errorf("cannot drop table %s.%s: deletion_protection is enabled - set it to false and apply before destroying", keyspaceName, name)
+	}
 
-	start := time.Now()
-	session, sessionCreateError := cluster.CreateSession()
-	gocqltable.SetDefaultSession(session)
-	elapsed := time.Since(start)
-	log.Printf("Getting a session took %s", elapsed)
+	providerConfig := meta.(*ProviderConfig)
+	table := parseTableData(d, providerConfig.Mode)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
 
-	if sessionCreateError != nil {
-		return diag.FromErr(sessionCreateError)
+	if d.Get("require_empty_on_destroy").(bool) && !d.Get("force").(bool) {
+		hasRows, err := tableHasRows(ctx, executor, table)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if hasRows {
+			return diag.Errorf("cannot drop table %s.%s: it still contains data - empty it or set force = true", keyspaceName, name)
+		}
 	}
-	defer session.Close()
 
-	keyspace := gocqltable.NewKeyspace(keyspaceName)
 	log.Printf("Deleting table '%s' with obj: %v ", name, attributes)
-	resourceTable := keyspace.NewTable(
-		name,
-		rowKeys,
-		rangeKeys,
-		attributes,
-	)
-
-	err := resourceTable.Drop()
+	err = executor.ExecSchemaChange(ctx, fmt.Sprintf(`DROP TABLE %s`, table.qualifiedName()), d.Timeout(schema.TimeoutDelete))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	if providerConfig.Mode == modeAWSKeyspaces {
+		if err := waitForKeyspacesTableStatus(ctx, executor, table.metadataName(keyspaceName), table.metadataName(name), keyspacesTableStatusDeleted, d.Timeout(schema.TimeoutDelete)); err != nil {
+			return diag.Errorf("error waiting for table %s.%s to be deleted: %s", keyspaceName, name, err)
+		}
+	}
+
 	return diags
 }
@@ -0,0 +1,208 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/konradotto/terraform-provider-cassandra/cqlschema"
+)
+
+func resourceCassandraTableColumn() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Add a single column to an existing table, e.g. one owned by another module or created outside Terraform",
+		CreateContext: resourceTableColumnCreate,
+		ReadContext:   resourceTableColumnRead,
+		DeleteContext: resourceTableColumnDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceTableColumnImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"keyspace": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Keyspace of the table",
+			},
+			"table": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Name of the table to add the column to",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Name of the column",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "CQL type of the column, e.g. text, int or map<text, int>",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"quote_identifiers": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Double-quote the table and column names in generated CQL, keeping their case. Set to false to use unquoted, case-insensitive identifiers",
+			},
+		},
+	}
+}
+
+func parseTableColumnData(d attributeGetter) (*Table, TableColumn) {
+	table := &Table{
+		Keyspace:         d.Get("keyspace").(string),
+		Name:             d.Get("table").(string),
+		QuoteIdentifiers: d.Get("quote_identifiers").(bool),
+	}
+	column := TableColumn{
+		Name: d.Get("name").(string),
+		Type: d.Get("type").(string),
+	}
+	return table, column
+}
+
+func tableColumnID(keyspace, table, name string) string {
+	return fmt.Sprintf("%s.%s.%s", keyspace, table, name)
+}
+
+// parseTableColumnID splits an ID of the form keyspace.table.column.
+func parseTableColumnID(id string) (string, string, string, error) {
+	parts := strings.SplitN(id, ".", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("unexpected format of ID (%s), expected keyspace.table.column", id)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+func generateAddColumnQueryString(table *Table, column TableColumn) string {
+	return fmt.Sprintf(`ALTER TABLE %s ADD %s %s`, table.qualifiedName(), table.identifier(column.Name), column.Type)
+}
+
+func generateDropColumnQueryString(table *Table, column TableColumn) string {
+	return fmt.Sprintf(`ALTER TABLE %s DROP %s`, table.qualifiedName(), table.identifier(column.Name))
+}
+
+func resourceTableColumnImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	keyspaceName, tableName, name, err := parseTableColumnID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.Set("keyspace", keyspaceName)
+	d.Set("table", tableName)
+	d.Set("name", name)
+	d.Set("quote_identifiers", true)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceTableColumnCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	table, column := parseTableColumnData(d)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := generateAddColumnQueryString(table, column)
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(tableColumnID(table.Keyspace, table.Name, column.Name))
+
+	if providerConfig.Mode == modeAWSKeyspaces {
+		if err := waitForKeyspacesTableStatus(ctx, executor, table.metadataName(table.Keyspace), table.metadataName(table.Name), keyspacesTableStatusActive, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return append(diags, warnAfterCreate(diag.Errorf("error waiting for table %s.%s to become active: %s", table.Keyspace, table.Name, err))...)
+		}
+	}
+	diags = append(diags, warnAfterCreate(resourceTableColumnRead(ctx, d, meta))...)
+	return diags
+}
+
+func resourceTableColumnRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	table, column := parseTableColumnData(d)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	keyspaceMetadata, err := providerConfig.keyspaceMetadata(executor, table.metadataName(table.Keyspace))
+	if err == gocql.ErrKeyspaceDoesNotExist {
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tableMetadata, ok := keyspaceMetadata.Tables[table.metadataName(table.Name)]
+	if !ok {
+		log.Printf("Table '%s' in '%s' no longer exists", table.Name, table.Keyspace)
+		d.SetId("")
+		return nil
+	}
+	existing, ok := tableMetadata.Columns[table.metadataName(column.Name)]
+	if !ok {
+		log.Printf("Column '%s' of table '%s' in '%s' no longer exists", column.Name, table.Name, table.Keyspace)
+		d.SetId("")
+		return nil
+	}
+
+	// Keep the configured spelling of the type (e.g. text vs varchar) unless
+	// it actually differs from the one reported by the cluster.
+	if existingType := cqlschema.ColumnType(existing); normalizeCQLType(existingType) != normalizeCQLType(column.Type) {
+		d.Set("type", existingType)
+	}
+	return diags
+}
+
+func resourceTableColumnDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	table, column := parseTableColumnData(d)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := generateDropColumnQueryString(table, column)
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if providerConfig.Mode == modeAWSKeyspaces {
+		if err := waitForKeyspacesTableStatus(ctx, executor, table.metadataName(table.Keyspace), table.metadataName(table.Name), keyspacesTableStatusActive, d.Timeout(schema.TimeoutDelete)); err != nil {
+			return diag.Errorf("error waiting for table %s.%s to become active: %s", table.Keyspace, table.Name, err)
+		}
+	}
+
+	return diags
+}
@@ -0,0 +1,31 @@
+package cassandra
+
+import "testing"
+
+func TestGenerateColumnQueryStrings(t *testing.T) {
+	table := &Table{Keyspace: "some_keyspace", Name: "Events", QuoteIdentifiers: true}
+	column := TableColumn{Name: "userId", Type: "map<text, int>"}
+
+	expected := `ALTER TABLE "some_keyspace"."Events" ADD "userId" map<text, int>`
+	if query := generateAddColumnQueryString(table, column); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+	expected = `ALTER TABLE "some_keyspace"."Events" DROP "userId"`
+	if query := generateDropColumnQueryString(table, column); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+}
+
+func TestParseTableColumnID(t *testing.T) {
+	keyspace, table, name, err := parseTableColumnID(tableColumnID("some_keyspace", "some_table", "some_column"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyspace != "some_keyspace" || table != "some_table" || name != "some_column" {
+		t.Fatalf("unexpected ID parts %q, %q, %q", keyspace, table, name)
+	}
+
+	if _, _, _, err := parseTableColumnID("some_keyspace.some_table"); err == nil {
+		t.Fatal("expected an ID without a column to be rejected")
+	}
+}
@@ -0,0 +1,382 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+	"github.com/konradotto/terraform-provider-cassandra/cqlschema"
+)
+
+var (
+	tableOptionScalars = []string{"comment", "default_time_to_live", "gc_grace_seconds", "speculative_retry"}
+	tableOptionMaps    = []string{"caching", "compaction", "compression"}
+
+	// compactionStrategies lists the built-in compaction classes and the
+	// engines they are available on. Other (custom) classes are not checked.
+	compactionStrategies = map[string]compactionStrategy{
+		"SizeTieredCompactionStrategy":  {Modes: []string{modeCassandra, modeScylla}},
+		"LeveledCompactionStrategy":     {Modes: []string{modeCassandra, modeScylla}},
+		"TimeWindowCompactionStrategy":  {Modes: []string{modeCassandra, modeScylla}},
+		"UnifiedCompactionStrategy":     {Modes: []string{modeCassandra}, MinMajorVersion: 5},
+		"IncrementalCompactionStrategy": {Modes: []string{modeScylla}, MinMajorVersion: scyllaEnterpriseMajorVersion},
+	}
+)
+
+// scyllaEnterpriseMajorVersion is the lowest major version of ScyllaDB
+// Enterprise, which numbers its releases by year (e.g. 2024.1).
+const scyllaEnterpriseMajorVersion = 2000
+
+type compactionStrategy struct {
+	Modes           []string
+	MinMajorVersion int
+}
+
+func resourceCassandraTableOptions() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Manage the WITH options of an existing table without owning its schema. Destroying the resource leaves the options in place",
+		CreateContext: resourceTableOptionsCreate,
+		ReadContext:   resourceTableOptionsRead,
+		UpdateContext: resourceTableOptionsUpdate,
+		DeleteContext: resourceTableOptionsDelete,
+		CustomizeDiff: resourceTableOptionsCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceTableOptionsImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"keyspace": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Keyspace of the table",
+			},
+			"table": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Name of the table whose options are managed",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"quote_identifiers": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Double-quote the table name in generated CQL, keeping its case. Set to false to use an unquoted, case-insensitive identifier",
+			},
+			"comment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Comment of the table. The managed-by marker of the provider argument managed_by is appended to it on the cluster",
+			},
+			"default_time_to_live": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Default TTL of inserted rows in seconds, 0 disables expiry",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"gc_grace_seconds": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Seconds to keep tombstones before they are garbage collected",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"speculative_retry": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Speculative retry policy, e.g. 99p, 50ms or NONE",
+			},
+			"caching": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Caching options, e.g. keys and rows_per_partition. Only the configured keys are tracked",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"compaction": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Compaction options, e.g. class and its sub-options. Only the configured keys are tracked",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"compression": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Compression options, e.g. class and chunk_length_in_kb. Only the configured keys are tracked",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func parseTableOptionsTable(d attributeGetter) *Table {
+	return &Table{
+		Keyspace:         d.Get("keyspace").(string),
+		Name:             d.Get("table").(string),
+		QuoteIdentifiers: d.Get("quote_identifiers").(bool),
+	}
+}
+
+// renderTableOptions renders the given options as WITH properties. Empty maps
+// are skipped since they cannot be applied and mean the option is no longer managed.
+func renderTableOptions(d attributeGetter, keys []string) map[string]string {
+	properties := map[string]string{}
+	for _, key := range keys {
+		switch value := d.Get(key).(type) {
+		case string:
+			properties[key] = cql.Literal(value)
+		case int:
+			properties[key] = fmt.Sprintf("%d", value)
+		case map[string]interface{}:
+			if len(value) > 0 {
+				properties[key] = cql.Map(mapToStringMap(value))
+			}
+		}
+	}
+	return properties
+}
+
+// sameTableOptionValue compares a configured option value with the one
+// reported by the cluster, which expands class names to their full package.
+func sameTableOptionValue(key, configured, existing string) bool {
+	if configured == existing {
+		return true
+	}
+	return key == "class" && strings.HasSuffix(existing, "."+configured)
+}
+
+// trackedTableOptions narrows an option map read from the cluster down to the
+// configured keys, so server-side defaults do not show up as drift.
+func trackedTableOptions(configured map[string]string, existing map[string]string) map[string]string {
+	tracked := make(map[string]string, len(configured))
+	for key, value := range configured {
+		existingValue, ok := existing[key]
+		if !ok {
+			continue
+		}
+		if sameTableOptionValue(key, value, existingValue) {
+			tracked[key] = value
+		} else {
+			tracked[key] = existingValue
+		}
+	}
+	return tracked
+}
+
+// validateCompactionClass checks that a compaction class is available on the
+// given engine. majorVersion is only consulted for classes that need one.
+func validateCompactionClass(class string, mode string, majorVersion func() (int, error)) error {
+	name := class[strings.LastIndex(class, ".")+1:]
+	strategy, ok := compactionStrategies[name]
+	if !ok {
+		return nil
+	}
+
+	supported := false
+	for _, m := range strategy.Modes {
+		if m == mode {
+			supported = true
+		}
+	}
+	if !supported {
+		return fmt.Errorf("compaction class %s is not supported in %s mode, only in %s", name, mode, strings.Join(strategy.Modes, ", "))
+	}
+	if strategy.MinMajorVersion == 0 {
+		return nil
+	}
+
+	version, err := majorVersion()
+	if err != nil {
+		return fmt.Errorf("cannot determine the version of the cluster to validate compaction class %s: %s", name, err)
+	}
+	if version < strategy.MinMajorVersion {
+		if mode == modeScylla {
+			return fmt.Errorf("compaction class %s requires ScyllaDB Enterprise, cluster runs version %d", name, version)
+		}
+		return fmt.Errorf("compaction class %s requires version %d or later, cluster runs version %d", name, strategy.MinMajorVersion, version)
+	}
+	return nil
+}
+
+// clusterMajorVersion returns the major release version of the connected
+// engine. ScyllaDB reports a Cassandra compatible release_version in
+// system.local, so its own version from system.versions is used.
+func clusterMajorVersion(probe *ClusterProbe, mode string) (int, error) {
+	if mode == modeScylla {
+		return parseMajorVersion(probe.ScyllaVersion)
+	}
+	return parseMajorVersion(probe.ReleaseVersion)
+}
+
+// parseMajorVersion returns the major version of a release version, e.g. 4 for 4.1.3.
+func parseMajorVersion(version string) (int, error) {
+	major, err := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+	if err != nil {
+		return 0, fmt.Errorf("unexpected version %s", version)
+	}
+	return major, nil
+}
+
+func resourceTableOptionsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChange("compaction") || !d.NewValueKnown("compaction") {
+		return nil
+	}
+	compaction := mapToStringMap(d.Get("compaction"))
+	if len(compaction) == 0 {
+		return nil
+	}
+
+	providerConfig := meta.(*ProviderConfig)
+	if providerConfig.Mode == modeAWSKeyspaces {
+		return fmt.Errorf("compaction is not supported in %s mode", modeAWSKeyspaces)
+	}
+	class, ok := compaction["class"]
+	if !ok {
+		return nil
+	}
+
+	return validateCompactionClass(class, providerConfig.Mode, func() (int, error) {
+		probe, err := providerConfig.clusterProbe(ctx)
+		if err != nil {
+			return 0, err
+		}
+		return clusterMajorVersion(probe, providerConfig.Mode)
+	})
+}
+
+func parseTableOptionsID(id string) (string, string, error) {
+	parts := strings.SplitN(id, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("unexpected format of ID (%s), expected keyspace.table", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+func resourceTableOptionsImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	keyspaceName, tableName, err := parseTableOptionsID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.Set("keyspace", keyspaceName)
+	d.Set("table", tableName)
+	d.Set("quote_identifiers", true)
+	return []*schema.ResourceData{d}, nil
+}
+
+func alterTableOptions(ctx context.Context, d *schema.ResourceData, meta interface{}, keys []string, timeout time.Duration) diag.Diagnostics {
+	providerConfig := meta.(*ProviderConfig)
+	table := parseTableOptionsTable(d)
+	properties := renderTableOptions(d, keys)
+	if len(properties) == 0 {
+		return nil
+	}
+	if _, ok := properties["comment"]; ok {
+		properties["comment"] = cql.Literal(managedByComment(d.Get("comment").(string), providerConfig.ManagedBy))
+	}
+
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := fmt.Sprintf(`ALTER TABLE %s WITH %s`, table.qualifiedName(), renderTableProperties(properties))
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, timeout); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if providerConfig.Mode == modeAWSKeyspaces {
+		if err := waitForKeyspacesTableStatus(ctx, executor, table.metadataName(table.Keyspace), table.metadataName(table.Name), keyspacesTableStatusActive, timeout); err != nil {
+			return diag.Errorf("error waiting for table %s.%s to become active: %s", table.Keyspace, table.Name, err)
+		}
+	}
+	return nil
+}
+
+func resourceTableOptionsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	keys := []string{}
+	config := d.GetRawConfig()
+	for _, key := range append(tableOptionScalars, tableOptionMaps...) {
+		if !config.GetAttr(key).IsNull() {
+			keys = append(keys, key)
+		}
+	}
+	if diags := alterTableOptions(ctx, d, meta, keys, d.Timeout(schema.TimeoutCreate)); diags.HasError() {
+		return diags
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", d.Get("keyspace").(string), d.Get("table").(string)))
+	diags = append(diags, warnAfterCreate(resourceTableOptionsRead(ctx, d, meta))...)
+	return diags
+}
+
+func resourceTableOptionsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	table := parseTableOptionsTable(d)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	options, err := cqlschema.ReadTableOptions(ctx, executor, providerConfig.schemaKeyspace(), table.metadataName(table.Keyspace), table.metadataName(table.Name))
+	if err == gocql.ErrNotFound {
+		log.Printf("Table '%s' in '%s' no longer exists", table.Name, table.Keyspace)
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("comment", stripManagedByMarker(options.Comment))
+	d.Set("default_time_to_live", options.DefaultTimeToLive)
+	d.Set("gc_grace_seconds", options.GCGraceSeconds)
+	d.Set("speculative_retry", options.SpeculativeRetry)
+	d.Set("caching", trackedTableOptions(mapToStringMap(d.Get("caching")), options.Caching))
+	d.Set("compaction", trackedTableOptions(mapToStringMap(d.Get("compaction")), options.Compaction))
+	d.Set("compression", trackedTableOptions(mapToStringMap(d.Get("compression")), options.Compression))
+	return diags
+}
+
+func resourceTableOptionsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	keys := []string{}
+	for _, key := range append(tableOptionScalars, tableOptionMaps...) {
+		if d.HasChange(key) {
+			keys = append(keys, key)
+		}
+	}
+	if diags := alterTableOptions(ctx, d, meta, keys, d.Timeout(schema.TimeoutUpdate)); diags.HasError() {
+		return diags
+	}
+
+	diags = append(diags, resourceTableOptionsRead(ctx, d, meta)...)
+	return diags
+}
+
+func resourceTableOptionsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("Leaving options of table '%s' in '%s' in place", d.Get("table").(string), d.Get("keyspace").(string))
+	return nil
+}
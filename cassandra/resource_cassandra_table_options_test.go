@@ -0,0 +1,71 @@
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestRenderTableOptions(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCassandraTableOptions().Schema, map[string]interface{}{
+		"keyspace":             "some_keyspace",
+		"table":                "some_table",
+		"comment":              "owner's table",
+		"default_time_to_live": 0,
+		"gc_grace_seconds":     3600,
+		"compaction":           map[string]interface{}{"class": "LeveledCompactionStrategy", "sstable_size_in_mb": "160"},
+	})
+
+	properties := renderTableOptions(d, []string{"comment", "default_time_to_live", "gc_grace_seconds", "compaction", "compression"})
+	expected := "comment = 'owner''s table' AND compaction = {'class':'LeveledCompactionStrategy', 'sstable_size_in_mb':'160'} AND default_time_to_live = 0 AND gc_grace_seconds = 3600"
+	if rendered := renderTableProperties(properties); rendered != expected {
+		t.Fatalf("expected %q, got %q", expected, rendered)
+	}
+}
+
+func TestTrackedTableOptions(t *testing.T) {
+	configured := map[string]string{"class": "LeveledCompactionStrategy", "sstable_size_in_mb": "160"}
+	existing := map[string]string{
+		"class":              "org.apache.cassandra.db.compaction.LeveledCompactionStrategy",
+		"sstable_size_in_mb": "320",
+		"max_threshold":      "32",
+	}
+
+	tracked := trackedTableOptions(configured, existing)
+	if len(tracked) != 2 || tracked["class"] != "LeveledCompactionStrategy" || tracked["sstable_size_in_mb"] != "320" {
+		t.Fatalf("unexpected tracked options %v", tracked)
+	}
+}
+
+func TestValidateCompactionClass(t *testing.T) {
+	version := func(major int) func() (int, error) {
+		return func() (int, error) { return major, nil }
+	}
+
+	if err := validateCompactionClass("org.apache.cassandra.db.compaction.UnifiedCompactionStrategy", modeCassandra, version(5)); err != nil {
+		t.Fatalf("expected UnifiedCompactionStrategy to be accepted on Cassandra 5, got %s", err)
+	}
+	if err := validateCompactionClass("UnifiedCompactionStrategy", modeCassandra, version(4)); err == nil {
+		t.Fatal("expected UnifiedCompactionStrategy to be rejected on Cassandra 4")
+	}
+	if err := validateCompactionClass("UnifiedCompactionStrategy", modeScylla, version(2024)); err == nil {
+		t.Fatal("expected UnifiedCompactionStrategy to be rejected on ScyllaDB")
+	}
+	if err := validateCompactionClass("IncrementalCompactionStrategy", modeScylla, version(5)); err == nil {
+		t.Fatal("expected IncrementalCompactionStrategy to be rejected on ScyllaDB open source")
+	}
+	if err := validateCompactionClass("IncrementalCompactionStrategy", modeScylla, version(2024)); err != nil {
+		t.Fatalf("expected IncrementalCompactionStrategy to be accepted on ScyllaDB Enterprise, got %s", err)
+	}
+
+	unreachable := func() (int, error) {
+		t.Fatal("the cluster version should only be looked up for version dependent classes")
+		return 0, nil
+	}
+	if err := validateCompactionClass("LeveledCompactionStrategy", modeCassandra, unreachable); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateCompactionClass("com.example.CustomCompactionStrategy", modeCassandra, unreachable); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,509 @@
+package cassandra
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestGenerateCreateTableQueryString(t *testing.T) {
+	table := &Table{
+		Keyspace: "some_keyspace",
+		Name:     "some_table",
+		Columns: []TableColumn{
+			{Name: "name", Type: "text"},
+			{Name: "created", Type: "decimal"},
+		},
+		RowKeys:   []string{"name"},
+		RangeKeys: []string{"created"},
+	}
+
+	query, err := generateCreateTableQueryString(table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "CREATE TABLE some_keyspace.some_table (name text, created decimal, PRIMARY KEY ((name), created))"
+	if query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+}
+
+func TestGenerateCreateTableQueryString_noRowKeys(t *testing.T) {
+	table := &Table{
+		Keyspace: "some_keyspace",
+		Name:     "some_table",
+		Columns:  []TableColumn{{Name: "name", Type: "text"}},
+	}
+
+	if _, err := generateCreateTableQueryString(table); err == nil {
+		t.Fatal("expected an error for a table without row keys")
+	}
+}
+
+func TestGenerateCreateTableQueryString_masked(t *testing.T) {
+	table := &Table{
+		Keyspace: "some_keyspace",
+		Name:     "some_table",
+		Columns: []TableColumn{
+			{Name: "name", Type: "text"},
+			{Name: "email", Type: "text", Mask: "mask_inner(1, null)"},
+		},
+		RowKeys: []string{"name"},
+	}
+
+	query, err := generateCreateTableQueryString(table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "CREATE TABLE some_keyspace.some_table (name text, email text MASKED WITH mask_inner(1, null), PRIMARY KEY ((name)))"
+	if query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+}
+
+func TestGenerateAlterTableQueryStrings_masks(t *testing.T) {
+	old := &Table{
+		Keyspace: "some_keyspace",
+		Name:     "some_table",
+		Columns: []TableColumn{
+			{Name: "name", Type: "text"},
+			{Name: "email", Type: "text", Mask: "mask_default()"},
+			{Name: "phone", Type: "text"},
+		},
+	}
+	new := &Table{
+		Keyspace: "some_keyspace",
+		Name:     "some_table",
+		Columns: []TableColumn{
+			{Name: "name", Type: "text"},
+			{Name: "email", Type: "text"},
+			{Name: "phone", Type: "text", Mask: "mask_replace('redacted')"},
+		},
+	}
+
+	queries := generateAlterTableQueryStrings(old, new)
+	expected := []string{
+		"ALTER TABLE some_keyspace.some_table ALTER email DROP MASKED",
+		"ALTER TABLE some_keyspace.some_table ALTER phone MASKED WITH mask_replace('redacted')",
+	}
+	if len(queries) != len(expected) {
+		t.Fatalf("expected %d queries, got %v", len(expected), queries)
+	}
+	for i := range expected {
+		if queries[i] != expected[i] {
+			t.Fatalf("expected %q, got %q", expected[i], queries[i])
+		}
+	}
+}
+
+func TestResourceTableDelete_deletionProtection(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCassandraTableSpace().Schema, map[string]interface{}{
+		"name":                "some_table",
+		"keyspace":            "some_keyspace",
+		"row_keys":            []interface{}{"name"},
+		"attribute":           []interface{}{map[string]interface{}{"name": "name", "type": "S"}},
+		"deletion_protection": true,
+	})
+
+	diags := resourceTableDelete(context.Background(), d, nil)
+	if !diags.HasError() {
+		t.Fatal("expected deletion_protection to prevent the table from being dropped")
+	}
+}
+
+func TestResourceTableDelete_requireEmptyOnDestroy(t *testing.T) {
+	executor := newMockCQLExecutor()
+	d := schema.TestResourceDataRaw(t, resourceCassandraTableSpace().Schema, map[string]interface{}{
+		"name":                     "some_table",
+		"keyspace":                 "some_keyspace",
+		"row_keys":                 []interface{}{"name"},
+		"attribute":                []interface{}{map[string]interface{}{"name": "name", "type": "S"}},
+		"require_empty_on_destroy": true,
+	})
+	executor.rows[`SELECT * FROM "some_keyspace"."some_table" LIMIT 1`] = []map[string]interface{}{{"name": "a"}}
+
+	if diags := resourceTableDelete(context.Background(), d, executor.providerConfig()); !diags.HasError() {
+		t.Fatal("expected a table with rows not to be dropped")
+	}
+	if len(executor.executed) != 0 {
+		t.Fatalf("expected no statements, got %v", executor.executed)
+	}
+
+	executor.rows = map[string][]map[string]interface{}{}
+	if diags := resourceTableDelete(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(executor.executed) != 1 || executor.executed[0] != `DROP TABLE "some_keyspace"."some_table"` {
+		t.Fatalf("expected the empty table to be dropped, got %v", executor.executed)
+	}
+}
+
+func TestResourceTableRead_compactStorage(t *testing.T) {
+	executor := newMockCQLExecutor()
+	d := testResourceDataWithIdentity(t, resourceCassandraTableSpace(), map[string]interface{}{
+		"name":      "some_table",
+		"keyspace":  "some_keyspace",
+		"row_keys":  []interface{}{"name"},
+		"attribute": []interface{}{map[string]interface{}{"name": "name", "type": "S"}},
+	})
+	executor.keyspaces["some_keyspace"] = &gocql.KeyspaceMetadata{
+		Name:   "some_keyspace",
+		Tables: map[string]*gocql.TableMetadata{"some_table": {Keyspace: "some_keyspace", Name: "some_table"}},
+	}
+	executor.rows[`SELECT flags FROM system_schema.tables WHERE keyspace_name = ? AND table_name = ? [some_keyspace some_table]`] = []map[string]interface{}{
+		{"flags": []string{"dense"}},
+	}
+
+	if diags := resourceTableRead(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if !d.Get("compact_storage").(bool) {
+		t.Fatal("expected a dense table to be read as compact storage")
+	}
+}
+
+func TestTableMatchesMetadata(t *testing.T) {
+	name := &gocql.ColumnMetadata{Name: "name", Validator: "varchar", Kind: gocql.ColumnPartitionKey}
+	created := &gocql.ColumnMetadata{Name: "created", Validator: "decimal", Kind: gocql.ColumnClusteringKey}
+	metadata := &gocql.TableMetadata{
+		Keyspace:          "some_keyspace",
+		Name:              "some_table",
+		PartitionKey:      []*gocql.ColumnMetadata{name},
+		ClusteringColumns: []*gocql.ColumnMetadata{created},
+		Columns: map[string]*gocql.ColumnMetadata{
+			"name":    name,
+			"created": created,
+		},
+	}
+	table := &Table{
+		Keyspace: "some_keyspace",
+		Name:     "some_table",
+		Columns: []TableColumn{
+			{Name: "name", Type: "text"},
+			{Name: "created", Type: "decimal"},
+		},
+		RowKeys:   []string{"name"},
+		RangeKeys: []string{"created"},
+	}
+
+	if err := tableMatchesMetadata(table, metadata); err != nil {
+		t.Fatalf("expected table to match, got %s", err)
+	}
+
+	table.Columns[1].Type = "blob"
+	if err := tableMatchesMetadata(table, metadata); err == nil {
+		t.Fatal("expected a type mismatch to be reported")
+	}
+}
+
+func TestRenderKeyspacesCustomProperties(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCassandraTableSpace().Schema, map[string]interface{}{
+		"name":      "some_table",
+		"keyspace":  "some_keyspace",
+		"row_keys":  []interface{}{"name"},
+		"attribute": []interface{}{map[string]interface{}{"name": "name", "type": "S"}},
+		"capacity_specification": []interface{}{map[string]interface{}{
+			"throughput_mode":      "PROVISIONED",
+			"read_capacity_units":  10,
+			"write_capacity_units": 20,
+		}},
+		"point_in_time_recovery": true,
+	})
+
+	query, err := generateCreateTableQueryString(parseTableData(d, modeAWSKeyspaces))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "CREATE TABLE \"some_keyspace\".\"some_table\" (\"name\" text, PRIMARY KEY ((\"name\"))) WITH CUSTOM_PROPERTIES = {'capacity_mode':{'throughput_mode':'PROVISIONED', 'read_capacity_units':10, 'write_capacity_units':20}, 'point_in_time_recovery':{'status':'enabled'}}"
+	if query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+
+	if table := parseTableData(d, modeCassandra); len(table.Properties) != 0 {
+		t.Fatalf("expected no custom properties outside %s mode, got %v", modeAWSKeyspaces, table.Properties)
+	}
+}
+
+func TestGenerateAlterTableQueryStrings_properties(t *testing.T) {
+	old := &Table{
+		Keyspace:   "some_keyspace",
+		Name:       "some_table",
+		Properties: map[string]string{"CUSTOM_PROPERTIES": "{'point_in_time_recovery':{'status':'disabled'}}"},
+	}
+	new := &Table{
+		Keyspace:   "some_keyspace",
+		Name:       "some_table",
+		Properties: map[string]string{"CUSTOM_PROPERTIES": "{'point_in_time_recovery':{'status':'enabled'}}"},
+	}
+
+	queries := generateAlterTableQueryStrings(old, new)
+	expected := "ALTER TABLE some_keyspace.some_table WITH CUSTOM_PROPERTIES = {'point_in_time_recovery':{'status':'enabled'}}"
+	if len(queries) != 1 || queries[0] != expected {
+		t.Fatalf("expected [%q], got %v", expected, queries)
+	}
+}
+
+func TestValidateTableKeys(t *testing.T) {
+	table := &Table{
+		Name:      "some_table",
+		Columns:   []TableColumn{{Name: "name", Type: "text"}, {Name: "created", Type: "decimal"}},
+		RowKeys:   []string{"name"},
+		RangeKeys: []string{"created"},
+	}
+	if err := validateTableKeys(table); err != nil {
+		t.Fatalf("expected keys to be valid, got %s", err)
+	}
+
+	table.RangeKeys = []string{"craeted"}
+	if err := validateTableKeys(table); err == nil {
+		t.Fatal("expected an undeclared range key to be rejected")
+	}
+
+	table.RangeKeys = nil
+	table.RowKeys = nil
+	if err := validateTableKeys(table); err == nil {
+		t.Fatal("expected a table without row keys to be rejected")
+	}
+}
+
+func TestParseTableData_inlineKeys(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCassandraTableSpace().Schema, map[string]interface{}{
+		"name":     "some_table",
+		"keyspace": "some_keyspace",
+		"attribute": []interface{}{
+			map[string]interface{}{"name": "tenant", "type": "S", "key": "partition", "position": 0},
+			map[string]interface{}{"name": "bucket", "type": "N", "key": "partition", "position": 1},
+			map[string]interface{}{"name": "created", "type": "N", "key": "clustering"},
+			map[string]interface{}{"name": "payload", "type": "B"},
+		},
+	})
+
+	table := parseTableData(d, modeCassandra)
+	if len(table.RowKeys) != 2 || table.RowKeys[0] != "tenant" || table.RowKeys[1] != "bucket" {
+		t.Fatalf("expected row keys [tenant bucket], got %v", table.RowKeys)
+	}
+	if len(table.RangeKeys) != 1 || table.RangeKeys[0] != "created" {
+		t.Fatalf("expected range keys [created], got %v", table.RangeKeys)
+	}
+}
+
+func TestGenerateCreateTableQueryString_quotedIdentifiers(t *testing.T) {
+	table := &Table{
+		Keyspace: "some_keyspace",
+		Name:     "Events",
+		Columns: []TableColumn{
+			{Name: "key", Type: "text"},
+			{Name: "userId", Type: "text"},
+		},
+		RowKeys:          []string{"key"},
+		RangeKeys:        []string{"userId"},
+		QuoteIdentifiers: true,
+	}
+
+	query, err := generateCreateTableQueryString(table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `CREATE TABLE "some_keyspace"."Events" ("key" text, "userId" text, PRIMARY KEY (("key"), "userId"))`
+	if query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+	if err := validateTableKeys(table); err != nil {
+		t.Fatal(err)
+	}
+
+	table.RangeKeys = []string{"userid"}
+	if err := validateTableKeys(table); err == nil {
+		t.Fatal("expected quoted identifiers to be matched case-sensitively")
+	}
+}
+
+func TestParseTableData_declarationOrder(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCassandraTableSpace().Schema, map[string]interface{}{
+		"name":     "some_table",
+		"keyspace": "some_keyspace",
+		"row_keys": []interface{}{"name"},
+		"attribute": []interface{}{
+			map[string]interface{}{"name": "name", "type": "S"},
+			map[string]interface{}{"name": "zone", "type": "S"},
+			map[string]interface{}{"name": "amount", "type": "N"},
+		},
+	})
+
+	query, err := generateCreateTableQueryString(parseTableData(d, modeCassandra))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `CREATE TABLE "some_keyspace"."some_table" ("name" text, "zone" text, "amount" decimal, PRIMARY KEY (("name")))`
+	if query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+}
+
+func TestAttributeSignatures_reorder(t *testing.T) {
+	old := []interface{}{
+		map[string]interface{}{"name": "name", "type": "S"},
+		map[string]interface{}{"name": "amount", "type": "N"},
+	}
+	reordered := []interface{}{old[1], old[0]}
+	if !sameStringSet(attributeSignatures(old), attributeSignatures(reordered)) {
+		t.Fatal("expected reordered attributes to be considered equal")
+	}
+
+	changed := []interface{}{old[0], map[string]interface{}{"name": "amount", "type": "B"}}
+	if sameStringSet(attributeSignatures(old), attributeSignatures(changed)) {
+		t.Fatal("expected a changed attribute type to be detected")
+	}
+}
+
+func TestTableDiffOfCompactStorage(t *testing.T) {
+	state := &terraform.InstanceState{ID: "legacy", Attributes: map[string]string{
+		"id":                  "legacy",
+		"name":                "legacy",
+		"keyspace":            "app",
+		"compact_storage":     "true",
+		"quote_identifiers":   "true",
+		"attribute.#":         "0",
+		"row_keys.#":          "0",
+		"range_keys.#":        "0",
+		"deletion_protection": "false",
+	}}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":     "legacy",
+		"keyspace": "app",
+		"attribute": []interface{}{
+			map[string]interface{}{"name": "key", "type": "text"},
+			map[string]interface{}{"name": "value", "type": "blob"},
+		},
+		"row_keys": []interface{}{"key"},
+	})
+
+	_, err := resourceCassandraTableSpace().Diff(context.Background(), state, config, &ProviderConfig{Mode: modeCassandra})
+	if err == nil || !strings.Contains(err.Error(), "COMPACT STORAGE") {
+		t.Fatalf("expected replacing a COMPACT STORAGE table to be refused, got %v", err)
+	}
+}
+
+func TestTWCSCompaction(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCassandraTableSpace().Schema, map[string]interface{}{
+		"name":      "events",
+		"keyspace":  "some_keyspace",
+		"row_keys":  []interface{}{"sensor"},
+		"attribute": []interface{}{map[string]interface{}{"name": "sensor", "type": "S"}},
+		"twcs":      []interface{}{map[string]interface{}{"compaction_window_unit": "HOURS", "compaction_window_size": 6}},
+	})
+
+	query, err := generateCreateTableQueryString(parseTableData(d, modeCassandra))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "CREATE TABLE \"some_keyspace\".\"events\" (\"sensor\" text, PRIMARY KEY ((\"sensor\"))) WITH compaction = {'class':'TimeWindowCompactionStrategy', 'compaction_window_size':'6', 'compaction_window_unit':'HOURS'}"
+	if query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+
+	twcs := flattenTWCSCompaction(map[string]string{
+		"class":                  "org.apache.cassandra.db.compaction.TimeWindowCompactionStrategy",
+		"compaction_window_size": "6",
+		"compaction_window_unit": "HOURS",
+		"max_threshold":          "32",
+	})
+	if len(twcs) != 1 || twcs[0].(map[string]interface{})["compaction_window_size"] != 6 || twcs[0].(map[string]interface{})["compaction_window_unit"] != "HOURS" {
+		t.Fatalf("expected the windows of the table, got %v", twcs)
+	}
+	if twcs := flattenTWCSCompaction(map[string]string{"class": "org.apache.cassandra.db.compaction.SizeTieredCompactionStrategy"}); len(twcs) != 0 {
+		t.Fatalf("expected no twcs block for another strategy, got %v", twcs)
+	}
+}
+
+func TestRefreshKeyspacesTableStatus(t *testing.T) {
+	notListed := func() (string, error) { return "", gocql.ErrNotFound }
+
+	result, state, err := refreshKeyspacesTableStatus(keyspacesTableStatusActive, notListed)()
+	if err != nil || result != nil || state != "" {
+		t.Fatalf("expected a table not listed yet to be not found while waiting for ACTIVE, got %v %q %v", result, state, err)
+	}
+	if _, state, err := refreshKeyspacesTableStatus(keyspacesTableStatusDeleted, notListed)(); err != nil || state != keyspacesTableStatusDeleted {
+		t.Fatalf("expected a table not listed to be deleted while waiting for deletion, got %q %v", state, err)
+	}
+	if _, state, err := refreshKeyspacesTableStatus(keyspacesTableStatusActive, func() (string, error) { return "CREATING", nil })(); err != nil || state != "CREATING" {
+		t.Fatalf("expected the listed status, got %q %v", state, err)
+	}
+}
+
+func TestRenderColumnMask(t *testing.T) {
+	cases := []struct {
+		function string
+		args     []interface{}
+		expected string
+	}{
+		{"mask_default", []interface{}{}, "mask_default()"},
+		{"mask_inner", []interface{}{"1", "null"}, "mask_inner(1, null)"},
+		{"my_keyspace.myMask", []interface{}{"'x'"}, `my_keyspace."myMask"('x')`},
+	}
+	for _, c := range cases {
+		if mask := renderColumnMask(map[string]interface{}{"function": c.function, "args": c.args}); mask != c.expected {
+			t.Errorf("expected %s, got %s", c.expected, mask)
+		}
+	}
+}
+
+func TestMaskValidation(t *testing.T) {
+	for _, function := range []string{"mask_inner", "ks.mask_hash", "MyMask"} {
+		if !maskFunctionRegex.MatchString(function) {
+			t.Errorf("expected function %s to be valid", function)
+		}
+	}
+	for _, function := range []string{"", "mask_null() MASKED WITH x", "mask_inner(1)", "a.b.c", `"quoted"`} {
+		if maskFunctionRegex.MatchString(function) {
+			t.Errorf("expected function %s to be invalid", function)
+		}
+	}
+	for _, arg := range []string{"1", "-2.5", "1e3", "'redacted'", "'it''s'", "0xcafe", "NULL", "true"} {
+		if !maskArgumentRegex.MatchString(arg) {
+			t.Errorf("expected argument %s to be valid", arg)
+		}
+	}
+	for _, arg := range []string{"", "'open", "1) WITH x = (1", "'a' , 'b'", "now()"} {
+		if maskArgumentRegex.MatchString(arg) {
+			t.Errorf("expected argument %s to be invalid", arg)
+		}
+	}
+}
+
+func TestResourceTableStateUpgradeV0(t *testing.T) {
+	state, err := resourceTableStateUpgradeV0(context.Background(), map[string]interface{}{"name": "Events"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state["quote_identifiers"] != false {
+		t.Fatalf("expected a table without quote_identifiers to stay unquoted, got %v", state["quote_identifiers"])
+	}
+
+	state, err = resourceTableStateUpgradeV0(context.Background(), map[string]interface{}{"name": "Events", "quote_identifiers": true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state["quote_identifiers"] != true {
+		t.Fatalf("expected quote_identifiers to be kept, got %v", state["quote_identifiers"])
+	}
+}
+
+func TestValidateQuoteIdentifiersChange(t *testing.T) {
+	old := &Table{Keyspace: "some_keyspace", Name: "events", Columns: []TableColumn{{Name: "sensor", Type: "text"}}}
+	new := &Table{Keyspace: "some_keyspace", Name: "events", Columns: []TableColumn{{Name: "sensor", Type: "text"}}, QuoteIdentifiers: true}
+	if err := validateQuoteIdentifiersChange(old, new); err != nil {
+		t.Fatalf("expected lower-case names to be quoted in place, got %s", err)
+	}
+
+	old.Name, new.Name = "Events", "Events"
+	err := validateQuoteIdentifiersChange(old, new)
+	if err == nil || !strings.Contains(err.Error(), "Events (stored as events)") {
+		t.Fatalf("expected quoting a table created as Events to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+)
+
+func resourceCassandraTrigger() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Create and Delete triggers on tables. The trigger class must be deployed to every node beforehand",
+		CreateContext: resourceTriggerCreate,
+		ReadContext:   resourceTriggerRead,
+		DeleteContext: resourceTriggerDelete,
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if mode := meta.(*ProviderConfig).Mode; mode != modeCassandra {
+				return fmt.Errorf("triggers are not supported in %s mode", mode)
+			}
+			return nil
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceTriggerImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"keyspace": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Keyspace of the table",
+			},
+			"table": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Name of the table the trigger fires on",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Name of the trigger, unique within the table",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"class": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Fully qualified Java class implementing org.apache.cassandra.triggers.ITrigger",
+				ValidateFunc: validation.StringLenBetween(1, 1024),
+			},
+			"quote_identifiers": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Double-quote the trigger and table names in generated CQL, keeping their case. Set to false to use unquoted, case-insensitive identifiers",
+			},
+		},
+	}
+}
+
+// Trigger holds everything needed to render the DDL of a cassandra_trigger.
+type Trigger struct {
+	Keyspace string
+	Table    string
+	Name     string
+	Class    string
+
+	QuoteIdentifiers bool
+}
+
+// table returns a Table for the table the trigger fires on.
+func (t *Trigger) table() *Table {
+	return &Table{Keyspace: t.Keyspace, Name: t.Table, QuoteIdentifiers: t.QuoteIdentifiers}
+}
+
+func parseTriggerData(d attributeGetter) *Trigger {
+	return &Trigger{
+		Keyspace: d.Get("keyspace").(string),
+		Table:    d.Get("table").(string),
+		Name:     d.Get("name").(string),
+		Class:    d.Get("class").(string),
+
+		QuoteIdentifiers: d.Get("quote_identifiers").(bool),
+	}
+}
+
+func generateCreateTriggerQueryString(trigger *Trigger) string {
+	table := trigger.table()
+	return fmt.Sprintf(`CREATE TRIGGER %s ON %s USING %s`, table.identifier(trigger.Name), table.qualifiedName(), cql.Literal(trigger.Class))
+}
+
+func generateDropTriggerQueryString(trigger *Trigger) string {
+	table := trigger.table()
+	return fmt.Sprintf(`DROP TRIGGER %s ON %s`, table.identifier(trigger.Name), table.qualifiedName())
+}
+
+func triggerID(keyspace, table, name string) string {
+	return fmt.Sprintf("%s.%s.%s", keyspace, table, name)
+}
+
+// parseTriggerID splits an ID of the form keyspace.table.trigger.
+func parseTriggerID(id string) (string, string, string, error) {
+	parts := strings.SplitN(id, ".", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("unexpected format of ID (%s), expected keyspace.table.trigger", id)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+func resourceTriggerImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	keyspaceName, tableName, name, err := parseTriggerID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.Set("keyspace", keyspaceName)
+	d.Set("table", tableName)
+	d.Set("name", name)
+	d.Set("quote_identifiers", true)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceTriggerCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	trigger := parseTriggerData(d)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := generateCreateTriggerQueryString(trigger)
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(triggerID(trigger.Keyspace, trigger.Table, trigger.Name))
+	diags = append(diags, warnAfterCreate(resourceTriggerRead(ctx, d, meta))...)
+	return diags
+}
+
+func resourceTriggerRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	trigger := parseTriggerData(d)
+	table := trigger.table()
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	rows, err := executor.Select(ctx, `SELECT options FROM system_schema.triggers WHERE keyspace_name = ? AND table_name = ? AND trigger_name = ?`,
+		table.metadataName(trigger.Keyspace), table.metadataName(trigger.Table), table.metadataName(trigger.Name))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if len(rows) == 0 {
+		log.Printf("Trigger '%s' on '%s' in '%s' no longer exists", trigger.Name, trigger.Table, trigger.Keyspace)
+		d.SetId("")
+		return nil
+	}
+
+	options, _ := rows[0]["options"].(map[string]string)
+	d.Set("class", options["class"])
+	return diags
+}
+
+func resourceTriggerDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	trigger := parseTriggerData(d)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := generateDropTriggerQueryString(trigger)
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
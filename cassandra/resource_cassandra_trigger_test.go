@@ -0,0 +1,77 @@
+package cassandra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestGenerateTriggerQueryStrings(t *testing.T) {
+	trigger := &Trigger{Keyspace: "some_keyspace", Table: "Events", Name: "audit", Class: "com.example.AuditTrigger", QuoteIdentifiers: true}
+
+	expected := `CREATE TRIGGER "audit" ON "some_keyspace"."Events" USING 'com.example.AuditTrigger'`
+	if query := generateCreateTriggerQueryString(trigger); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+	expected = `DROP TRIGGER "audit" ON "some_keyspace"."Events"`
+	if query := generateDropTriggerQueryString(trigger); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+}
+
+func TestParseTriggerID(t *testing.T) {
+	keyspace, table, name, err := parseTriggerID(triggerID("some_keyspace", "some_table", "audit"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyspace != "some_keyspace" || table != "some_table" || name != "audit" {
+		t.Fatalf("unexpected ID parts %q, %q, %q", keyspace, table, name)
+	}
+	if _, _, _, err := parseTriggerID("some_keyspace.some_table"); err == nil {
+		t.Fatal("expected an ID without a trigger to be rejected")
+	}
+}
+
+func TestResourceTriggerLifecycle(t *testing.T) {
+	executor := newMockCQLExecutor()
+	d := schema.TestResourceDataRaw(t, resourceCassandraTrigger().Schema, map[string]interface{}{
+		"keyspace":          "app",
+		"table":             "Events",
+		"name":              "audit",
+		"class":             "com.example.AuditTrigger",
+		"quote_identifiers": true,
+	})
+	executor.rows[`SELECT options FROM system_schema.triggers WHERE keyspace_name = ? AND table_name = ? AND trigger_name = ? [app Events audit]`] = []map[string]interface{}{
+		{"options": map[string]string{"class": "com.example.AuditTriggerV2"}},
+	}
+
+	if diags := resourceTriggerCreate(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if d.Id() != "app.Events.audit" {
+		t.Fatalf("unexpected ID %s", d.Id())
+	}
+	if class := d.Get("class").(string); class != "com.example.AuditTriggerV2" {
+		t.Fatalf("expected the class stored by the cluster to be read, got %s", class)
+	}
+
+	executor.rows = map[string][]map[string]interface{}{}
+	if diags := resourceTriggerRead(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if d.Id() != "" {
+		t.Fatal("expected the trigger to be gone")
+	}
+
+	if diags := resourceTriggerDelete(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	expected := []string{
+		`CREATE TRIGGER "audit" ON "app"."Events" USING 'com.example.AuditTrigger'`,
+		`DROP TRIGGER "audit" ON "app"."Events"`,
+	}
+	if len(executor.executed) != len(expected) || executor.executed[0] != expected[0] || executor.executed[1] != expected[1] {
+		t.Fatalf("expected %v to be executed, got %v", expected, executor.executed)
+	}
+}
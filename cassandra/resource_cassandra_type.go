@@ -0,0 +1,313 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/konradotto/terraform-provider-cassandra/cqlschema"
+)
+
+func resourceCassandraType() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Create and Delete user-defined types within Keyspaces",
+		CreateContext: resourceTypeCreate,
+		ReadContext:   resourceTypeRead,
+		UpdateContext: resourceTypeUpdate,
+		DeleteContext: resourceTypeDelete,
+		CustomizeDiff: resourceTypeCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceTypeImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"keyspace": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Keyspace to create the type within",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Name of the type - must contain between 1 and 256 characters",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"field": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "Fields of the type, in declaration order. Fields appended at the end are added and fields renamed in place are renamed without replacing the type",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "Name of the field",
+							ValidateFunc: validation.StringLenBetween(1, 256),
+						},
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "CQL type of the field, e.g. text, int or frozen<other_type>",
+							ValidateFunc: validation.StringLenBetween(1, 256),
+						},
+					},
+				},
+			},
+			"quote_identifiers": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Double-quote the type and field names in generated CQL, keeping their case. Set to false to use unquoted, case-insensitive identifiers",
+			},
+		},
+	}
+}
+
+// UserType holds everything needed to render the DDL of a cassandra_type.
+type UserType struct {
+	Keyspace string
+	Name     string
+	Fields   []TableColumn
+
+	QuoteIdentifiers bool
+}
+
+// table returns a Table for the type, used to render identifiers.
+func (u *UserType) table() *Table {
+	return &Table{Keyspace: u.Keyspace, Name: u.Name, QuoteIdentifiers: u.QuoteIdentifiers}
+}
+
+func parseUserTypeData(d attributeGetter) *UserType {
+	userType := &UserType{
+		Keyspace: d.Get("keyspace").(string),
+		Name:     d.Get("name").(string),
+
+		QuoteIdentifiers: d.Get("quote_identifiers").(bool),
+	}
+	for _, raw := range d.Get("field").([]interface{}) {
+		field := raw.(map[string]interface{})
+		userType.Fields = append(userType.Fields, TableColumn{
+			Name: field["name"].(string),
+			Type: field["type"].(string),
+		})
+	}
+	return userType
+}
+
+func generateCreateTypeQueryString(userType *UserType) string {
+	table := userType.table()
+	fields := make([]string, 0, len(userType.Fields))
+	for _, field := range userType.Fields {
+		fields = append(fields, fmt.Sprintf("%s %s", table.identifier(field.Name), field.Type))
+	}
+	return fmt.Sprintf(`CREATE TYPE %s (%s)`, table.qualifiedName(), strings.Join(fields, ", "))
+}
+
+func generateDropTypeQueryString(userType *UserType) string {
+	return fmt.Sprintf(`DROP TYPE %s`, userType.table().qualifiedName())
+}
+
+// canAlterUserType tells whether new can be reached from old with ALTER TYPE:
+// existing fields keep their position and type, new fields are appended.
+func canAlterUserType(old, new *UserType) bool {
+	if len(new.Fields) < len(old.Fields) {
+		return false
+	}
+	for i, field := range old.Fields {
+		if normalizeCQLType(field.Type) != normalizeCQLType(new.Fields[i].Type) {
+			return false
+		}
+	}
+	return true
+}
+
+// generateAlterTypeQueryStrings returns the statements renaming the fields
+// whose name changed in place, followed by those adding the appended fields.
+func generateAlterTypeQueryStrings(old, new *UserType) []string {
+	table := new.table()
+	queries := []string{}
+	for i, field := range old.Fields {
+		if field.Name != new.Fields[i].Name {
+			queries = append(queries, fmt.Sprintf(`ALTER TYPE %s RENAME %s TO %s`, table.qualifiedName(), table.identifier(field.Name), table.identifier(new.Fields[i].Name)))
+		}
+	}
+	for _, field := range new.Fields[len(old.Fields):] {
+		queries = append(queries, fmt.Sprintf(`ALTER TYPE %s ADD %s %s`, table.qualifiedName(), table.identifier(field.Name), field.Type))
+	}
+	return queries
+}
+
+func resourceTypeCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("field") || !d.NewValueKnown("field") {
+		return nil
+	}
+	if !canAlterUserType(parseUserTypeData(oldValueGetter{d}), parseUserTypeData(d)) {
+		return d.ForceNew("field")
+	}
+	return nil
+}
+
+// userTypeFields pairs the field_names and field_types stored in system_schema.types.
+func userTypeFields(names []string, types []string) []TableColumn {
+	fields := make([]TableColumn, 0, len(names))
+	for i, fieldName := range names {
+		field := TableColumn{Name: fieldName}
+		if i < len(types) {
+			field.Type = types[i]
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// userTypeFieldsMatch compares configured fields with the ones stored by the
+// cluster, ignoring the spelling of equivalent types such as text and varchar.
+func userTypeFieldsMatch(table *Table, configured []TableColumn, existing []cqlschema.Field) bool {
+	if len(configured) != len(existing) {
+		return false
+	}
+	for i := range configured {
+		if table.metadataName(configured[i].Name) != existing[i].Name ||
+			normalizeCQLType(configured[i].Type) != normalizeCQLType(existing[i].Type) {
+			return false
+		}
+	}
+	return true
+}
+
+func flattenUserTypeFields(fields []cqlschema.Field) []interface{} {
+	ret := make([]interface{}, 0, len(fields))
+	for _, field := range fields {
+		ret = append(ret, map[string]interface{}{
+			"name": field.Name,
+			"type": field.Type,
+		})
+	}
+	return ret
+}
+
+func parseTypeID(id string) (string, string, error) {
+	parts := strings.SplitN(id, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("unexpected format of ID (%s), expected keyspace.type", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+func resourceTypeImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	keyspaceName, name, err := parseTypeID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.Set("keyspace", keyspaceName)
+	d.Set("name", name)
+	d.Set("quote_identifiers", true)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceTypeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	userType := parseUserTypeData(d)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := generateCreateTypeQueryString(userType)
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", userType.Keyspace, userType.Name))
+	diags = append(diags, warnAfterCreate(resourceTypeRead(ctx, d, meta))...)
+	return diags
+}
+
+func resourceTypeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	userType := parseUserTypeData(d)
+	table := userType.table()
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	fields, err := cqlschema.ReadUserTypeFields(ctx, executor, providerConfig.schemaKeyspace(), table.metadataName(userType.Keyspace), table.metadataName(userType.Name))
+	if err == gocql.ErrNotFound {
+		log.Printf("Type '%s' in '%s' no longer exists", userType.Name, userType.Keyspace)
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Keep the configured spelling of the fields unless they actually differ
+	// from the ones stored by the cluster.
+	if !userTypeFieldsMatch(table, userType.Fields, fields) {
+		d.Set("field", flattenUserTypeFields(fields))
+	}
+	return diags
+}
+
+func resourceTypeUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	queries := generateAlterTypeQueryStrings(parseUserTypeData(oldValueGetter{d}), parseUserTypeData(d))
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	for _, query := range queries {
+		log.Printf("Executing query: %s", query)
+		if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	diags = append(diags, resourceTypeRead(ctx, d, meta)...)
+	return diags
+}
+
+func resourceTypeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	userType := parseUserTypeData(d)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := generateDropTypeQueryString(userType)
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
@@ -0,0 +1,122 @@
+package cassandra
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/konradotto/terraform-provider-cassandra/cqlschema"
+)
+
+func TestGenerateTypeQueryStrings(t *testing.T) {
+	userType := &UserType{
+		Keyspace: "some_keyspace",
+		Name:     "Address",
+		Fields: []TableColumn{
+			{Name: "street", Type: "text"},
+			{Name: "zipCode", Type: "int"},
+			{Name: "phones", Type: "set<text>"},
+		},
+		QuoteIdentifiers: true,
+	}
+
+	expected := `CREATE TYPE "some_keyspace"."Address" ("street" text, "zipCode" int, "phones" set<text>)`
+	if query := generateCreateTypeQueryString(userType); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+	expected = `DROP TYPE "some_keyspace"."Address"`
+	if query := generateDropTypeQueryString(userType); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+}
+
+func TestUserTypeFieldsMatch(t *testing.T) {
+	table := &Table{}
+	configured := []TableColumn{{Name: "Street", Type: "varchar"}, {Name: "phones", Type: "set<text>"}}
+
+	if !userTypeFieldsMatch(table, configured, []cqlschema.Field{{Name: "street", Type: "text"}, {Name: "phones", Type: "set<text>"}}) {
+		t.Fatal("expected fields differing only in spelling to match")
+	}
+	if userTypeFieldsMatch(table, configured, []cqlschema.Field{{Name: "phones", Type: "set<text>"}, {Name: "street", Type: "text"}}) {
+		t.Fatal("expected reordered fields not to match")
+	}
+	if userTypeFieldsMatch(table, configured, []cqlschema.Field{{Name: "street", Type: "text"}}) {
+		t.Fatal("expected a missing field not to match")
+	}
+}
+
+func TestGenerateAlterTypeQueryStrings(t *testing.T) {
+	old := &UserType{
+		Keyspace: "some_keyspace",
+		Name:     "address",
+		Fields:   []TableColumn{{Name: "street", Type: "text"}, {Name: "zip", Type: "text"}},
+	}
+	new := &UserType{
+		Keyspace: "some_keyspace",
+		Name:     "address",
+		Fields:   []TableColumn{{Name: "street", Type: "varchar"}, {Name: "zip_code", Type: "text"}, {Name: "phones", Type: "set<text>"}},
+	}
+
+	if !canAlterUserType(old, new) {
+		t.Fatal("expected a rename and an appended field to be applied in place")
+	}
+	expected := []string{
+		`ALTER TYPE some_keyspace.address RENAME zip TO zip_code`,
+		`ALTER TYPE some_keyspace.address ADD phones set<text>`,
+	}
+	queries := generateAlterTypeQueryStrings(old, new)
+	if len(queries) != len(expected) {
+		t.Fatalf("expected %d queries, got %v", len(expected), queries)
+	}
+	for i := range expected {
+		if queries[i] != expected[i] {
+			t.Fatalf("expected %q, got %q", expected[i], queries[i])
+		}
+	}
+
+	if canAlterUserType(new, old) {
+		t.Fatal("expected a removed field to require replacing the type")
+	}
+	new.Fields[1].Type = "int"
+	if canAlterUserType(old, new) {
+		t.Fatal("expected a changed field type to require replacing the type")
+	}
+}
+
+func TestResourceTypeLifecycle(t *testing.T) {
+	executor := newMockCQLExecutor()
+	d := schema.TestResourceDataRaw(t, resourceCassandraType().Schema, map[string]interface{}{
+		"keyspace": "app",
+		"name":     "address",
+		"field": []interface{}{
+			map[string]interface{}{"name": "street", "type": "text"},
+			map[string]interface{}{"name": "zip", "type": "int"},
+		},
+	})
+	executor.rows[`SELECT field_names, field_types FROM system_schema.types WHERE keyspace_name = ? AND type_name = ? [app address]`] = []map[string]interface{}{
+		{"field_names": []string{"street", "zip", "city"}, "field_types": []string{"text", "int", "text"}},
+	}
+
+	if diags := resourceTypeCreate(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if d.Id() != "app.address" {
+		t.Fatalf("unexpected ID %s", d.Id())
+	}
+	if fields := d.Get("field").([]interface{}); len(fields) != 3 {
+		t.Fatalf("expected the fields stored by the cluster to be read, got %v", fields)
+	}
+
+	executor.rows = map[string][]map[string]interface{}{}
+	if diags := resourceTypeRead(context.Background(), d, executor.providerConfig()); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if d.Id() != "" {
+		t.Fatal("expected the type to be gone")
+	}
+	if len(executor.executed) != 1 || !strings.HasPrefix(executor.executed[0], "CREATE TYPE ") {
+		t.Fatalf("expected one CREATE TYPE, got %v", executor.executed)
+	}
+}
@@ -0,0 +1,154 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+)
+
+func resourceCassandraUser() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Manage Users within clusters predating roles (Cassandra 2.1 and earlier)",
+		CreateContext: resourceUserCreate,
+		ReadContext:   resourceUserRead,
+		UpdateContext: resourceUserUpdate,
+		DeleteContext: resourceUserDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Name of user",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"super_user": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Allow user to create and manage other users",
+			},
+			"password": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Sensitive:    true,
+				Description:  "Password for user when using Cassandra internal authentication",
+				ValidateFunc: validation.StringLenBetween(1, 512),
+			},
+		},
+	}
+}
+
+func generateUserQueryString(action string, name string, password string, superUser bool) string {
+	superUserOption := "NOSUPERUSER"
+	if superUser {
+		superUserOption = "SUPERUSER"
+	}
+	return fmt.Sprintf(`%s USER %s WITH PASSWORD %s %s`, action, cql.Literal(name), cql.Literal(password), superUserOption)
+}
+
+// readUser reads a user from the users table of clusters predating roles and
+// falls back to the roles table once the cluster has migrated to roles, which
+// drops the users table.
+func readUser(ctx context.Context, executor CQLExecutor, name string, systemKeyspace string) (string, bool, error) {
+	query := fmt.Sprintf("SELECT name, super FROM %s.users WHERE name = ?", systemKeyspace)
+	rows, err := executor.Select(ctx, query, name)
+	if err == nil && len(rows) > 0 {
+		user, _ := rows[0]["name"].(string)
+		isSuperUser, _ := rows[0]["super"].(bool)
+		return user, isSuperUser, nil
+	} else if err == nil {
+		return "", false, fmt.Errorf("cannot read user with name %s", name)
+	}
+
+	log.Printf("Reading users failed (%s), reading roles instead", err)
+	role, _, isSuperUser, _, err := readRole(ctx, executor, name, systemKeyspace)
+	if err != nil {
+		return "", false, fmt.Errorf("cannot read user with name %s", name)
+	}
+	return role, isSuperUser, nil
+}
+
+func resourceUserCreateOrUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}, createUser bool) diag.Diagnostics {
+	name := d.Get("name").(string)
+	superUser := d.Get("super_user").(bool)
+	password := d.Get("password").(string)
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	action := "CREATE"
+	if !createUser {
+		action = "ALTER"
+	}
+	query := generateUserQueryString(action, name, password, superUser)
+	log.Printf("Executing query: %s USER '%s'", action, name)
+	if err := executor.Exec(ctx, query); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(name)
+	diags = append(diags, warnAfterCreate(resourceUserRead(ctx, d, meta))...)
+	return diags
+}
+
+func resourceUserCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceUserCreateOrUpdate(ctx, d, meta, true)
+}
+
+func resourceUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Id()
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.sharedExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	user, superUser, err := readUser(ctx, executor, name, providerConfig.authKeyspace(ctx, executor))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("name", user)
+	d.Set("super_user", superUser)
+	return diags
+}
+
+func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceUserCreateOrUpdate(ctx, d, meta, false)
+}
+
+func resourceUserDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := fmt.Sprintf(`DROP USER %s`, cql.Literal(name))
+	log.Printf("Executing query: %s", query)
+	if err := executor.Exec(ctx, query); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
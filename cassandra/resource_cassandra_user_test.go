@@ -0,0 +1,40 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGenerateUserQueryString(t *testing.T) {
+	for query, expected := range map[string]string{
+		generateUserQueryString("CREATE", "app_user", "s3cr3t", false): `CREATE USER 'app_user' WITH PASSWORD 's3cr3t' NOSUPERUSER`,
+		generateUserQueryString("ALTER", "admin", "it's", true):        `ALTER USER 'admin' WITH PASSWORD 'it''s' SUPERUSER`,
+	} {
+		if query != expected {
+			t.Fatalf("expected %q, got %q", expected, query)
+		}
+	}
+}
+
+func TestReadUser(t *testing.T) {
+	executor := newMockCQLExecutor()
+	executor.rows[`SELECT name, super FROM system_auth.users WHERE name = ? [admin]`] = []map[string]interface{}{
+		{"name": "admin", "super": true},
+	}
+	if user, superUser, err := readUser(context.Background(), executor, "admin", "system_auth"); err != nil || user != "admin" || !superUser {
+		t.Fatalf("expected the superuser admin, got %s, %t, %v", user, superUser, err)
+	}
+	if _, _, err := readUser(context.Background(), executor, "missing", "system_auth"); err == nil {
+		t.Fatal("expected reading a missing user to fail")
+	}
+
+	// Clusters migrated to roles have no users table.
+	executor.errors[`SELECT name, super FROM system_auth.users WHERE name = ?`] = errors.New("unconfigured table users")
+	executor.rows[`SELECT role, can_login, is_superuser, salted_hash FROM system_auth.roles WHERE role = ? [app]`] = []map[string]interface{}{
+		{"role": "app", "can_login": true, "is_superuser": false},
+	}
+	if user, superUser, err := readUser(context.Background(), executor, "app", "system_auth"); err != nil || user != "app" || superUser {
+		t.Fatalf("expected the role app, got %s, %t, %v", user, superUser, err)
+	}
+}
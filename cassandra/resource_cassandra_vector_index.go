@@ -0,0 +1,263 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/konradotto/terraform-provider-cassandra/cqlschema"
+)
+
+// vectorSourceModels are the embedding models a vector index can be tuned for.
+var vectorSourceModels = []string{"OTHER", "ADA002", "OPENAI_V3_SMALL", "OPENAI_V3_LARGE", "BERT", "GECKO", "NV_QA_4", "COHERE_V3"}
+
+func resourceCassandraVectorIndex() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Create and Delete Storage-Attached Indexes on vector columns for approximate nearest neighbor (ANN) search",
+		CreateContext: resourceVectorIndexCreate,
+		ReadContext:   resourceVectorIndexRead,
+		DeleteContext: resourceVectorIndexDelete,
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			providerConfig := meta.(*ProviderConfig)
+			if mode := providerConfig.Mode; mode != modeCassandra {
+				return fmt.Errorf("vector indexes are not supported in %s mode", mode)
+			}
+			if d.Id() == "" {
+				return providerConfig.requireCapability(ctx, "supports_vectors", "cassandra_vector_index")
+			}
+			return nil
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceVectorIndexImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"keyspace": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Keyspace of the table",
+			},
+			"table": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Name of the indexed table",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"column": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Name of the indexed column, which must be of a vector type such as vector<float, 1536>",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				Description:  "Name of the index, unique within the keyspace. Generated by the cluster when omitted, e.g. table_column_idx",
+				ValidateFunc: validation.StringLenBetween(1, 256),
+			},
+			"similarity_function": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "COSINE",
+				Description:      fmt.Sprintf("Function comparing vectors - one of %s", strings.Join(saiSimilarityFunctions, ", ")),
+				ValidateFunc:     validation.StringInSlice(saiSimilarityFunctions, true),
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool { return strings.EqualFold(old, new) },
+			},
+			"source_model": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Description:      fmt.Sprintf("Embedding model the vectors come from, tuning the index for it - one of %s. Supported by DataStax Enterprise, HCD and Astra", strings.Join(vectorSourceModels, ", ")),
+				ValidateFunc:     validation.StringInSlice(vectorSourceModels, true),
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool { return strings.EqualFold(old, new) },
+			},
+			"quote_identifiers": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Double-quote the index, table and column names in generated CQL, keeping their case. Set to false to use unquoted, case-insensitive identifiers",
+			},
+		},
+	}
+}
+
+// parseVectorIndexData returns the Storage-Attached Index a cassandra_vector_index renders to.
+func parseVectorIndexData(d attributeGetter) *Index {
+	options := map[string]string{
+		"similarity_function": strings.ToUpper(d.Get("similarity_function").(string)),
+	}
+	if model := d.Get("source_model").(string); model != "" {
+		options["source_model"] = strings.ToUpper(model)
+	}
+	return &Index{
+		Keyspace: d.Get("keyspace").(string),
+		Table:    d.Get("table").(string),
+		Column:   d.Get("column").(string),
+		Name:     d.Get("name").(string),
+		Type:     indexTypeSAI,
+		Options:  options,
+
+		QuoteIdentifiers: d.Get("quote_identifiers").(bool),
+	}
+}
+
+// isVectorType tells whether a CQL type as stored in system_schema.columns is a vector.
+func isVectorType(cqlType string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(cqlType)), "vector<")
+}
+
+// queryColumnType reads the type of a column by the stored names of its table
+// and itself. It returns gocql.ErrNotFound if the column does not exist.
+func queryColumnType(ctx context.Context, executor CQLExecutor, keyspace string, table string, column string) (string, error) {
+	rows, err := executor.Select(ctx, `SELECT type FROM system_schema.columns WHERE keyspace_name = ? AND table_name = ? AND column_name = ?`, keyspace, table, column)
+	if err != nil {
+		return "", err
+	}
+	if len(rows) == 0 {
+		return "", gocql.ErrNotFound
+	}
+	cqlType, _ := rows[0]["type"].(string)
+	return cqlType, nil
+}
+
+func resourceVectorIndexImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	keyspaceName, name, err := parseIndexID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.Set("keyspace", keyspaceName)
+	d.Set("name", name)
+	d.Set("quote_identifiers", true)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceVectorIndexCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	index := parseVectorIndexData(d)
+	table := index.table()
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	cqlType, err := queryColumnType(ctx, executor, table.metadataName(index.Keyspace), table.metadataName(index.Table), table.metadataName(index.Column))
+	if err == gocql.ErrNotFound {
+		return diag.Errorf("column %s does not exist in %s", index.Column, table.qualifiedName())
+	} else if err != nil {
+		return diag.FromErr(err)
+	}
+	if !isVectorType(cqlType) {
+		return diag.Errorf("column %s of %s is of type %s, expected a vector type", index.Column, table.qualifiedName(), cqlType)
+	}
+
+	query := generateCreateIndexQueryString(index)
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Look up the name the cluster picked when none was configured.
+	name := table.metadataName(index.Name)
+	if name == "" {
+		if name, err = lookupIndexName(ctx, executor, index); err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("name", name)
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", index.Keyspace, name))
+	diags = append(diags, warnAfterCreate(resourceVectorIndexRead(ctx, d, meta))...)
+	return diags
+}
+
+func resourceVectorIndexRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	index := parseVectorIndexData(d)
+	table := index.table()
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	indexes, err := cqlschema.ReadIndexes(ctx, executor, table.metadataName(index.Keyspace), "")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var existing *cqlschema.Index
+	for _, candidate := range indexes {
+		if candidate.Name == table.metadataName(index.Name) {
+			existing = candidate
+		}
+	}
+	if existing == nil {
+		log.Printf("Index '%s' in '%s' no longer exists", index.Name, index.Keyspace)
+		d.SetId("")
+		return nil
+	}
+	if indexType := indexTypeFromClass(existing.Options["class_name"]); indexType != indexTypeSAI {
+		return diag.Errorf("index %s in %s is a %s index, expected a %s index", index.Name, index.Keyspace, indexType, indexTypeSAI)
+	}
+
+	// Keep the configured spelling of names unless they actually differ from
+	// the ones stored by the cluster.
+	if table.metadataName(index.Table) != existing.Table {
+		d.Set("table", existing.Table)
+	}
+	if _, column := parseIndexTarget(existing.Options["target"]); table.metadataName(index.Column) != column {
+		d.Set("column", column)
+	}
+	// Indexes created without a similarity function use cosine similarity.
+	function := strings.ToUpper(existing.Options["similarity_function"])
+	if function == "" {
+		function = "COSINE"
+	}
+	if function != index.Options["similarity_function"] {
+		d.Set("similarity_function", function)
+	}
+	if model := strings.ToUpper(existing.Options["source_model"]); model != index.Options["source_model"] {
+		d.Set("source_model", model)
+	}
+	return diags
+}
+
+func resourceVectorIndexDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	providerConfig := meta.(*ProviderConfig)
+	index := parseVectorIndexData(d)
+	executor, err := providerConfig.newExecutor()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer executor.Close()
+
+	query := generateDropIndexQueryString(index)
+	log.Printf("Executing query: %s", query)
+	if err := executor.ExecSchemaChange(ctx, query, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
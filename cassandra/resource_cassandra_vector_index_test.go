@@ -0,0 +1,48 @@
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestGenerateVectorIndexQueryString(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCassandraVectorIndex().Schema, map[string]interface{}{
+		"keyspace":            "some_keyspace",
+		"table":               "products",
+		"column":              "embedding",
+		"name":                "products_ann",
+		"similarity_function": "dot_product",
+		"source_model":        "openai_v3_small",
+	})
+
+	expected := `CREATE CUSTOM INDEX "products_ann" ON "some_keyspace"."products" ("embedding") USING 'StorageAttachedIndex' WITH OPTIONS = {'similarity_function':'DOT_PRODUCT', 'source_model':'OPENAI_V3_SMALL'}`
+	if query := generateCreateIndexQueryString(parseVectorIndexData(d)); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceCassandraVectorIndex().Schema, map[string]interface{}{
+		"keyspace": "some_keyspace",
+		"table":    "products",
+		"column":   "embedding",
+	})
+
+	expected = `CREATE CUSTOM INDEX ON "some_keyspace"."products" ("embedding") USING 'StorageAttachedIndex' WITH OPTIONS = {'similarity_function':'COSINE'}`
+	if query := generateCreateIndexQueryString(parseVectorIndexData(d)); query != expected {
+		t.Fatalf("expected %q, got %q", expected, query)
+	}
+}
+
+func TestIsVectorType(t *testing.T) {
+	for cqlType, expected := range map[string]bool{
+		"vector<float, 1536>": true,
+		"VECTOR<float, 3>":    true,
+		"list<float>":         false,
+		"frozen<list<float>>": false,
+		"blob":                false,
+	} {
+		if isVectorType(cqlType) != expected {
+			t.Fatalf("expected isVectorType(%q) to be %t", cqlType, expected)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package cassandra
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// stringIdentity returns the resource identity of a resource addressed by
+// string attributes. The required ones must be given to import it by
+// identity, the optional ones only where they apply.
+func stringIdentity(required []string, optional []string) *schema.ResourceIdentity {
+	return &schema.ResourceIdentity{
+		SchemaFunc: func() map[string]*schema.Schema {
+			attributes := map[string]*schema.Schema{}
+			for _, name := range required {
+				attributes[name] = &schema.Schema{Type: schema.TypeString, RequiredForImport: true}
+			}
+			for _, name := range optional {
+				attributes[name] = &schema.Schema{Type: schema.TypeString, OptionalForImport: true}
+			}
+			return attributes
+		},
+	}
+}
+
+// setIdentity copies the non-empty attributes of a resource to its identity.
+func setIdentity(d *schema.ResourceData, attributes ...string) error {
+	identity, err := d.Identity()
+	if err != nil {
+		return err
+	}
+	for _, name := range attributes {
+		if value := d.Get(name).(string); value != "" {
+			if err := identity.Set(name, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// importStateWithIdentity returns an import function for resources that can
+// be imported by ID or by identity. Imported by identity, the attributes of
+// the identity are copied to the resource and id derives its ID from them.
+// The import then continues with next, unless it is nil.
+func importStateWithIdentity(attributes []string, id func(d *schema.ResourceData) (string, error), next schema.StateContextFunc) schema.StateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+		if d.Id() == "" {
+			identity, err := d.Identity()
+			if err != nil {
+				return nil, err
+			}
+			for _, name := range attributes {
+				if value, ok := identity.GetOk(name); ok {
+					d.Set(name, value)
+				}
+			}
+			resourceID, err := id(d)
+			if err != nil {
+				return nil, err
+			}
+			d.SetId(resourceID)
+		}
+
+		if next == nil {
+			return []*schema.ResourceData{d}, nil
+		}
+		return next(ctx, d, meta)
+	}
+}
+
+// nameID derives the ID of a resource identified by its name.
+func nameID(d *schema.ResourceData) (string, error) {
+	return d.Get("name").(string), nil
+}
@@ -0,0 +1,58 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// testResourceDataWithIdentity is schema.TestResourceDataRaw for resources
+// with an identity, whose schema TestResourceDataRaw leaves out.
+func testResourceDataWithIdentity(t *testing.T, r *schema.Resource, raw map[string]interface{}) *schema.ResourceData {
+	t.Helper()
+	d := schema.TestResourceDataRaw(t, r.Schema, raw)
+	d.SetId("pending")
+	state := d.State()
+	state.ID = ""
+	return r.Data(state)
+}
+
+func TestImportStateWithIdentity(t *testing.T) {
+	r := resourceCassandraTableSpace()
+	d := schema.TestResourceDataWithIdentityRaw(t, r.Schema, r.Identity.SchemaMap(), map[string]string{"keyspace": "app", "name": "events"})
+
+	imported, err := r.Importer.StateContext(context.Background(), d, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(imported) != 1 || d.Id() != "events" || d.Get("keyspace").(string) != "app" || d.Get("name").(string) != "events" {
+		t.Fatalf("expected table app.events to be imported, got ID %s", d.Id())
+	}
+}
+
+func TestResourceGrantImport(t *testing.T) {
+	r := resourceCassandraGrant()
+	d := schema.TestResourceDataWithIdentityRaw(t, r.Schema, r.Identity.SchemaMap(), map[string]string{
+		identifierPrivilege:    privilegeSelect,
+		identifierGrantee:      "reporting",
+		identifierResourceType: resourceTable,
+		identifierKeyspaceName: "app",
+		identifierTableName:    "events",
+	})
+
+	if _, err := r.Importer.StateContext(context.Background(), d, nil); err != nil {
+		t.Fatal(err)
+	}
+	expected := hash(fmt.Sprintf("%+v", &Grant{privilegeSelect, resourceTable, "reporting", "app", "events"}))
+	if d.Id() != expected {
+		t.Fatalf("expected ID %s, got %s", expected, d.Id())
+	}
+
+	d = r.Data(&terraform.InstanceState{ID: expected})
+	if _, err := r.Importer.StateContext(context.Background(), d, nil); err == nil {
+		t.Fatal("expected a grant imported by its hashed ID to be rejected")
+	}
+}
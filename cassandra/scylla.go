@@ -0,0 +1,51 @@
+package cassandra
+
+import (
+	"context"
+	"log"
+)
+
+// scyllaAuthKeyspace keeps the roles, role_members and role_permissions
+// tables from ScyllaDB 6.0 on, which manages them with Raft (auth v2).
+const scyllaAuthKeyspace = "system"
+
+// authKeyspace returns the keyspace of the roles, role_members and
+// role_permissions tables. Unless system_keyspace_name is set, ScyllaDB is
+// asked once whether it keeps them in system or, before 6.0, in system_auth.
+func (c *ProviderConfig) authKeyspace(ctx context.Context, executor CQLExecutor) string {
+	if c.Mode != modeScylla || c.SystemKeyspaceName != "" {
+		return c.SystemKeyspaceName
+	}
+
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.authKeyspaceName != "" {
+		return c.authKeyspaceName
+	}
+	rows, err := executor.Select(ctx, `SELECT table_name FROM system_schema.tables WHERE keyspace_name = ? AND table_name = 'roles'`, scyllaAuthKeyspace)
+	switch {
+	case err == nil && len(rows) > 0:
+		c.authKeyspaceName = scyllaAuthKeyspace
+	case err == nil:
+		c.authKeyspaceName = defaultSystemKeyspace
+	default:
+		log.Printf("[WARN] Finding the roles table failed (%s), assuming %s", err, defaultSystemKeyspace)
+		return defaultSystemKeyspace
+	}
+	log.Printf("ScyllaDB keeps roles in %s", c.authKeyspaceName)
+	return c.authKeyspaceName
+}
+
+// systemView returns the table of a virtual table of system_views, e.g.
+// settings or clients. ScyllaDB has no system_views and lists the same in
+// system, e.g. its configuration in system.config.
+func systemView(mode string, name string) string {
+	if mode != modeScylla {
+		return "system_views." + name
+	}
+	if name == "settings" {
+		return "system.config"
+	}
+	return "system." + name
+}
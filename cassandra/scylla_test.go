@@ -0,0 +1,51 @@
+package cassandra
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSystemView(t *testing.T) {
+	cases := []struct {
+		mode     string
+		name     string
+		expected string
+	}{
+		{modeCassandra, "settings", "system_views.settings"},
+		{modeCassandra, "clients", "system_views.clients"},
+		{modeScylla, "settings", "system.config"},
+		{modeScylla, "clients", "system.clients"},
+	}
+	for _, c := range cases {
+		if actual := systemView(c.mode, c.name); actual != c.expected {
+			t.Fatalf("expected %s for %s in %s mode, got %s", c.expected, c.name, c.mode, actual)
+		}
+	}
+}
+
+func TestAuthKeyspaceConfigured(t *testing.T) {
+	for _, mode := range []string{modeCassandra, modeScylla} {
+		providerConfig := &ProviderConfig{Mode: mode, SystemKeyspaceName: "custom_auth"}
+		if keyspace := providerConfig.authKeyspace(context.Background(), nil); keyspace != "custom_auth" {
+			t.Fatalf("expected the configured keyspace in %s mode, got %s", mode, keyspace)
+		}
+	}
+}
+
+func TestAuthKeyspaceScylla(t *testing.T) {
+	query := "SELECT table_name FROM system_schema.tables WHERE keyspace_name = ? AND table_name = 'roles' [system]"
+	for _, c := range []struct {
+		rows     []map[string]interface{}
+		expected string
+	}{
+		{[]map[string]interface{}{{"table_name": "roles"}}, scyllaAuthKeyspace},
+		{nil, defaultSystemKeyspace},
+	} {
+		executor := newMockCQLExecutor()
+		executor.rows[query] = c.rows
+		providerConfig := &ProviderConfig{Mode: modeScylla}
+		if keyspace := providerConfig.authKeyspace(context.Background(), executor); keyspace != c.expected {
+			t.Fatalf("expected %s, got %s", c.expected, keyspace)
+		}
+	}
+}
@@ -1,13 +1,36 @@
 package cassandra
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
 	"hash/crc32"
+	"log"
+	"strings"
+	"time"
 
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
 )
 
+// defaultSchemaChangeTimeout bounds the retries of schema changes issued by
+// resources without configurable timeouts.
+const defaultSchemaChangeTimeout = 2 * time.Minute
+
+// concurrentSchemaChangeErrors are fragments of the errors returned when a
+// schema change races another one. They go away once the cluster converges.
+var concurrentSchemaChangeErrors = []string{
+	"column family id mismatch",
+	"schema version mismatch",
+	"cluster schema versions not consistent",
+	"schema agreement",
+}
+
 func hash(s string) string {
 	sha := sha256.Sum256([]byte(s))
 	return hex.EncodeToString(sha[:])
@@ -36,3 +59,104 @@ func setToArray(s interface{}) []string {
 	}
 	return ret
 }
+
+func listToArray(l interface{}) []string {
+	list, ok := l.([]interface{})
+	if !ok {
+		return []string{}
+	}
+
+	ret := []string{}
+	for _, elem := range list {
+		ret = append(ret, elem.(string))
+	}
+	return ret
+}
+
+func mapToStringMap(m interface{}) map[string]string {
+	ret := map[string]string{}
+	raw, ok := m.(map[string]interface{})
+	if !ok {
+		return ret
+	}
+	for key, value := range raw {
+		ret[key] = value.(string)
+	}
+	return ret
+}
+
+// generateAlterTagsQueryStrings returns the ADD TAGS / DROP TAGS statements
+// moving the tags of target (e.g. "KEYSPACE ks" or "TABLE ks.tbl") from old to new.
+func generateAlterTagsQueryStrings(target string, old, new map[string]string) []string {
+	added := map[string]string{}
+	for key, value := range new {
+		if oldValue, ok := old[key]; !ok || oldValue != value {
+			added[key] = value
+		}
+	}
+	removed := map[string]string{}
+	for key, value := range old {
+		if _, ok := new[key]; !ok {
+			removed[key] = value
+		}
+	}
+
+	queries := []string{}
+	if len(removed) > 0 {
+		queries = append(queries, fmt.Sprintf(`ALTER %s DROP TAGS %s`, target, cql.Map(removed)))
+	}
+	if len(added) > 0 {
+		queries = append(queries, fmt.Sprintf(`ALTER %s ADD TAGS %s`, target, cql.Map(added)))
+	}
+	return queries
+}
+
+func isConcurrentSchemaChangeError(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, fragment := range concurrentSchemaChangeErrors {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// execSchemaChange executes a DDL statement. When it fails because another
+// client changed the schema concurrently, it waits for schema agreement and
+// retries with backoff until the timeout expires.
+func execSchemaChange(ctx context.Context, session *gocql.Session, query string, timeout time.Duration) error {
+	return retry.RetryContext(ctx, timeout, func() *retry.RetryError {
+		err := session.Query(query).WithContext(ctx).Exec()
+		if err == nil {
+			return nil
+		}
+		if !isConcurrentSchemaChangeError(err) {
+			return retry.NonRetryableError(err)
+		}
+
+		log.Printf("Schema change raced a concurrent one, retrying: %s", err)
+		if err := session.AwaitSchemaAgreement(ctx); err != nil {
+			log.Printf("Unable to await schema agreement: %s", err)
+		}
+		return retry.RetryableError(err)
+	})
+}
+
+// createdObjectDetail explains the warnings of warnAfterCreate.
+const createdObjectDetail = "The object was created on the cluster, but the steps following its creation failed. It is kept in the state so the next refresh reconciles it."
+
+// warnAfterCreate turns the errors of the steps following a successful
+// create, e.g. reading the object back, into warnings. Errors would leave the
+// object on the cluster either tainted or, without an ID, unmanaged.
+func warnAfterCreate(diags diag.Diagnostics) diag.Diagnostics {
+	for i := range diags {
+		if diags[i].Severity != diag.Error {
+			continue
+		}
+		diags[i].Severity = diag.Warning
+		if diags[i].Detail == "" {
+			diags[i].Detail = createdObjectDetail
+		}
+	}
+	return diags
+}
@@ -0,0 +1,34 @@
+package cassandra
+
+import (
+	"log"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// sharedSession returns the session the provider shares between the reads
+// of roles and grants. gocql keeps the statements it prepared per session,
+// keyed by query text, so refreshing thousands of roles and grants through
+// one session prepares each of their queries once instead of once per
+// resource. The session stays open for the lifetime of the provider and must
+// not be closed by callers.
+func (c *ProviderConfig) sharedSession() (*gocql.Session, error) {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+
+	if c.session != nil && !c.session.Closed() {
+		return c.session, nil
+	}
+
+	start := time.Now()
+	session, err := c.Cluster.CreateSession()
+	elapsed := time.Since(start)
+	log.Printf("Getting the shared session took %s", elapsed)
+
+	if err != nil {
+		return nil, err
+	}
+	c.session = session
+	return session, nil
+}
@@ -0,0 +1,19 @@
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/gocql/gocql"
+)
+
+func TestSharedSessionRetriesFailedConnections(t *testing.T) {
+	providerConfig := &ProviderConfig{Cluster: gocql.NewCluster()}
+	for i := 0; i < 2; i++ {
+		if _, err := providerConfig.sharedSession(); err != gocql.ErrNoHosts {
+			t.Fatalf("expected %s, got %v", gocql.ErrNoHosts, err)
+		}
+		if providerConfig.session != nil {
+			t.Fatal("expected a failed session not to be shared")
+		}
+	}
+}
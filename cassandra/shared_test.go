@@ -0,0 +1,22 @@
+package cassandra
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsConcurrentSchemaChangeError(t *testing.T) {
+	retryable := []error{
+		errors.New("Column family ID mismatch (found 5e0d8c40-...; expected 5d8e1b20-...)"),
+		errors.New("gocql: cluster schema versions not consistent: [...]"),
+	}
+	for _, err := range retryable {
+		if !isConcurrentSchemaChangeError(err) {
+			t.Fatalf("expected %q to be retried", err)
+		}
+	}
+
+	if isConcurrentSchemaChangeError(errors.New("Cannot add already existing table \"some_table\" to keyspace \"some_keyspace\"")) {
+		t.Fatal("expected an already existing table not to be retried")
+	}
+}
@@ -0,0 +1,250 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+)
+
+// testAccResourcePrefix starts the names of the roles, keyspaces and tables
+// created by acceptance tests, so the sweepers can tell them apart from
+// anything else living in a shared test cluster.
+const testAccResourcePrefix = "tf_acc_"
+
+// TestMain runs the sweepers when the tests are run with -sweep, e.g.
+// go test ./cassandra -v -sweep=all, and the tests otherwise, starting a
+// server container for acceptance tests if no CASSANDRA_HOST is given.
+func TestMain(m *testing.M) {
+	resource.TestMain(testAccRunner{m: m})
+}
+
+func init() {
+	resource.AddTestSweepers("cassandra_grant", &resource.Sweeper{
+		Name: "cassandra_grant",
+		F:    sweepGrants,
+	})
+	resource.AddTestSweepers("cassandra_role", &resource.Sweeper{
+		Name:         "cassandra_role",
+		Dependencies: []string{"cassandra_grant"},
+		F:            sweepRoles,
+	})
+	resource.AddTestSweepers("cassandra_table", &resource.Sweeper{
+		Name: "cassandra_table",
+		F:    sweepTables,
+	})
+	resource.AddTestSweepers("cassandra_keyspace", &resource.Sweeper{
+		Name:         "cassandra_keyspace",
+		Dependencies: []string{"cassandra_table", "cassandra_grant"},
+		F:            sweepKeyspaces,
+	})
+}
+
+// sweeperSession connects to the cluster configured by the CASSANDRA_*
+// environment variables, as the acceptance tests do. Clusters have no
+// regions, so the region of the sweeper is ignored.
+func sweeperSession() (*gocql.Session, *ProviderConfig, error) {
+	provider := Provider()
+	if diags := provider.Configure(context.Background(), terraform.NewResourceConfigRaw(nil)); diags.HasError() {
+		return nil, nil, fmt.Errorf("configuring the provider: %v", diags)
+	}
+	providerConfig := provider.Meta().(*ProviderConfig)
+	session, err := providerConfig.Cluster.CreateSession()
+	if err != nil {
+		return nil, nil, err
+	}
+	return session, providerConfig, nil
+}
+
+func isSweepable(name string) bool {
+	return strings.HasPrefix(name, testAccResourcePrefix)
+}
+
+func querySweepableRoles(session *gocql.Session, systemKeyspace string) ([]string, error) {
+	iter := session.Query(fmt.Sprintf(`SELECT role FROM %s.roles`, systemKeyspace)).Iter()
+	roles := []string{}
+	var role string
+	for iter.Scan(&role) {
+		if isSweepable(role) {
+			roles = append(roles, role)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// permissionResourceCQL converts a resource as listed by LIST PERMISSIONS,
+// e.g. <table app.events>, to the resource of a REVOKE statement.
+func permissionResourceCQL(listed string) (string, error) {
+	target := strings.TrimSuffix(strings.TrimPrefix(listed, "<"), ">")
+	for _, prefix := range []string{"all keyspaces", "all roles", "all functions in ", "all functions", "all mbeans"} {
+		if strings.HasPrefix(target, prefix) {
+			if prefix == "all functions in " {
+				return "ALL FUNCTIONS IN KEYSPACE " + strings.TrimPrefix(target, prefix), nil
+			}
+			return strings.ToUpper(prefix), nil
+		}
+	}
+	for _, kind := range []string{"keyspace", "table", "role", "function", "mbean"} {
+		if strings.HasPrefix(target, kind+" ") {
+			return strings.ToUpper(kind) + " " + strings.TrimPrefix(target, kind+" "), nil
+		}
+	}
+	return "", fmt.Errorf("unknown permission resource %s", listed)
+}
+
+// sweepGrants revokes the permissions granted to test roles, and those
+// granted to any role on test keyspaces and tables.
+func sweepGrants(region string) error {
+	session, _, err := sweeperSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	iter := session.Query(`LIST ALL PERMISSIONS`).Iter()
+	revokes := []string{}
+	row := map[string]interface{}{}
+	for iter.MapScan(row) {
+		role, _ := row["role"].(string)
+		listed, _ := row["resource"].(string)
+		permission, _ := row["permission"].(string)
+		row = map[string]interface{}{}
+
+		if !isSweepable(role) && !strings.Contains(listed, " "+testAccResourcePrefix) {
+			continue
+		}
+		target, err := permissionResourceCQL(listed)
+		if err != nil {
+			log.Printf("[WARN] Not revoking %s on %s from %s: %s", permission, listed, role, err)
+			continue
+		}
+		revokes = append(revokes, fmt.Sprintf(`REVOKE %s ON %s FROM %s`, permission, target, cql.QuoteIdentifier(role)))
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	for _, query := range revokes {
+		log.Printf("Executing query: %s", query)
+		if err := session.Query(query).Exec(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sweepRoles(region string) error {
+	session, providerConfig, err := sweeperSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	roles, err := querySweepableRoles(session, providerConfig.authKeyspace(context.Background(), &gocqlExecutor{session: session}))
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		query := fmt.Sprintf(`DROP ROLE IF EXISTS %s`, cql.QuoteIdentifier(role))
+		log.Printf("Executing query: %s", query)
+		if err := session.Query(query).Exec(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sweepTables drops the test tables of the keyspaces that are not swept
+// themselves.
+func sweepTables(region string) error {
+	session, _, err := sweeperSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	iter := session.Query(`SELECT keyspace_name, table_name FROM system_schema.tables`).Iter()
+	queries := []string{}
+	var keyspace, table string
+	for iter.Scan(&keyspace, &table) {
+		if isSystemKeyspace(keyspace) || isSweepable(keyspace) || !isSweepable(table) {
+			continue
+		}
+		queries = append(queries, fmt.Sprintf(`DROP TABLE IF EXISTS %s.%s`, cql.QuoteIdentifier(keyspace), cql.QuoteIdentifier(table)))
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	for _, query := range queries {
+		log.Printf("Executing query: %s", query)
+		if err := execSchemaChange(context.Background(), session, query, defaultSchemaChangeTimeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sweepKeyspaces(region string) error {
+	session, _, err := sweeperSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	iter := session.Query(`SELECT keyspace_name FROM system_schema.keyspaces`).Iter()
+	queries := []string{}
+	var keyspace string
+	for iter.Scan(&keyspace) {
+		if isSystemKeyspace(keyspace) || !isSweepable(keyspace) {
+			continue
+		}
+		queries = append(queries, fmt.Sprintf(`DROP KEYSPACE IF EXISTS %s`, cql.QuoteIdentifier(keyspace)))
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	for _, query := range queries {
+		log.Printf("Executing query: %s", query)
+		if err := execSchemaChange(context.Background(), session, query, defaultSchemaChangeTimeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestPermissionResourceCQL(t *testing.T) {
+	cases := map[string]string{
+		"<all keyspaces>":            "ALL KEYSPACES",
+		"<keyspace tf_acc_keyspace>": "KEYSPACE tf_acc_keyspace",
+		"<table tf_acc_keyspace.t>":  "TABLE tf_acc_keyspace.t",
+		"<all functions in app>":     "ALL FUNCTIONS IN KEYSPACE app",
+		"<function app.f(int)>":      "FUNCTION app.f(int)",
+		"<role tf_acc_user>":         "ROLE tf_acc_user",
+		"<all roles>":                "ALL ROLES",
+	}
+	for listed, expected := range cases {
+		actual, err := permissionResourceCQL(listed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != expected {
+			t.Fatalf("expected %s for %s, got %s", expected, listed, actual)
+		}
+	}
+
+	if _, err := permissionResourceCQL("<data>"); err == nil {
+		t.Fatal("expected an unknown resource to be rejected")
+	}
+}
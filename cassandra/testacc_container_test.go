@@ -0,0 +1,252 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// testAccServer is a server version the acceptance tests can run against in
+// a container.
+type testAccServer struct {
+	Image string
+	// Args are passed to the entrypoint of the image.
+	Args []string
+	// Setup runs in the container before the server starts.
+	Setup string
+}
+
+// cassandraAuthSetup enables authentication and authorization, which the
+// role and grant resources need, in the cassandra.yaml of the official
+// images. Both the 3.x/4.x and the 5.0 format of the settings are matched.
+const cassandraAuthSetup = `sed -i -e 's/AllowAllAuthenticator/PasswordAuthenticator/' -e 's/AllowAllAuthorizer/CassandraAuthorizer/' /etc/cassandra/cassandra.yaml`
+
+// cassandraArgs are the default command of the official images, which is
+// dropped when the entrypoint is overridden to run the setup.
+var cassandraArgs = []string{"cassandra", "-f"}
+
+// scyllaArgs run a ScyllaDB container on one shard with authentication and
+// authorization enabled.
+var scyllaArgs = []string{"--smp", "1", "--overprovisioned", "1", "--authenticator", "PasswordAuthenticator", "--authorizer", "CassandraAuthorizer"}
+
+// testAccServers are the servers selectable with CASSANDRA_TEST_SERVER.
+var testAccServers = map[string]testAccServer{
+	"cassandra-3.11": {Image: "cassandra:3.11", Args: cassandraArgs, Setup: cassandraAuthSetup},
+	"cassandra-4.1":  {Image: "cassandra:4.1", Args: cassandraArgs, Setup: cassandraAuthSetup},
+	"cassandra-5.0":  {Image: "cassandra:5.0", Args: cassandraArgs, Setup: cassandraAuthSetup},
+	"scylla":         {Image: "scylladb/scylla:5.4", Args: scyllaArgs},
+	// scylla-6.2 keeps roles and permissions in system instead of system_auth.
+	"scylla-6.2": {Image: "scylladb/scylla:6.2", Args: scyllaArgs},
+}
+
+const (
+	defaultTestAccServer     = "cassandra-4.1"
+	testAccContainerPort     = "9042/tcp"
+	testAccContainerStartup  = 5 * time.Minute
+	testAccContainerUsername = "cassandra"
+	testAccContainerPassword = "cassandra"
+)
+
+// testAccServerName is the server the acceptance tests run against, empty
+// when they use the cluster of CASSANDRA_HOST.
+var testAccServerName string
+
+// testAccRunner starts a container for the acceptance tests of the package
+// before running them and removes it afterwards, so all tests of a package
+// share one server.
+type testAccRunner struct {
+	m *testing.M
+}
+
+func (r testAccRunner) Run() int {
+	stop, err := startTestAccContainer()
+	if err != nil {
+		log.Printf("[ERROR] Starting the acceptance test container failed: %s", err)
+		return 1
+	}
+	defer stop()
+	return r.m.Run()
+}
+
+// startTestAccContainer starts the server of CASSANDRA_TEST_SERVER and points
+// the CASSANDRA_* variables of the provider at it. Nothing is started unless
+// acceptance tests run and no CASSANDRA_HOST is given.
+func startTestAccContainer() (func(), error) {
+	if os.Getenv("TF_ACC") == "" || os.Getenv("CASSANDRA_HOST") != "" {
+		return func() {}, nil
+	}
+
+	name := os.Getenv("CASSANDRA_TEST_SERVER")
+	if name == "" {
+		name = defaultTestAccServer
+	}
+	server, ok := testAccServers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown CASSANDRA_TEST_SERVER %s, expected one of %s", name, strings.Join(testAccServerNames(), ", "))
+	}
+
+	ctx := context.Background()
+	log.Printf("Starting %s for the acceptance tests", server.Image)
+	container, err := testcontainers.Run(ctx, server.Image, server.options()...)
+	if err != nil {
+		// The container is returned, and must be removed, even if it did not start.
+		if err := testcontainers.TerminateContainer(container); err != nil {
+			log.Printf("[WARN] Removing the %s container failed: %s", server.Image, err)
+		}
+		return nil, fmt.Errorf("starting %s: %s", server.Image, err)
+	}
+	stop := func() {
+		// Ryuk, the reaper of testcontainers, still removes the container
+		// unless TESTCONTAINERS_RYUK_DISABLED is set as well.
+		if os.Getenv("CASSANDRA_TEST_KEEP_CONTAINER") != "" {
+			log.Printf("Keeping container %s", container.GetContainerID())
+			return
+		}
+		if err := testcontainers.TerminateContainer(container); err != nil {
+			log.Printf("[WARN] Removing container %s failed: %s", container.GetContainerID(), err)
+		}
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		stop()
+		return nil, err
+	}
+	mappedPort, err := container.MappedPort(ctx, testAccContainerPort)
+	if err != nil {
+		stop()
+		return nil, err
+	}
+	port := mappedPort.Port()
+
+	if err := waitForTestAccServer(host, port); err != nil {
+		stop()
+		return nil, fmt.Errorf("%s did not accept connections: %s", server.Image, err)
+	}
+
+	testAccServerName = name
+	os.Setenv("CASSANDRA_HOST", host)
+	os.Setenv("CASSANDRA_PORT", port)
+	os.Setenv("CASSANDRA_USERNAME", testAccContainerUsername)
+	os.Setenv("CASSANDRA_PASSWORD", testAccContainerPassword)
+	return stop, nil
+}
+
+// options returns the container request of the server, which runs the setup
+// before the entrypoint of the image if there is one.
+func (s testAccServer) options() []testcontainers.ContainerCustomizer {
+	options := []testcontainers.ContainerCustomizer{
+		testcontainers.WithExposedPorts(testAccContainerPort),
+		testcontainers.WithWaitStrategy(wait.ForListeningPort(testAccContainerPort).WithStartupTimeout(testAccContainerStartup)),
+	}
+	if s.Setup == "" {
+		return append(options, testcontainers.WithCmd(s.Args...))
+	}
+	script := s.Setup + ` && exec docker-entrypoint.sh "$@"`
+	return append(options,
+		testcontainers.WithEntrypoint("sh", "-c", script, "sh"),
+		testcontainers.WithCmd(s.Args...),
+	)
+}
+
+func testAccServerNames() []string {
+	names := make([]string, 0, len(testAccServers))
+	for name := range testAccServers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// waitForTestAccServer waits until the server accepts logins, which happens
+// a while after it starts listening, once the default superuser is created.
+func waitForTestAccServer(host string, port string) error {
+	cluster := gocql.NewCluster(host + ":" + port)
+	cluster.Authenticator = gocql.PasswordAuthenticator{
+		Username: testAccContainerUsername,
+		Password: testAccContainerPassword,
+	}
+	cluster.DisableInitialHostLookup = true
+	cluster.Timeout = 10 * time.Second
+
+	deadline := time.Now().Add(testAccContainerStartup)
+	for {
+		session, err := cluster.CreateSession()
+		if err == nil {
+			session.Close()
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(5 * time.Second)
+	}
+}
+
+// testAccPreCheckServer skips a test unless the acceptance tests run against
+// a container of one of the given servers, or against CASSANDRA_HOST.
+func testAccPreCheckServer(t *testing.T, names ...string) {
+	if testAccServerName == "" {
+		return
+	}
+	for _, name := range names {
+		if name == testAccServerName {
+			return
+		}
+	}
+	t.Skipf("not supported by %s", testAccServerName)
+}
+
+// testAccPreCheckAzureManagedInstance skips a test unless CASSANDRA_TEST_PROFILE
+// is azure_managed_instance, i.e. the CASSANDRA_* variables point at an Azure
+// Managed Instance for Apache Cassandra and its non-superuser management
+// account, which cannot run in a container.
+func testAccPreCheckAzureManagedInstance(t *testing.T) {
+	if os.Getenv("CASSANDRA_TEST_PROFILE") != modeAzureManagedInstance {
+		t.Skipf("CASSANDRA_TEST_PROFILE is not %s", modeAzureManagedInstance)
+	}
+}
+
+func TestTestAccServerOptions(t *testing.T) {
+	request := func(server testAccServer) testcontainers.GenericContainerRequest {
+		req := testcontainers.GenericContainerRequest{ContainerRequest: testcontainers.ContainerRequest{Image: server.Image}}
+		for _, option := range server.options() {
+			if err := option.Customize(&req); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return req
+	}
+
+	req := request(testAccServers["cassandra-5.0"])
+	entrypoint := strings.Join(req.Entrypoint, " ")
+	expected := `sh -c ` + cassandraAuthSetup + ` && exec docker-entrypoint.sh "$@" sh`
+	if entrypoint != expected {
+		t.Fatalf("expected entrypoint %s, got %s", expected, entrypoint)
+	}
+	if cmd := strings.Join(req.Cmd, " "); cmd != "cassandra -f" {
+		t.Fatalf("expected command cassandra -f, got %s", cmd)
+	}
+	if ports := strings.Join(req.ExposedPorts, " "); ports != testAccContainerPort {
+		t.Fatalf("expected exposed port %s, got %s", testAccContainerPort, ports)
+	}
+
+	req = request(testAccServers["scylla"])
+	if len(req.Entrypoint) != 0 {
+		t.Fatalf("expected the entrypoint of the image, got %v", req.Entrypoint)
+	}
+	cmd := strings.Join(req.Cmd, " ")
+	expected = "--smp 1 --overprovisioned 1 --authenticator PasswordAuthenticator --authorizer CassandraAuthorizer"
+	if cmd != expected {
+		t.Fatalf("expected command %s, got %s", expected, cmd)
+	}
+}
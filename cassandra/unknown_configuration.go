@@ -0,0 +1,49 @@
+package cassandra
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// connectionAttributes are the provider arguments needed to reach and log in
+// to the cluster.
+var connectionAttributes = []string{"host", "hosts", "port", "username", "password", "use_ssl", "root_ca", "mode"}
+
+// unknownConnectionAttributes lists the connection attributes of the
+// provider configuration that are not known yet, e.g. because they are
+// derived from resources created in the same plan.
+func unknownConnectionAttributes(config cty.Value) []string {
+	if config.IsNull() || !config.IsKnown() {
+		return nil
+	}
+	unknown := []string{}
+	for _, name := range connectionAttributes {
+		if config.Type().HasAttribute(name) && !config.GetAttr(name).IsWhollyKnown() {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown
+}
+
+// configureProviderDeferred configures the provider unless its connection
+// attributes are unknown. Then all resources and data sources are deferred to
+// a later plan if Terraform allows deferral, and planning fails otherwise.
+func configureProviderDeferred(ctx context.Context, req schema.ConfigureProviderRequest, resp *schema.ConfigureProviderResponse) {
+	unknown := unknownConnectionAttributes(req.ResourceData.GetRawConfig())
+	if len(unknown) == 0 {
+		resp.Meta, resp.Diagnostics = configureProvider(ctx, req.ResourceData)
+		return
+	}
+
+	if !req.DeferralAllowed {
+		resp.Diagnostics = diag.Errorf("the provider configuration is not known until apply (%s). Enable deferred actions with terraform plan -allow-deferral, or apply the resources the provider configuration depends on first, e.g. with -target", strings.Join(unknown, ", "))
+		return
+	}
+	log.Printf("[WARN] Deferring all resources and data sources: the provider configuration is not known until apply (%s)", strings.Join(unknown, ", "))
+	resp.Deferred = &schema.Deferred{Reason: schema.DeferredReasonProviderConfigUnknown}
+}
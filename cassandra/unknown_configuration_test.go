@@ -0,0 +1,73 @@
+package cassandra
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestUnknownConnectionAttributes(t *testing.T) {
+	config := cty.ObjectVal(map[string]cty.Value{
+		"host":     cty.UnknownVal(cty.String),
+		"hosts":    cty.ListVal([]cty.Value{cty.StringVal("10.0.0.1"), cty.UnknownVal(cty.String)}),
+		"username": cty.StringVal("cassandra"),
+		"password": cty.UnknownVal(cty.String),
+		"port":     cty.NumberIntVal(9042),
+	})
+	if unknown := unknownConnectionAttributes(config); !reflect.DeepEqual(unknown, []string{"host", "hosts", "password"}) {
+		t.Fatalf("expected host, hosts and password to be unknown, got %v", unknown)
+	}
+	if unknown := unknownConnectionAttributes(cty.NullVal(config.Type())); len(unknown) != 0 {
+		t.Fatalf("expected no unknown attributes in a null configuration, got %v", unknown)
+	}
+}
+
+// configureUnknownHost configures the provider through its gRPC server with
+// an unknown host.
+func configureUnknownHost(t *testing.T, deferralAllowed bool) (*schema.GRPCProviderServer, *tfprotov5.ConfigureProviderResponse) {
+	configType := schema.InternalMap(Provider().Schema).CoreConfigSchema().ImpliedType()
+	attributes := map[string]cty.Value{}
+	for name, attributeType := range configType.AttributeTypes() {
+		attributes[name] = cty.NullVal(attributeType)
+	}
+	attributes["host"] = cty.UnknownVal(cty.String)
+	config, err := msgpack.Marshal(cty.ObjectVal(attributes), configType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := schema.NewGRPCProviderServer(Provider())
+	resp, err := server.ConfigureProvider(context.Background(), &tfprotov5.ConfigureProviderRequest{
+		Config:             &tfprotov5.DynamicValue{MsgPack: config},
+		ClientCapabilities: &tfprotov5.ConfigureProviderClientCapabilities{DeferralAllowed: deferralAllowed},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server, resp
+}
+
+func TestConfigureProviderDeferred(t *testing.T) {
+	server, resp := configureUnknownHost(t, true)
+	if len(resp.Diagnostics) != 0 {
+		t.Fatalf("expected the provider to be configured, got %v", resp.Diagnostics[0].Summary)
+	}
+	read, err := server.ReadDataSource(context.Background(), &tfprotov5.ReadDataSourceRequest{TypeName: "cassandra_keyspaces"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Deferred == nil || read.Deferred.Reason != tfprotov5.DeferredReasonProviderConfigUnknown {
+		t.Fatalf("expected the data source to be deferred, got %+v", read.Deferred)
+	}
+
+	_, resp = configureUnknownHost(t, false)
+	expected := "the provider configuration is not known until apply (host). Enable deferred actions with terraform plan -allow-deferral, or apply the resources the provider configuration depends on first, e.g. with -target"
+	if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != expected {
+		t.Fatalf("expected %q, got %v", expected, resp.Diagnostics)
+	}
+}
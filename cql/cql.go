@@ -0,0 +1,69 @@
+// Package cql renders identifiers, string literals and option maps for the
+// CQL statements built by the provider, so they are all quoted and escaped
+// the same way, and parses CQL type expressions. It has no dependency on
+// Terraform, so other tools can build statements the way the provider does.
+package cql
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var unquotedIdentifierRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// reservedKeywords cannot be used as unquoted identifiers.
+var reservedKeywords = map[string]bool{
+	"add": true, "allow": true, "alter": true, "and": true, "apply": true, "asc": true,
+	"authorize": true, "batch": true, "begin": true, "by": true, "columnfamily": true,
+	"create": true, "delete": true, "desc": true, "describe": true, "drop": true,
+	"entries": true, "execute": true, "from": true, "full": true, "grant": true, "if": true,
+	"in": true, "index": true, "infinity": true, "insert": true, "into": true, "keyspace": true,
+	"limit": true, "modify": true, "nan": true, "norecursive": true, "not": true, "null": true,
+	"of": true, "on": true, "or": true, "order": true, "primary": true, "rename": true,
+	"replace": true, "revoke": true, "schema": true, "select": true, "set": true, "table": true,
+	"to": true, "token": true, "truncate": true, "unlogged": true, "update": true, "use": true,
+	"using": true, "view": true, "where": true, "with": true,
+}
+
+// QuoteIdentifier double-quotes a keyspace, table, column or role name,
+// escaping embedded double quotes. Quoted identifiers keep their case.
+func QuoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// Identifier renders a name as stored by Cassandra, quoting it only when it
+// would not survive being used unquoted, e.g. mixed-case names or keywords.
+func Identifier(name string) string {
+	if unquotedIdentifierRegex.MatchString(name) && !reservedKeywords[name] {
+		return name
+	}
+	return QuoteIdentifier(name)
+}
+
+// QualifiedName renders the name of a table, type or function of a keyspace.
+func QualifiedName(keyspace string, name string) string {
+	return Identifier(keyspace) + "." + Identifier(name)
+}
+
+// Literal renders a value as a single-quoted string literal, escaping
+// embedded single quotes.
+func Literal(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// Map renders a map, e.g. replication, compaction or tags, as a CQL map
+// literal of strings with sorted keys.
+func Map(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, Literal(key)+":"+Literal(m[key]))
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
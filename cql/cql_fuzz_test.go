@@ -0,0 +1,180 @@
+package cql
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// scanQuoted reads a token enclosed in quote, in which quote is escaped by
+// doubling it, and returns its value and the text following it.
+func scanQuoted(text string, quote byte) (string, string, error) {
+	if len(text) == 0 || text[0] != quote {
+		return "", "", fmt.Errorf("expected %c at %q", quote, text)
+	}
+	value := strings.Builder{}
+	for i := 1; i < len(text); i++ {
+		if text[i] != quote {
+			value.WriteByte(text[i])
+			continue
+		}
+		if i+1 < len(text) && text[i+1] == quote {
+			value.WriteByte(quote)
+			i++
+			continue
+		}
+		return value.String(), text[i+1:], nil
+	}
+	return "", "", fmt.Errorf("unterminated %c in %q", quote, text)
+}
+
+// scanIdentifier reads an identifier as Cassandra does: quoted identifiers
+// keep their case, unquoted ones are lowercased.
+func scanIdentifier(text string) (string, string, error) {
+	if strings.HasPrefix(text, `"`) {
+		return scanQuoted(text, '"')
+	}
+	end := 0
+	for end < len(text) && (text[end] == '_' || text[end] >= 'a' && text[end] <= 'z' || text[end] >= 'A' && text[end] <= 'Z' || text[end] >= '0' && text[end] <= '9') {
+		end++
+	}
+	if end == 0 {
+		return "", "", fmt.Errorf("expected an identifier at %q", text)
+	}
+	name := strings.ToLower(text[:end])
+	if reservedKeywords[name] {
+		return "", "", fmt.Errorf("unquoted keyword %s", name)
+	}
+	return name, text[end:], nil
+}
+
+// scanMap reads a map literal of strings as rendered by Map.
+func scanMap(text string) (map[string]string, error) {
+	if !strings.HasPrefix(text, "{") {
+		return nil, fmt.Errorf("expected { at %q", text)
+	}
+	text = text[1:]
+	m := map[string]string{}
+	for !strings.HasPrefix(text, "}") {
+		if len(m) > 0 {
+			if !strings.HasPrefix(text, ", ") {
+				return nil, fmt.Errorf("expected , at %q", text)
+			}
+			text = text[2:]
+		}
+		key, rest, err := scanQuoted(text, '\'')
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(rest, ":") {
+			return nil, fmt.Errorf("expected : at %q", rest)
+		}
+		value, rest, err := scanQuoted(rest[1:], '\'')
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := m[key]; ok {
+			return nil, fmt.Errorf("duplicate key %q", key)
+		}
+		m[key] = value
+		text = rest
+	}
+	if text != "}" {
+		return nil, fmt.Errorf("unexpected %q after the map", text[1:])
+	}
+	return m, nil
+}
+
+func FuzzIdentifier(f *testing.F) {
+	for _, seed := range []string{"events", "MyTable", `say "hi"`, "", "select", `"; DROP KEYSPACE app; --`, "1st", "ünïcode"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		for _, rendered := range []string{Identifier(name), QuoteIdentifier(name)} {
+			actual, rest, err := scanIdentifier(rendered)
+			if err != nil {
+				t.Fatalf("cannot read %s back: %s", rendered, err)
+			}
+			if actual != name || rest != "" {
+				t.Fatalf("%q rendered as %s reads back as %q followed by %q", name, rendered, actual, rest)
+			}
+		}
+	})
+}
+
+func FuzzQualifiedName(f *testing.F) {
+	f.Add("app", "events")
+	f.Add("App", "my.table")
+	f.Add(`a"."b`, "c")
+	f.Fuzz(func(t *testing.T, keyspace string, name string) {
+		rendered := QualifiedName(keyspace, name)
+		actualKeyspace, rest, err := scanIdentifier(rendered)
+		if err != nil {
+			t.Fatalf("cannot read %s back: %s", rendered, err)
+		}
+		if !strings.HasPrefix(rest, ".") {
+			t.Fatalf("expected . after the keyspace of %s", rendered)
+		}
+		actualName, rest, err := scanIdentifier(rest[1:])
+		if err != nil {
+			t.Fatalf("cannot read %s back: %s", rendered, err)
+		}
+		if actualKeyspace != keyspace || actualName != name || rest != "" {
+			t.Fatalf("%q.%q rendered as %s reads back as %q.%q followed by %q", keyspace, name, rendered, actualKeyspace, actualName, rest)
+		}
+	})
+}
+
+func FuzzLiteral(f *testing.F) {
+	for _, seed := range []string{"plain", "it's", "'); DROP --", "", "''", "\\'", "$$"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, value string) {
+		rendered := Literal(value)
+		actual, rest, err := scanQuoted(rendered, '\'')
+		if err != nil {
+			t.Fatalf("cannot read %s back: %s", rendered, err)
+		}
+		if actual != value || rest != "" {
+			t.Fatalf("%q rendered as %s reads back as %q followed by %q", value, rendered, actual, rest)
+		}
+	})
+}
+
+func FuzzMap(f *testing.F) {
+	f.Add("team", "data", "owner", "o'brien")
+	f.Add("a", "b', 'c':'d", "", "")
+	f.Add("}", "{", "':'", ", ")
+	f.Fuzz(func(t *testing.T, key1 string, value1 string, key2 string, value2 string) {
+		m := map[string]string{key1: value1, key2: value2}
+		rendered := Map(m)
+		actual, err := scanMap(rendered)
+		if err != nil {
+			t.Fatalf("cannot read %s back: %s", rendered, err)
+		}
+		if len(actual) != len(m) {
+			t.Fatalf("%v rendered as %s reads back as %v", m, rendered, actual)
+		}
+		for key, value := range m {
+			if actual[key] != value {
+				t.Fatalf("%v rendered as %s reads back as %v", m, rendered, actual)
+			}
+		}
+	})
+}
+
+func FuzzRedact(f *testing.F) {
+	for _, seed := range []string{"secret", "it's", "'", "'' AND LOGIN = true", "password = 'x'", ""} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, password string) {
+		statement := "CREATE ROLE app WITH PASSWORD = " + Literal(password) + " AND LOGIN = true"
+		if actual, expected := Redact(statement), "CREATE ROLE app WITH PASSWORD = '***' AND LOGIN = true"; actual != expected {
+			t.Fatalf("expected %s, got %s", expected, actual)
+		}
+		options := "ALTER ROLE app WITH OPTIONS = " + Map(map[string]string{"ldap_password": password, "mode": "x"})
+		if actual, expected := Redact(options), "ALTER ROLE app WITH OPTIONS = {'ldap_password':'***', 'mode':'x'}"; actual != expected {
+			t.Fatalf("expected %s, got %s", expected, actual)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package cql
+
+import "testing"
+
+func TestQuoteIdentifier(t *testing.T) {
+	cases := map[string]string{
+		"events":     `"events"`,
+		"MyTable":    `"MyTable"`,
+		`say "hi"`:   `"say ""hi"""`,
+		"":           `""`,
+		"user_names": `"user_names"`,
+	}
+	for name, expected := range cases {
+		if actual := QuoteIdentifier(name); actual != expected {
+			t.Fatalf("expected %s for %q, got %s", expected, name, actual)
+		}
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	cases := map[string]string{
+		"events":   "events",
+		"_private": "_private",
+		"MyTable":  `"MyTable"`,
+		"1st":      `"1st"`,
+		"select":   `"select"`,
+		"key":      "key",
+		"a-b":      `"a-b"`,
+	}
+	for name, expected := range cases {
+		if actual := Identifier(name); actual != expected {
+			t.Fatalf("expected %s for %q, got %s", expected, name, actual)
+		}
+	}
+
+	if actual := QualifiedName("app", "Events"); actual != `app."Events"` {
+		t.Fatalf(`expected app."Events", got %s`, actual)
+	}
+}
+
+func TestLiteral(t *testing.T) {
+	cases := map[string]string{
+		"plain":       "'plain'",
+		"it's":        "'it''s'",
+		"'); DROP --": "'''); DROP --'",
+		"":            "''",
+	}
+	for value, expected := range cases {
+		if actual := Literal(value); actual != expected {
+			t.Fatalf("expected %s for %q, got %s", expected, value, actual)
+		}
+	}
+}
+
+func TestMap(t *testing.T) {
+	actual := Map(map[string]string{"team": "data", "owner": "o'brien"})
+	expected := "{'owner':'o''brien', 'team':'data'}"
+	if actual != expected {
+		t.Fatalf("expected %s, got %s", expected, actual)
+	}
+	if actual := Map(map[string]string{}); actual != "{}" {
+		t.Fatalf("expected {}, got %s", actual)
+	}
+}
@@ -0,0 +1,135 @@
+package cql
+
+import (
+	"regexp"
+	"strings"
+)
+
+// statementTokenRegex matches, from left to right, the comments, string
+// literals and function bodies to skip and the identifiers, dots and
+// semicolons to look at.
+var statementTokenRegex = regexp.MustCompile(
+	`(?s)--[^\n]*|//[^\n]*|/\*.*?\*/|\$\$.*?\$\$|'(?:[^']|'')*'` +
+		`|"(?:[^"]|"")*"|[A-Za-z_][A-Za-z0-9_]*|[0-9][A-Za-z0-9_.]*|[.;]`,
+)
+
+// objectKeywords are followed by the possibly keyspace-qualified name of a
+// table, type, index, view, function or aggregate.
+var objectKeywords = map[string]bool{
+	"TABLE": true, "COLUMNFAMILY": true, "FROM": true, "INTO": true, "UPDATE": true, "TRUNCATE": true,
+	"TYPE": true, "INDEX": true, "VIEW": true, "FUNCTION": true, "AGGREGATE": true, "ON": true,
+}
+
+// resourceKeywords follow ON in GRANT, REVOKE and LIST statements instead of
+// a table name.
+var resourceKeywords = map[string]bool{
+	"ALL": true, "KEYSPACE": true, "KEYSPACES": true, "TABLE": true, "COLUMNFAMILY": true, "ROLE": true, "ROLES": true,
+	"FUNCTION": true, "FUNCTIONS": true, "AGGREGATE": true, "MBEAN": true, "MBEANS": true,
+}
+
+type statementToken struct {
+	text   string
+	quoted bool
+}
+
+func (t statementToken) isName() bool {
+	return t.quoted || t.text[0] == '_' || t.text[0] >= 'A' && t.text[0] <= 'Z' || t.text[0] >= 'a' && t.text[0] <= 'z'
+}
+
+func (t statementToken) keyword() string {
+	if t.quoted {
+		return ""
+	}
+	return strings.ToUpper(t.text)
+}
+
+// name returns the name as stored by Cassandra, i.e. unquoted or lower-cased.
+func (t statementToken) name() string {
+	if t.quoted {
+		return strings.ReplaceAll(t.text[1:len(t.text)-1], `""`, `"`)
+	}
+	return strings.ToLower(t.text)
+}
+
+// StatementKeyspaces returns the keyspaces the CQL statements of script name,
+// in KEYSPACE clauses, USE statements and keyspace-qualified names. Unqualified
+// table, type, index, view, function and aggregate names are taken to be in
+// defaultKeyspace, or in the keyspace of a preceding USE statement. The
+// keyspaces are returned as stored by Cassandra, in order of appearance.
+func StatementKeyspaces(script string, defaultKeyspace string) []string {
+	tokens := []statementToken{}
+	for _, match := range statementTokenRegex.FindAllString(script, -1) {
+		switch match[0] {
+		case '-', '/', '$', '\'':
+			continue
+		}
+		tokens = append(tokens, statementToken{text: match, quoted: match[0] == '"'})
+	}
+
+	keyspaces := []string{}
+	seen := map[string]bool{}
+	add := func(keyspace string) {
+		if keyspace != "" && !seen[keyspace] {
+			seen[keyspace] = true
+			keyspaces = append(keyspaces, keyspace)
+		}
+	}
+	isDot := func(i int) bool {
+		return i < len(tokens) && tokens[i].text == "."
+	}
+	// skipIfExists skips an IF EXISTS or IF NOT EXISTS clause starting at i.
+	skipIfExists := func(i int) int {
+		if i < len(tokens) && tokens[i].keyword() == "IF" {
+			i++
+			if i < len(tokens) && tokens[i].keyword() == "NOT" {
+				i++
+			}
+			if i < len(tokens) && tokens[i].keyword() == "EXISTS" {
+				i++
+			}
+		}
+		return i
+	}
+
+	statement := ""
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if token.text == ";" {
+			statement = ""
+			continue
+		}
+		if token.text == "." || !token.isName() {
+			continue
+		}
+		if statement == "" {
+			statement = token.keyword()
+		}
+		if isDot(i+1) && i+2 < len(tokens) && tokens[i+2].isName() {
+			add(token.name())
+			i += 2
+			continue
+		}
+
+		switch keyword := token.keyword(); {
+		case keyword == "KEYSPACE" || keyword == "USE" && statement == "USE":
+			if j := skipIfExists(i + 1); j < len(tokens) && tokens[j].isName() {
+				add(tokens[j].name())
+				if keyword == "USE" {
+					defaultKeyspace = tokens[j].name()
+				}
+				i = j
+			}
+		case objectKeywords[keyword] && !(keyword == "FROM" && statement == "REVOKE"):
+			j := skipIfExists(i + 1)
+			if j >= len(tokens) || !tokens[j].isName() || isDot(j+1) {
+				continue
+			}
+			if keyword == "ON" && resourceKeywords[tokens[j].keyword()] {
+				continue
+			}
+			add(defaultKeyspace)
+			i = j
+		}
+	}
+	return keyspaces
+}
@@ -0,0 +1,36 @@
+package cql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStatementKeyspaces(t *testing.T) {
+	cases := []struct {
+		script          string
+		defaultKeyspace string
+		expected        []string
+	}{
+		{`DROP KEYSPACE IF EXISTS billing`, "", []string{"billing"}},
+		{`DROP TABLE billing.invoices`, "", []string{"billing"}},
+		{`DROP TABLE "Billing"."Invoices"`, "", []string{"Billing"}},
+		{`TRUNCATE Billing.invoices`, "", []string{"billing"}},
+		{`DROP TABLE invoices`, "app", []string{"app"}},
+		{`DROP TABLE invoices`, "", []string{}},
+		{`DROP TRIGGER audit ON billing.events`, "", []string{"billing"}},
+		{`DROP INDEX IF EXISTS events_by_day`, "app", []string{"app"}},
+		{`REVOKE SELECT ON KEYSPACE billing FROM reporting`, "app", []string{"billing"}},
+		{`REVOKE SELECT ON ALL KEYSPACES FROM reporting`, "app", []string{}},
+		{`DROP ROLE reporting`, "app", []string{}},
+		{`DELETE FROM app.settings WHERE id = 1; DROP TABLE billing.invoices`, "", []string{"app", "billing"}},
+		{`USE billing; DROP TABLE invoices`, "app", []string{"billing"}},
+		{`INSERT INTO app.notes (id, text) VALUES (1, 'see billing.invoices') -- or other.table`, "", []string{"app"}},
+		{`UPDATE app.prices SET amount = 1.5 WHERE id = 2`, "", []string{"app"}},
+		{`DROP FUNCTION app.add`, "", []string{"app"}},
+	}
+	for _, test := range cases {
+		if actual := StatementKeyspaces(test.script, test.defaultKeyspace); !reflect.DeepEqual(actual, test.expected) {
+			t.Fatalf("%s: expected %v, got %v", test.script, test.expected, actual)
+		}
+	}
+}
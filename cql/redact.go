@@ -0,0 +1,52 @@
+package cql
+
+import (
+	"regexp"
+	"strings"
+)
+
+const redacted = "'***'"
+
+// redactTokenRegex matches, from left to right, the secrets to mask and the
+// quoted strings and identifiers to leave alone, so text within quotes, e.g.
+// a password containing PASSWORD = '...', is never taken for a clause.
+var redactTokenRegex = regexp.MustCompile(
+	// Map entries whose key names a secret, e.g. {'password': '...'} in role options.
+	`(?i)('(?:[^']|'')*(?:password|secret|token|credential)(?:[^']|'')*'\s*:\s*)'(?:[^']|'')*'` +
+		// The passwords of role and user statements, e.g. WITH PASSWORD = '...',
+		// HASHED PASSWORD = '...' or PASSWORD '...'.
+		`|(?i)(\bPASSWORD\s*(?:=\s*)?)'(?:[^']|'')*'` +
+		`|'(?:[^']|'')*'|"(?:[^"]|"")*"`,
+)
+
+// Redact masks the passwords and secret map entries of CQL statements found
+// in text, e.g. a statement, a log line or an error message.
+func Redact(text string) string {
+	result := strings.Builder{}
+	for {
+		match := redactTokenRegex.FindStringSubmatchIndex(text)
+		if match == nil {
+			break
+		}
+		switch {
+		case match[2] >= 0:
+			result.WriteString(text[:match[3]] + redacted)
+		case match[4] >= 0:
+			result.WriteString(text[:match[5]] + redacted)
+		case match[0] > 0 && isWordByte(text[match[0]-1]):
+			// An apostrophe, e.g. in doesn't, rather than an opening quote.
+			result.WriteString(text[:match[0]+1])
+			text = text[match[0]+1:]
+			continue
+		default:
+			result.WriteString(text[:match[1]])
+		}
+		text = text[match[1]:]
+	}
+	result.WriteString(text)
+	return result.String()
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
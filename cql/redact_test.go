@@ -0,0 +1,23 @@
+package cql
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+	cases := map[string]string{
+		`CREATE ROLE 'app' WITH PASSWORD = 'it''s secret' AND LOGIN = true`:          `CREATE ROLE 'app' WITH PASSWORD = '***' AND LOGIN = true`,
+		`ALTER ROLE 'app' WITH HASHED PASSWORD = '$2a$10$abc' AND SUPERUSER = false`: `ALTER ROLE 'app' WITH HASHED PASSWORD = '***' AND SUPERUSER = false`,
+		`CREATE USER 'app' WITH PASSWORD 'secret' NOSUPERUSER`:                       `CREATE USER 'app' WITH PASSWORD '***' NOSUPERUSER`,
+		`[DEBUG] Executing query: create role app with password='x'`:                 `[DEBUG] Executing query: create role app with password='***'`,
+		`ALTER ROLE app WITH OPTIONS = {'ldap_password': 'x', 'mode': 'y'}`:          `ALTER ROLE app WITH OPTIONS = {'ldap_password': '***', 'mode': 'y'}`,
+		`INSERT INTO app.tokens (id, api_token) VALUES (1, 'abc')`:                   `INSERT INTO app.tokens (id, api_token) VALUES (1, 'abc')`,
+		`DROP TABLE app.passwords`:                                                   `DROP TABLE app.passwords`,
+		`ALTER ROLE app WITH OPTIONS = {'ldap_password':'password = ''x'''}`:         `ALTER ROLE app WITH OPTIONS = {'ldap_password':'***'}`,
+		`CREATE ROLE "o'brien" WITH PASSWORD = 'x'`:                                  `CREATE ROLE "o'brien" WITH PASSWORD = '***'`,
+		`Role app doesn't exist: ALTER ROLE app WITH PASSWORD = 'x'`:                 `Role app doesn't exist: ALTER ROLE app WITH PASSWORD = '***'`,
+	}
+	for text, expected := range cases {
+		if actual := Redact(text); actual != expected {
+			t.Fatalf("expected %s, got %s", expected, actual)
+		}
+	}
+}
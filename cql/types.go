@@ -0,0 +1,233 @@
+package cql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// nativeTypes maps the native CQL types to their canonical spelling.
+	nativeTypes = map[string]string{
+		"ascii":     "ascii",
+		"bigint":    "bigint",
+		"blob":      "blob",
+		"boolean":   "boolean",
+		"counter":   "counter",
+		"date":      "date",
+		"decimal":   "decimal",
+		"double":    "double",
+		"duration":  "duration",
+		"float":     "float",
+		"inet":      "inet",
+		"int":       "int",
+		"smallint":  "smallint",
+		"text":      "text",
+		"time":      "time",
+		"timestamp": "timestamp",
+		"timeuuid":  "timeuuid",
+		"tinyint":   "tinyint",
+		"uuid":      "uuid",
+		"varchar":   "text",
+		"varint":    "varint",
+	}
+
+	// typeArity is the number of type parameters of the parameterized CQL
+	// types, -1 meaning one or more.
+	typeArity = map[string]int{
+		"frozen": 1,
+		"list":   1,
+		"set":    1,
+		"map":    2,
+		"tuple":  -1,
+	}
+
+	typeTokenRegex = regexp.MustCompile(`^(\s+|[A-Za-z_][A-Za-z0-9_]*|"(?:[^"]|"")*"|[0-9]+|[<>,.])`)
+)
+
+// NativeType returns the canonical spelling of a native type, e.g. text for
+// varchar, or an empty string if name is not a native type.
+func NativeType(name string) string {
+	return nativeTypes[strings.ToLower(name)]
+}
+
+// typeParser is a recursive descent parser for CQL type expressions.
+type typeParser struct {
+	tokens    []string
+	pos       int
+	userTypes map[string]bool
+}
+
+// ParseType validates a CQL type expression and returns its canonical
+// spelling. Names other than native types must be listed in userTypes.
+func ParseType(cqlType string, userTypes []string) (string, error) {
+	tokens, err := TokenizeType(cqlType)
+	if err != nil {
+		return "", err
+	}
+	parser := &typeParser{tokens: tokens, userTypes: map[string]bool{}}
+	for _, userType := range userTypes {
+		parser.userTypes[IdentifierName(userType)] = true
+	}
+
+	normalized, err := parser.parseType(false)
+	if err != nil {
+		return "", fmt.Errorf("invalid CQL type %q: %s", cqlType, err)
+	}
+	if parser.pos < len(parser.tokens) {
+		return "", fmt.Errorf("invalid CQL type %q: unexpected %q", cqlType, parser.tokens[parser.pos])
+	}
+	return normalized, nil
+}
+
+// TokenizeType splits a CQL type expression into names, quoted names,
+// numbers and the punctuation <, >, comma and dot, dropping whitespace.
+func TokenizeType(cqlType string) ([]string, error) {
+	tokens := []string{}
+	for rest := cqlType; rest != ""; {
+		token := typeTokenRegex.FindString(rest)
+		if token == "" {
+			return nil, fmt.Errorf("invalid CQL type %q: unexpected %q", cqlType, rest[:1])
+		}
+		rest = rest[len(token):]
+		if strings.TrimSpace(token) != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (p *typeParser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	token := p.tokens[p.pos]
+	p.pos++
+	return token
+}
+
+func (p *typeParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *typeParser) expect(expected string) error {
+	if token := p.next(); token != expected {
+		if token == "" {
+			return fmt.Errorf("expected %q at the end", expected)
+		}
+		return fmt.Errorf("expected %q, got %q", expected, token)
+	}
+	return nil
+}
+
+// parseType parses one type. nested tells whether the type is a parameter
+// of another one, where counter is not allowed.
+func (p *typeParser) parseType(nested bool) (string, error) {
+	token := p.next()
+	if token == "" {
+		return "", fmt.Errorf("expected a type at the end")
+	}
+	if !strings.HasPrefix(token, `"`) && !isIdentifierToken(token) {
+		return "", fmt.Errorf("expected a type, got %q", token)
+	}
+
+	name := strings.ToLower(token)
+	if native, ok := nativeTypes[name]; ok && !strings.HasPrefix(token, `"`) {
+		if native == "counter" && nested {
+			return "", fmt.Errorf("counter cannot be used within another type")
+		}
+		return native, nil
+	}
+	if name == "vector" && p.peek() == "<" {
+		return p.parseVector()
+	}
+	if arity, ok := typeArity[name]; ok && p.peek() == "<" {
+		return p.parseParameterized(name, arity)
+	}
+	return p.parseUserType(token)
+}
+
+func (p *typeParser) parseParameterized(name string, arity int) (string, error) {
+	p.next()
+	parameters := []string{}
+	for {
+		parameter, err := p.parseType(true)
+		if err != nil {
+			return "", err
+		}
+		if name == "frozen" && (nativeTypes[parameter] != "" || strings.HasPrefix(parameter, "frozen<")) {
+			return "", fmt.Errorf("frozen is only allowed on collections, tuples and user-defined types, got %s", parameter)
+		}
+		parameters = append(parameters, parameter)
+		if p.peek() != "," {
+			break
+		}
+		p.next()
+	}
+	if err := p.expect(">"); err != nil {
+		return "", err
+	}
+	if arity > 0 && len(parameters) != arity {
+		return "", fmt.Errorf("%s takes %d type parameters, got %d", name, arity, len(parameters))
+	}
+	return fmt.Sprintf("%s<%s>", name, strings.Join(parameters, ", ")), nil
+}
+
+func (p *typeParser) parseVector() (string, error) {
+	p.next()
+	element, err := p.parseType(true)
+	if err != nil {
+		return "", err
+	}
+	if err := p.expect(","); err != nil {
+		return "", err
+	}
+	dimension, err := strconv.Atoi(p.next())
+	if err != nil || dimension <= 0 {
+		return "", fmt.Errorf("vector dimension must be a positive number")
+	}
+	if err := p.expect(">"); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("vector<%s, %d>", element, dimension), nil
+}
+
+// parseUserType parses a user-defined type, optionally qualified with its keyspace.
+func (p *typeParser) parseUserType(token string) (string, error) {
+	keyspace := ""
+	if p.peek() == "." {
+		p.next()
+		keyspace = Identifier(IdentifierName(token)) + "."
+		token = p.next()
+		if !strings.HasPrefix(token, `"`) && !isIdentifierToken(token) {
+			return "", fmt.Errorf("expected a type name after %q", keyspace)
+		}
+	}
+
+	name := IdentifierName(token)
+	if !p.userTypes[name] {
+		return "", fmt.Errorf("unknown type %s, user-defined types must be passed as user_types", token)
+	}
+	return keyspace + Identifier(name), nil
+}
+
+func isIdentifierToken(token string) bool {
+	if token == "" {
+		return false
+	}
+	c := token[0]
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// IdentifierName returns the name under which Cassandra stores an
+// identifier: unquoted identifiers are case-insensitive.
+func IdentifierName(identifier string) string {
+	if len(identifier) >= 2 && strings.HasPrefix(identifier, `"`) && strings.HasSuffix(identifier, `"`) {
+		return strings.ReplaceAll(identifier[1:len(identifier)-1], `""`, `"`)
+	}
+	return strings.ToLower(identifier)
+}
@@ -0,0 +1,57 @@
+package cql
+
+import "testing"
+
+func TestParseType(t *testing.T) {
+	userTypes := []string{"address", `"Phone"`}
+	for _, test := range []struct {
+		cqlType  string
+		expected string
+	}{
+		{"VARCHAR", "text"},
+		{"map<varchar,frozen<list<int>>>", "map<text, frozen<list<int>>>"},
+		{" set < timeuuid > ", "set<timeuuid>"},
+		{"tuple<int, text, blob>", "tuple<int, text, blob>"},
+		{"vector<float, 768>", "vector<float, 768>"},
+		{"frozen<Address>", "frozen<address>"},
+		{`list<frozen<"Phone">>`, `list<frozen<"Phone">>`},
+		{"app.address", "app.address"},
+	} {
+		normalized, err := ParseType(test.cqlType, userTypes)
+		if err != nil {
+			t.Fatalf("parsing %q: %s", test.cqlType, err)
+		}
+		if normalized != test.expected {
+			t.Fatalf("expected %q to normalize to %q, got %q", test.cqlType, test.expected, normalized)
+		}
+	}
+
+	for _, cqlType := range []string{
+		"",
+		"txet",
+		"phone",
+		"list<int",
+		"list<int>>",
+		"map<text>",
+		"set<int, int>",
+		"frozen<int>",
+		"list<counter>",
+		"vector<float>",
+		"vector<float, 0>",
+		"int;",
+		"app.",
+	} {
+		if _, err := ParseType(cqlType, userTypes); err == nil {
+			t.Fatalf("expected an error parsing %q", cqlType)
+		}
+	}
+}
+
+func TestNativeType(t *testing.T) {
+	if actual := NativeType("VARCHAR"); actual != "text" {
+		t.Fatalf("expected text, got %q", actual)
+	}
+	if actual := NativeType("address"); actual != "" {
+		t.Fatalf("expected no native type, got %q", actual)
+	}
+}
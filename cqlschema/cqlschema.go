@@ -0,0 +1,370 @@
+// Package cqlschema reads the schema of a keyspace from the system tables of
+// a cluster and renders it back as CREATE statements. It is the part of the
+// provider that tools and tests can reuse without Terraform.
+package cqlschema
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gocql/gocql"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+)
+
+// DefaultSchemaKeyspace describes the schema of Apache Cassandra, ScyllaDB
+// and DataStax Enterprise. Amazon Keyspaces describes it in system_schema_mcs.
+const DefaultSchemaKeyspace = "system_schema"
+
+// Field is a field of a user-defined type.
+type Field struct {
+	Name string
+	Type string
+}
+
+// TableOptions holds the WITH options of a table as stored in system_schema.tables.
+type TableOptions struct {
+	Comment           string
+	DefaultTimeToLive int
+	GCGraceSeconds    int
+	SpeculativeRetry  string
+	Caching           map[string]string
+	Compaction        map[string]string
+	Compression       map[string]string
+}
+
+// Index is the definition of an index as stored in system_schema.indexes.
+type Index struct {
+	Name    string
+	Table   string
+	Kind    string
+	Options map[string]string
+}
+
+// MaterializedView is the definition of a view as stored in system_schema.
+type MaterializedView struct {
+	BaseTable         string
+	IncludeAllColumns bool
+	WhereClause       string
+	Columns           []string
+	PartitionKeys     []string
+	ClusteringKeys    []string
+}
+
+// Keyspace holds the definitions the DDL of a keyspace is rendered from.
+type Keyspace struct {
+	Keyspace     *gocql.KeyspaceMetadata
+	UserTypes    map[string][]Field
+	TableOptions map[string]*TableOptions
+	Indexes      []*Index
+	Views        map[string]*MaterializedView
+}
+
+// Querier runs the queries the schema is read with, e.g. the CQLExecutor of
+// the provider.
+type Querier interface {
+	// Select returns the rows of a query as maps of column name to value.
+	Select(ctx context.Context, query string, values ...interface{}) ([]map[string]interface{}, error)
+	// KeyspaceMetadata returns the metadata of a keyspace, or
+	// gocql.ErrKeyspaceDoesNotExist.
+	KeyspaceMetadata(keyspace string) (*gocql.KeyspaceMetadata, error)
+}
+
+// ReadKeyspace reads the keyspace, its types, tables, indexes and views.
+// schemaKeyspace is DefaultSchemaKeyspace or the one of the cluster.
+func ReadKeyspace(ctx context.Context, querier Querier, schemaKeyspace string, keyspace string) (*Keyspace, error) {
+	keyspaceMetadata, err := querier.KeyspaceMetadata(keyspace)
+	if err != nil {
+		return nil, err
+	}
+	keyspaceSchema := &Keyspace{
+		Keyspace:     keyspaceMetadata,
+		TableOptions: map[string]*TableOptions{},
+		Views:        map[string]*MaterializedView{},
+	}
+
+	if keyspaceSchema.UserTypes, err = ReadUserTypes(ctx, querier, schemaKeyspace, keyspace); err != nil {
+		return nil, err
+	}
+	for name := range keyspaceMetadata.Tables {
+		if keyspaceSchema.TableOptions[name], err = ReadTableOptions(ctx, querier, schemaKeyspace, keyspace, name); err != nil {
+			return nil, err
+		}
+	}
+	if keyspaceSchema.Indexes, err = ReadIndexes(ctx, querier, keyspace, ""); err != nil {
+		return nil, err
+	}
+	viewNames, err := ReadMaterializedViewNames(ctx, querier, keyspace, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range viewNames {
+		if keyspaceSchema.Views[name], err = ReadMaterializedView(ctx, querier, keyspace, name); err != nil {
+			return nil, err
+		}
+	}
+	return keyspaceSchema, nil
+}
+
+// selectOne returns the first row of a query, or gocql.ErrNotFound.
+func selectOne(ctx context.Context, querier Querier, query string, values ...interface{}) (map[string]interface{}, error) {
+	rows, err := querier.Select(ctx, query, values...)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, gocql.ErrNotFound
+	}
+	return rows[0], nil
+}
+
+// ReadTableOptions reads the options of a table by its stored name. It
+// returns gocql.ErrNotFound if the table does not exist.
+func ReadTableOptions(ctx context.Context, querier Querier, schemaKeyspace string, keyspace string, name string) (*TableOptions, error) {
+	query := fmt.Sprintf(`SELECT comment, default_time_to_live, gc_grace_seconds, speculative_retry, caching, compaction, compression FROM %s.tables WHERE keyspace_name = ? AND table_name = ?`, schemaKeyspace)
+	row, err := selectOne(ctx, querier, query, keyspace, name)
+	if err != nil {
+		return nil, err
+	}
+	options := &TableOptions{}
+	options.Comment, _ = row["comment"].(string)
+	options.DefaultTimeToLive, _ = row["default_time_to_live"].(int)
+	options.GCGraceSeconds, _ = row["gc_grace_seconds"].(int)
+	options.SpeculativeRetry, _ = row["speculative_retry"].(string)
+	options.Caching, _ = row["caching"].(map[string]string)
+	options.Compaction, _ = row["compaction"].(map[string]string)
+	options.Compression, _ = row["compression"].(map[string]string)
+	return options, nil
+}
+
+// ReadTableFlags reads the flags of a table by its stored name, e.g.
+// compound, dense or super. It returns gocql.ErrNotFound if the table does
+// not exist.
+func ReadTableFlags(ctx context.Context, querier Querier, schemaKeyspace string, keyspace string, name string) ([]string, error) {
+	query := fmt.Sprintf(`SELECT flags FROM %s.tables WHERE keyspace_name = ? AND table_name = ?`, schemaKeyspace)
+	row, err := selectOne(ctx, querier, query, keyspace, name)
+	if err != nil {
+		return nil, err
+	}
+	flags, _ := row["flags"].([]string)
+	return flags, nil
+}
+
+// CompactStorage reports whether the flags of a table mark it as created
+// WITH COMPACT STORAGE, which Cassandra 4.0 and later cannot create: tables
+// that are not compound, or are dense or super.
+func CompactStorage(flags []string) bool {
+	compound := false
+	for _, flag := range flags {
+		switch flag {
+		case "dense", "super":
+			return true
+		case "compound":
+			compound = true
+		}
+	}
+	return !compound
+}
+
+// ReadUserTypes reads the fields of all types of a keyspace by type name.
+func ReadUserTypes(ctx context.Context, querier Querier, schemaKeyspace string, keyspace string) (map[string][]Field, error) {
+	query := fmt.Sprintf(`SELECT type_name, field_names, field_types FROM %s.types WHERE keyspace_name = ?`, schemaKeyspace)
+	rows, err := querier.Select(ctx, query, keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	types := map[string][]Field{}
+	for _, row := range rows {
+		name, _ := row["type_name"].(string)
+		names, _ := row["field_names"].([]string)
+		fieldTypes, _ := row["field_types"].([]string)
+		types[name] = fields(names, fieldTypes)
+	}
+	return types, nil
+}
+
+// ReadUserTypeFields reads the fields of a type by its stored name. It
+// returns gocql.ErrNotFound if the type does not exist.
+func ReadUserTypeFields(ctx context.Context, querier Querier, schemaKeyspace string, keyspace string, name string) ([]Field, error) {
+	query := fmt.Sprintf(`SELECT field_names, field_types FROM %s.types WHERE keyspace_name = ? AND type_name = ?`, schemaKeyspace)
+	row, err := selectOne(ctx, querier, query, keyspace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	names, _ := row["field_names"].([]string)
+	types, _ := row["field_types"].([]string)
+	return fields(names, types), nil
+}
+
+// fields pairs the field_names and field_types stored in system_schema.types.
+func fields(names []string, types []string) []Field {
+	ret := make([]Field, 0, len(names))
+	for i, name := range names {
+		field := Field{Name: name}
+		if i < len(types) {
+			field.Type = types[i]
+		}
+		ret = append(ret, field)
+	}
+	return ret
+}
+
+// ReadIndexes reads the indexes of a keyspace, optionally narrowed down to
+// one table by its stored name.
+func ReadIndexes(ctx context.Context, querier Querier, keyspace string, table string) ([]*Index, error) {
+	var rows []map[string]interface{}
+	var err error
+	if table != "" {
+		rows, err = querier.Select(ctx, `SELECT index_name, table_name, kind, options FROM system_schema.indexes WHERE keyspace_name = ? AND table_name = ?`, keyspace, table)
+	} else {
+		rows, err = querier.Select(ctx, `SELECT index_name, table_name, kind, options FROM system_schema.indexes WHERE keyspace_name = ?`, keyspace)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := []*Index{}
+	for _, row := range rows {
+		index := &Index{}
+		index.Name, _ = row["index_name"].(string)
+		index.Table, _ = row["table_name"].(string)
+		index.Kind, _ = row["kind"].(string)
+		index.Options, _ = row["options"].(map[string]string)
+		indexes = append(indexes, index)
+	}
+	return indexes, nil
+}
+
+// CustomIndexOptions strips the options the cluster adds to every index from
+// the options stored in system_schema.indexes.
+func CustomIndexOptions(options map[string]string) map[string]string {
+	ret := map[string]string{}
+	for key, value := range options {
+		if key != "target" && key != "class_name" {
+			ret[key] = value
+		}
+	}
+	return ret
+}
+
+// ReadMaterializedViewNames lists the stored names of the views of a
+// keyspace, optionally narrowed down to one base table, sorted.
+func ReadMaterializedViewNames(ctx context.Context, querier Querier, keyspace string, baseTable string) ([]string, error) {
+	rows, err := querier.Select(ctx, `SELECT view_name, base_table_name FROM system_schema.views WHERE keyspace_name = ?`, keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, row := range rows {
+		name, _ := row["view_name"].(string)
+		table, _ := row["base_table_name"].(string)
+		if baseTable == "" || table == baseTable {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ReadMaterializedView reads the definition of a view by its stored name.
+// It returns gocql.ErrNotFound if the view does not exist.
+func ReadMaterializedView(ctx context.Context, querier Querier, keyspace string, name string) (*MaterializedView, error) {
+	row, err := selectOne(ctx, querier, `SELECT base_table_name, include_all_columns, where_clause FROM system_schema.views WHERE keyspace_name = ? AND view_name = ?`, keyspace, name)
+	if err != nil {
+		return nil, err
+	}
+	view := &MaterializedView{}
+	view.BaseTable, _ = row["base_table_name"].(string)
+	view.IncludeAllColumns, _ = row["include_all_columns"].(bool)
+	view.WhereClause, _ = row["where_clause"].(string)
+
+	rows, err := querier.Select(ctx, `SELECT column_name, kind, position FROM system_schema.columns WHERE keyspace_name = ? AND table_name = ?`, keyspace, name)
+	if err != nil {
+		return nil, err
+	}
+	partitionPositions := map[string]int{}
+	clusteringPositions := map[string]int{}
+	for _, row := range rows {
+		column, _ := row["column_name"].(string)
+		kind, _ := row["kind"].(string)
+		position, _ := row["position"].(int)
+		switch kind {
+		case "partition_key":
+			view.PartitionKeys = append(view.PartitionKeys, column)
+			partitionPositions[column] = position
+		case "clustering":
+			view.ClusteringKeys = append(view.ClusteringKeys, column)
+			clusteringPositions[column] = position
+		default:
+			view.Columns = append(view.Columns, column)
+		}
+	}
+
+	sort.Slice(view.PartitionKeys, func(i, j int) bool {
+		return partitionPositions[view.PartitionKeys[i]] < partitionPositions[view.PartitionKeys[j]]
+	})
+	sort.Slice(view.ClusteringKeys, func(i, j int) bool {
+		return clusteringPositions[view.ClusteringKeys[i]] < clusteringPositions[view.ClusteringKeys[j]]
+	})
+	sort.Strings(view.Columns)
+	return view, nil
+}
+
+// Columns lists the columns of a table in primary key order, followed by the
+// remaining columns sorted by name. The value column of COMPACT STORAGE
+// tables, whose type is empty, is left out.
+func Columns(metadata *gocql.TableMetadata) []*gocql.ColumnMetadata {
+	ordered := append(append([]*gocql.ColumnMetadata{}, metadata.PartitionKey...), metadata.ClusteringColumns...)
+	regular := []*gocql.ColumnMetadata{}
+	for _, column := range metadata.Columns {
+		if column.Validator == "empty" || strings.HasSuffix(column.Validator, ".EmptyType") {
+			continue
+		}
+		if column.Kind != gocql.ColumnPartitionKey && column.Kind != gocql.ColumnClusteringKey {
+			regular = append(regular, column)
+		}
+	}
+	sort.Slice(regular, func(i, j int) bool {
+		return regular[i].Name < regular[j].Name
+	})
+	return append(ordered, regular...)
+}
+
+// ColumnType returns the CQL type of an existing column, translating the
+// marshaller class names reported by older protocol versions.
+func ColumnType(column *gocql.ColumnMetadata) string {
+	if strings.HasPrefix(column.Validator, "org.apache.cassandra.") && column.Type != nil {
+		return column.Type.Type().String()
+	}
+	return column.Validator
+}
+
+// Replication returns the replication strategy and options of a keyspace,
+// with the package of built-in strategies left out.
+func Replication(keyspaceMetadata *gocql.KeyspaceMetadata) (string, map[string]string) {
+	strategyOptions := make(map[string]string, len(keyspaceMetadata.StrategyOptions))
+	for key, value := range keyspaceMetadata.StrategyOptions {
+		strategyOptions[key] = fmt.Sprintf("%v", value)
+	}
+	return strings.TrimPrefix(keyspaceMetadata.StrategyClass, "org.apache.cassandra.locator."), strategyOptions
+}
+
+// RenderReplication renders the replication map of a keyspace with its
+// options sorted by name.
+func RenderReplication(strategyClass string, strategyOptions map[string]string) string {
+	replication := "{ 'class' : " + cql.Literal(strategyClass)
+	keys := make([]string, 0, len(strategyOptions))
+	for key := range strategyOptions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		replication += fmt.Sprintf(`, %s : %s`, cql.Literal(key), cql.Literal(strategyOptions[key]))
+	}
+	return replication + " }"
+}
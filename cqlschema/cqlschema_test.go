@@ -0,0 +1,125 @@
+package cqlschema
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gocql/gocql"
+)
+
+func TestCustomIndexOptions(t *testing.T) {
+	options := CustomIndexOptions(map[string]string{"target": "name", "class_name": "org.apache.cassandra.index.sasi.SASIIndex", "mode": "CONTAINS"})
+	if len(options) != 1 || options["mode"] != "CONTAINS" {
+		t.Fatalf("expected only the mode option, got %v", options)
+	}
+}
+
+func TestReplication(t *testing.T) {
+	strategyClass, strategyOptions := Replication(&gocql.KeyspaceMetadata{
+		Name:            "some_keyspace",
+		StrategyClass:   "org.apache.cassandra.locator.NetworkTopologyStrategy",
+		StrategyOptions: map[string]interface{}{"dc1": "3", "dc2": 5},
+	})
+	if strategyClass != "NetworkTopologyStrategy" {
+		t.Fatalf("unexpected strategy class %s", strategyClass)
+	}
+	if len(strategyOptions) != 2 || strategyOptions["dc2"] != "5" {
+		t.Fatalf("expected the live replication factors, got %v", strategyOptions)
+	}
+}
+
+func TestRenderReplication(t *testing.T) {
+	expected := `{ 'class' : 'NetworkTopologyStrategy', 'dc''2' : '5', 'dc1' : '3' }`
+	if replication := RenderReplication("NetworkTopologyStrategy", map[string]string{"dc'2": "5", "dc1": "3"}); replication != expected {
+		t.Fatalf("expected %s, got %s", expected, replication)
+	}
+}
+
+func TestCompactStorage(t *testing.T) {
+	for _, test := range []struct {
+		flags    []string
+		expected bool
+	}{
+		{[]string{"compound"}, false},
+		{[]string{"compound", "counter"}, false},
+		{[]string{}, true},
+		{[]string{"dense"}, true},
+		{[]string{"compound", "dense"}, true},
+		{[]string{"super"}, true},
+	} {
+		if compact := CompactStorage(test.flags); compact != test.expected {
+			t.Fatalf("expected flags %v to be compact: %t", test.flags, test.expected)
+		}
+	}
+}
+
+func TestColumns(t *testing.T) {
+	key := &gocql.ColumnMetadata{Name: "key", Kind: gocql.ColumnPartitionKey, Validator: "text"}
+	column1 := &gocql.ColumnMetadata{Name: "column1", Kind: gocql.ColumnClusteringKey, Validator: "text"}
+	columns := Columns(&gocql.TableMetadata{
+		PartitionKey:      []*gocql.ColumnMetadata{key},
+		ClusteringColumns: []*gocql.ColumnMetadata{column1},
+		Columns: map[string]*gocql.ColumnMetadata{
+			"key":     key,
+			"column1": column1,
+			"value":   {Name: "value", Kind: gocql.ColumnRegular, Validator: "empty"},
+			"b":       {Name: "b", Kind: gocql.ColumnStatic, Validator: "int"},
+			"a":       {Name: "a", Kind: gocql.ColumnStatic, Validator: "int"},
+		},
+	})
+
+	names := []string{}
+	for _, column := range columns {
+		names = append(names, column.Name)
+	}
+	if strings.Join(names, ",") != "key,column1,a,b" {
+		t.Fatalf("expected key,column1,a,b without the empty value column, got %v", names)
+	}
+}
+
+// fakeQuerier answers queries with the rows canned for the query and its
+// bound values.
+type fakeQuerier map[string][]map[string]interface{}
+
+func (q fakeQuerier) Select(ctx context.Context, query string, values ...interface{}) ([]map[string]interface{}, error) {
+	return q[fmt.Sprintf("%s %v", query, values)], nil
+}
+
+func (q fakeQuerier) KeyspaceMetadata(keyspace string) (*gocql.KeyspaceMetadata, error) {
+	return nil, gocql.ErrKeyspaceDoesNotExist
+}
+
+func TestReadMaterializedView(t *testing.T) {
+	querier := fakeQuerier{
+		`SELECT base_table_name, include_all_columns, where_clause FROM system_schema.views WHERE keyspace_name = ? AND view_name = ? [app users_by_email]`: {
+			{"base_table_name": "users", "include_all_columns": false, "where_clause": "email IS NOT NULL AND id IS NOT NULL"},
+		},
+		`SELECT column_name, kind, position FROM system_schema.columns WHERE keyspace_name = ? AND table_name = ? [app users_by_email]`: {
+			{"column_name": "name", "kind": "regular", "position": -1},
+			{"column_name": "id", "kind": "clustering", "position": 0},
+			{"column_name": "email", "kind": "partition_key", "position": 0},
+		},
+	}
+
+	view, err := ReadMaterializedView(context.Background(), querier, "app", "users_by_email")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &MaterializedView{
+		BaseTable:      "users",
+		WhereClause:    "email IS NOT NULL AND id IS NOT NULL",
+		PartitionKeys:  []string{"email"},
+		ClusteringKeys: []string{"id"},
+		Columns:        []string{"name"},
+	}
+	if !reflect.DeepEqual(view, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, view)
+	}
+
+	if _, err := ReadMaterializedView(context.Background(), querier, "app", "missing"); err != gocql.ErrNotFound {
+		t.Fatalf("expected %s, got %v", gocql.ErrNotFound, err)
+	}
+}
@@ -0,0 +1,198 @@
+package cqlschema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gocql/gocql"
+
+	"github.com/konradotto/terraform-provider-cassandra/cql"
+)
+
+// KeyspaceStatements renders the CREATE statements of a keyspace in an order
+// they can be replayed in: the keyspace, its types, each table followed by
+// its indexes, then the materialized views.
+func KeyspaceStatements(keyspaceSchema *Keyspace) []string {
+	keyspace := keyspaceSchema.Keyspace
+	strategyClass, strategyOptions := Replication(keyspace)
+	statements := []string{fmt.Sprintf(`CREATE KEYSPACE %s WITH REPLICATION = %s AND DURABLE_WRITES = %t`,
+		cql.Identifier(keyspace.Name), RenderReplication(strategyClass, strategyOptions), keyspace.DurableWrites)}
+
+	for _, name := range sortUserTypesByDependency(keyspaceSchema.UserTypes) {
+		fields := make([]string, 0, len(keyspaceSchema.UserTypes[name]))
+		for _, field := range keyspaceSchema.UserTypes[name] {
+			fields = append(fields, fmt.Sprintf("%s %s", cql.Identifier(field.Name), field.Type))
+		}
+		statements = append(statements, fmt.Sprintf(`CREATE TYPE %s (%s)`, cql.QualifiedName(keyspace.Name, name), strings.Join(fields, ", ")))
+	}
+
+	tableNames := make([]string, 0, len(keyspace.Tables))
+	for name := range keyspace.Tables {
+		tableNames = append(tableNames, name)
+	}
+	sort.Strings(tableNames)
+	indexes := append([]*Index{}, keyspaceSchema.Indexes...)
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i].Name < indexes[j].Name
+	})
+	for _, name := range tableNames {
+		statements = append(statements, TableStatement(keyspace.Tables[name], keyspaceSchema.TableOptions[name]))
+		for _, index := range indexes {
+			if index.Table == name {
+				statements = append(statements, IndexStatement(keyspace.Name, index))
+			}
+		}
+	}
+
+	viewNames := make([]string, 0, len(keyspaceSchema.Views))
+	for name := range keyspaceSchema.Views {
+		viewNames = append(viewNames, name)
+	}
+	sort.Strings(viewNames)
+	for _, name := range viewNames {
+		statements = append(statements, MaterializedViewStatement(keyspace.Name, name, keyspaceSchema.Views[name]))
+	}
+	return statements
+}
+
+func identifiers(names []string) []string {
+	ret := make([]string, 0, len(names))
+	for _, name := range names {
+		ret = append(ret, cql.Identifier(name))
+	}
+	return ret
+}
+
+func columnNames(columns []*gocql.ColumnMetadata) []string {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, column.Name)
+	}
+	return names
+}
+
+// sortUserTypesByDependency sorts types by name, moving each type after the
+// types its fields use.
+func sortUserTypesByDependency(userTypes map[string][]Field) []string {
+	names := make([]string, 0, len(userTypes))
+	for name := range userTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sorted := make([]string, 0, len(names))
+	visited := map[string]bool{}
+	var visit func(name string)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		for _, field := range userTypes[name] {
+			tokens, _ := cql.TokenizeType(field.Type)
+			for _, token := range tokens {
+				if dependency := cql.IdentifierName(token); dependency != name && userTypes[dependency] != nil {
+					visit(dependency)
+				}
+			}
+		}
+		sorted = append(sorted, name)
+	}
+	for _, name := range names {
+		visit(name)
+	}
+	return sorted
+}
+
+// TableStatement renders the CREATE TABLE statement of a table with its
+// options, which may be nil.
+func TableStatement(metadata *gocql.TableMetadata, options *TableOptions) string {
+	definitions := []string{}
+	for _, column := range Columns(metadata) {
+		definition := fmt.Sprintf("%s %s", cql.Identifier(column.Name), ColumnType(column))
+		if column.Kind == gocql.ColumnStatic {
+			definition += " static"
+		}
+		definitions = append(definitions, definition)
+	}
+
+	primaryKey := fmt.Sprintf("(%s)", strings.Join(identifiers(columnNames(metadata.PartitionKey)), ", "))
+	if len(metadata.ClusteringColumns) > 0 {
+		primaryKey += ", " + strings.Join(identifiers(columnNames(metadata.ClusteringColumns)), ", ")
+	}
+	definitions = append(definitions, fmt.Sprintf("PRIMARY KEY (%s)", primaryKey))
+
+	properties := []string{}
+	if len(metadata.ClusteringColumns) > 0 {
+		orders := make([]string, 0, len(metadata.ClusteringColumns))
+		for _, column := range metadata.ClusteringColumns {
+			order := "ASC"
+			if strings.EqualFold(column.ClusteringOrder, "desc") {
+				order = "DESC"
+			}
+			orders = append(orders, fmt.Sprintf("%s %s", cql.Identifier(column.Name), order))
+		}
+		properties = append(properties, fmt.Sprintf("CLUSTERING ORDER BY (%s)", strings.Join(orders, ", ")))
+	}
+	if options != nil {
+		for _, option := range []struct {
+			name  string
+			value map[string]string
+		}{
+			{"caching", options.Caching},
+			{"compaction", options.Compaction},
+			{"compression", options.Compression},
+		} {
+			if len(option.value) > 0 {
+				properties = append(properties, fmt.Sprintf("%s = %s", option.name, cql.Map(option.value)))
+			}
+		}
+		properties = append(properties,
+			"comment = "+cql.Literal(options.Comment),
+			fmt.Sprintf("default_time_to_live = %d", options.DefaultTimeToLive),
+			fmt.Sprintf("gc_grace_seconds = %d", options.GCGraceSeconds),
+		)
+		if options.SpeculativeRetry != "" {
+			properties = append(properties, "speculative_retry = "+cql.Literal(options.SpeculativeRetry))
+		}
+	}
+
+	query := fmt.Sprintf("CREATE TABLE %s (\n    %s\n)", cql.QualifiedName(metadata.Keyspace, metadata.Name), strings.Join(definitions, ",\n    "))
+	if len(properties) > 0 {
+		query += " WITH " + strings.Join(properties, "\n    AND ")
+	}
+	return query
+}
+
+// IndexStatement renders the CREATE INDEX statement of an index of keyspace.
+func IndexStatement(keyspace string, index *Index) string {
+	table := cql.QualifiedName(keyspace, index.Table)
+	class := index.Options["class_name"]
+	if class == "" {
+		return fmt.Sprintf(`CREATE INDEX %s ON %s (%s)`, cql.Identifier(index.Name), table, index.Options["target"])
+	}
+
+	query := fmt.Sprintf(`CREATE CUSTOM INDEX %s ON %s (%s) USING %s`, cql.Identifier(index.Name), table, index.Options["target"], cql.Literal(class))
+	if options := CustomIndexOptions(index.Options); len(options) > 0 {
+		query += " WITH OPTIONS = " + cql.Map(options)
+	}
+	return query
+}
+
+// MaterializedViewStatement renders the CREATE MATERIALIZED VIEW statement
+// of the view name of keyspace.
+func MaterializedViewStatement(keyspace string, name string, view *MaterializedView) string {
+	selection := "*"
+	if !view.IncludeAllColumns {
+		columns := append(append(append([]string{}, view.PartitionKeys...), view.ClusteringKeys...), view.Columns...)
+		selection = strings.Join(identifiers(columns), ", ")
+	}
+
+	primaryKey := fmt.Sprintf("(%s)", strings.Join(identifiers(view.PartitionKeys), ", "))
+	if len(view.ClusteringKeys) > 0 {
+		primaryKey += ", " + strings.Join(identifiers(view.ClusteringKeys), ", ")
+	}
+	return fmt.Sprintf("CREATE MATERIALIZED VIEW %s AS\n    SELECT %s\n    FROM %s\n    WHERE %s\n    PRIMARY KEY (%s)",
+		cql.QualifiedName(keyspace, name), selection, cql.QualifiedName(keyspace, view.BaseTable), view.WhereClause, primaryKey)
+}
@@ -0,0 +1,81 @@
+package cqlschema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gocql/gocql"
+)
+
+func TestKeyspaceStatements(t *testing.T) {
+	id := &gocql.ColumnMetadata{Name: "id", Kind: gocql.ColumnPartitionKey, Validator: "uuid"}
+	createdAt := &gocql.ColumnMetadata{Name: "CreatedAt", Kind: gocql.ColumnClusteringKey, Validator: "timestamp", ClusteringOrder: "desc"}
+	owner := &gocql.ColumnMetadata{Name: "owner", Kind: gocql.ColumnStatic, Validator: "frozen<person>"}
+	email := &gocql.ColumnMetadata{Name: "email", Kind: gocql.ColumnRegular, Validator: "text"}
+
+	statements := KeyspaceStatements(&Keyspace{
+		Keyspace: &gocql.KeyspaceMetadata{
+			Name:            "app",
+			DurableWrites:   true,
+			StrategyClass:   "org.apache.cassandra.locator.SimpleStrategy",
+			StrategyOptions: map[string]interface{}{"replication_factor": "3"},
+			Tables: map[string]*gocql.TableMetadata{
+				"events": {
+					Keyspace:          "app",
+					Name:              "events",
+					PartitionKey:      []*gocql.ColumnMetadata{id},
+					ClusteringColumns: []*gocql.ColumnMetadata{createdAt},
+					Columns:           map[string]*gocql.ColumnMetadata{"id": id, "CreatedAt": createdAt, "owner": owner, "email": email},
+				},
+			},
+		},
+		UserTypes: map[string][]Field{
+			"person":  {{Name: "name", Type: "text"}, {Name: "address", Type: "frozen<address>"}},
+			"address": {{Name: "street", Type: "text"}},
+		},
+		TableOptions: map[string]*TableOptions{
+			"events": {Comment: "it's", GCGraceSeconds: 864000, SpeculativeRetry: "99p", Compaction: map[string]string{"class": "LeveledCompactionStrategy"}},
+		},
+		Indexes: []*Index{
+			{Name: "events_by_email", Table: "events", Kind: "COMPOSITES", Options: map[string]string{"target": "email"}},
+		},
+		Views: map[string]*MaterializedView{
+			"events_by_email": {
+				BaseTable:      "events",
+				WhereClause:    "email IS NOT NULL AND id IS NOT NULL AND \"CreatedAt\" IS NOT NULL",
+				PartitionKeys:  []string{"email"},
+				ClusteringKeys: []string{"id", "CreatedAt"},
+			},
+		},
+	})
+
+	expected := []string{
+		`CREATE KEYSPACE app WITH REPLICATION = { 'class' : 'SimpleStrategy', 'replication_factor' : '3' } AND DURABLE_WRITES = true`,
+		`CREATE TYPE app.address (street text)`,
+		`CREATE TYPE app.person (name text, address frozen<address>)`,
+		"CREATE TABLE app.events (\n    id uuid,\n    \"CreatedAt\" timestamp,\n    email text,\n    owner frozen<person> static,\n    PRIMARY KEY ((id), \"CreatedAt\")\n)" +
+			" WITH CLUSTERING ORDER BY (\"CreatedAt\" DESC)\n    AND compaction = {'class':'LeveledCompactionStrategy'}\n    AND comment = 'it''s'\n    AND default_time_to_live = 0\n    AND gc_grace_seconds = 864000\n    AND speculative_retry = '99p'",
+		`CREATE INDEX events_by_email ON app.events (email)`,
+		"CREATE MATERIALIZED VIEW app.events_by_email AS\n    SELECT email, id, \"CreatedAt\"\n    FROM app.events\n    WHERE email IS NOT NULL AND id IS NOT NULL AND \"CreatedAt\" IS NOT NULL\n    PRIMARY KEY ((email), id, \"CreatedAt\")",
+	}
+	if len(statements) != len(expected) {
+		t.Fatalf("expected %d statements, got %d:\n%s", len(expected), len(statements), strings.Join(statements, "\n"))
+	}
+	for i := range expected {
+		if statements[i] != expected[i] {
+			t.Fatalf("expected statement %d to be\n%s\ngot\n%s", i, expected[i], statements[i])
+		}
+	}
+}
+
+func TestIndexStatement(t *testing.T) {
+	query := IndexStatement("app", &Index{Name: "events_by_tag", Table: "events", Options: map[string]string{
+		"target":         "values(tags)",
+		"class_name":     "org.apache.cassandra.index.sai.StorageAttachedIndex",
+		"case_sensitive": "false",
+	}})
+	expected := `CREATE CUSTOM INDEX events_by_tag ON app.events (values(tags)) USING 'org.apache.cassandra.index.sai.StorageAttachedIndex' WITH OPTIONS = {'case_sensitive':'false'}`
+	if query != expected {
+		t.Fatalf("expected %s, got %s", expected, query)
+	}
+}
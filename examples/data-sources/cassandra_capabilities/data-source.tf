@@ -0,0 +1,10 @@
+data "cassandra_capabilities" "cluster" {}
+
+resource "cassandra_index" "email" {
+  count = data.cassandra_capabilities.cluster.supports_sai ? 1 : 0
+
+  keyspace   = "app"
+  table      = "users"
+  column     = "email"
+  index_type = "sai"
+}
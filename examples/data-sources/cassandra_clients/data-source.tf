@@ -0,0 +1,8 @@
+data "cassandra_clients" "this" {
+  lifecycle {
+    postcondition {
+      condition     = self.unencrypted_count == 0
+      error_message = "Plaintext clients remain: ${join(", ", [for c in self.clients : "${c.username}@${c.address}" if !c.ssl_enabled])}"
+    }
+  }
+}
@@ -0,0 +1,11 @@
+data "cassandra_cluster_info" "this" {}
+
+resource "cassandra_keyspace" "app" {
+  name                 = "app"
+  replication_strategy = "NetworkTopologyStrategy"
+  datacenters          = { for dc in data.cassandra_cluster_info.this.datacenters : dc => 3 }
+}
+
+output "supports_sai" {
+  value = data.cassandra_cluster_info.this.major_version >= 5
+}
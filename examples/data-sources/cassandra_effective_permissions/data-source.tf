@@ -0,0 +1,10 @@
+data "cassandra_effective_permissions" "reporting" {
+  role = "reporting"
+}
+
+check "reporting_is_read_only" {
+  assert {
+    condition     = alltrue([for permission in data.cassandra_effective_permissions.reporting.permissions : contains(["SELECT", "DESCRIBE", "EXECUTE"], permission.permission)])
+    error_message = "The reporting role can do more than read."
+  }
+}
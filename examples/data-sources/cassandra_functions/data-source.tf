@@ -0,0 +1,10 @@
+data "cassandra_functions" "analytics" {
+  keyspace = "analytics"
+}
+
+resource "cassandra_cql_exec" "execute" {
+  for_each = toset([for f in data.cassandra_functions.analytics.functions : f.signature])
+
+  create_cql  = "GRANT EXECUTE ON FUNCTION analytics.${each.value} TO reporting"
+  destroy_cql = "REVOKE EXECUTE ON FUNCTION analytics.${each.value} FROM reporting"
+}
@@ -0,0 +1,7 @@
+data "cassandra_indexes" "app" {
+  keyspace = "my-keyspace"
+}
+
+output "legacy_secondary_indexes" {
+  value = [for index in data.cassandra_indexes.app.indexes : "${index.table}.${index.name}" if index.index_type == "secondary"]
+}
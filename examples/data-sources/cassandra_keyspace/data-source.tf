@@ -0,0 +1,7 @@
+data "cassandra_keyspace" "app" {
+  name = "my-keyspace"
+}
+
+output "app_replication" {
+  value = data.cassandra_keyspace.app.strategy_options
+}
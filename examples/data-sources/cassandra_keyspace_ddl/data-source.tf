@@ -0,0 +1,8 @@
+data "cassandra_keyspace_ddl" "app" {
+  keyspace = "my-keyspace"
+}
+
+resource "local_file" "schema_snapshot" {
+  filename = "${path.module}/schema/my-keyspace.cql"
+  content  = data.cassandra_keyspace_ddl.app.ddl
+}
@@ -0,0 +1,5 @@
+data "cassandra_keyspaces" "all" {}
+
+output "keyspaces" {
+  value = data.cassandra_keyspaces.all.names
+}
@@ -0,0 +1,14 @@
+data "cassandra_materialized_views" "users" {
+  keyspace   = "my-keyspace"
+  base_table = "users"
+}
+
+resource "cassandra_grant" "read_views" {
+  for_each = toset(data.cassandra_materialized_views.users.names)
+
+  privilege     = "select"
+  resource_type = "table"
+  keyspace_name = "my-keyspace"
+  table_name    = each.value
+  grantee       = "reader"
+}
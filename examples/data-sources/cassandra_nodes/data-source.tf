@@ -0,0 +1,7 @@
+data "cassandra_nodes" "eu" {
+  datacenter = "eu-west"
+}
+
+output "eu_racks" {
+  value = distinct([for node in data.cassandra_nodes.eu.nodes : node.rack])
+}
@@ -0,0 +1,12 @@
+data "cassandra_query" "datacenters" {
+  query      = "SELECT datacenter, replication_factor FROM ops.datacenters WHERE environment = ?"
+  parameters = ["production"]
+}
+
+resource "cassandra_keyspace" "app" {
+  name                 = "app"
+  replication_strategy = "NetworkTopologyStrategy"
+  datacenters = {
+    for row in data.cassandra_query.datacenters.rows : row.datacenter => row.replication_factor
+  }
+}
@@ -0,0 +1,7 @@
+data "cassandra_role_members" "admin" {
+  role = "admin"
+}
+
+output "admins" {
+  value = data.cassandra_role_members.admin.all_members
+}
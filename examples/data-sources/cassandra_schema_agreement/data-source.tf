@@ -0,0 +1,8 @@
+data "cassandra_schema_agreement" "this" {
+  lifecycle {
+    postcondition {
+      condition     = self.agreement
+      error_message = "Nodes disagree on the schema: ${jsonencode(self.schema_versions)}"
+    }
+  }
+}
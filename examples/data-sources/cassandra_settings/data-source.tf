@@ -0,0 +1,10 @@
+data "cassandra_settings" "auth" {
+  names = ["authenticator", "authorizer"]
+
+  lifecycle {
+    postcondition {
+      condition     = !endswith(self.settings["authorizer"], "AllowAllAuthorizer")
+      error_message = "Grants are not enforced, the cluster uses AllowAllAuthorizer"
+    }
+  }
+}
@@ -0,0 +1,8 @@
+data "cassandra_table" "events" {
+  keyspace = "my-keyspace"
+  name     = "events"
+}
+
+output "event_columns" {
+  value = data.cassandra_table.events.columns[*].name
+}
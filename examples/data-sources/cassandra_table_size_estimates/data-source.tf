@@ -0,0 +1,11 @@
+data "cassandra_table_size_estimates" "events" {
+  keyspace = "app"
+  table    = "events"
+}
+
+check "events_not_empty" {
+  assert {
+    condition     = data.cassandra_table_size_estimates.events.range_count == 0 || data.cassandra_table_size_estimates.events.partitions_count > 0
+    error_message = "app.events is unexpectedly empty."
+  }
+}
@@ -0,0 +1,13 @@
+data "cassandra_tables" "app" {
+  keyspace = "my-keyspace"
+}
+
+resource "cassandra_grant" "read_all" {
+  for_each = toset(data.cassandra_tables.app.names)
+
+  privilege     = "select"
+  resource_type = "table"
+  keyspace_name = "my-keyspace"
+  table_name    = each.value
+  grantee       = "reader"
+}
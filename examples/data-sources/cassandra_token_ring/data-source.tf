@@ -0,0 +1,22 @@
+data "cassandra_token_ring" "eu" {
+  datacenter = "eu-west"
+}
+
+locals {
+  max_ownership = max([for node in data.cassandra_token_ring.eu.nodes : node.ownership]...)
+}
+
+resource "cassandra_keyspace" "app" {
+  name                 = "app"
+  replication_strategy = "NetworkTopologyStrategy"
+  strategy_options = {
+    eu-west = 3
+  }
+
+  lifecycle {
+    precondition {
+      condition     = local.max_ownership < 40
+      error_message = "The token ring of eu-west is unbalanced, rebalance it before changing replication."
+    }
+  }
+}
@@ -0,0 +1,7 @@
+data "cassandra_types" "app" {
+  keyspace = "my-keyspace"
+}
+
+output "address_fields" {
+  value = [for t in data.cassandra_types.app.types : t.field if t.name == "address"][0]
+}
@@ -0,0 +1,10 @@
+ephemeral "cassandra_query" "bootstrap" {
+  query      = "SELECT value FROM ops.secrets WHERE name = ?"
+  parameters = ["grafana_password"]
+}
+
+provider "cassandra" {
+  alias    = "grafana"
+  username = "grafana"
+  password = ephemeral.cassandra_query.bootstrap.rows[0].value
+}
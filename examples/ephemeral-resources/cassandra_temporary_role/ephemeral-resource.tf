@@ -0,0 +1,9 @@
+ephemeral "cassandra_temporary_role" "migrations" {
+  member_of = ["schema_admin"]
+}
+
+provider "cassandra" {
+  alias    = "migrations"
+  username = ephemeral.cassandra_temporary_role.migrations.name
+  password = ephemeral.cassandra_temporary_role.migrations.password
+}
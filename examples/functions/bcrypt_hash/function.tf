@@ -0,0 +1,22 @@
+terraform {
+  required_providers {
+    cassandra = {
+      source = "konradotto/cassandra"
+    }
+  }
+}
+
+variable "app_password" {
+  type      = string
+  sensitive = true
+}
+
+resource "random_password" "app_salt" {
+  length  = 22
+  special = false
+}
+
+resource "cassandra_role" "app" {
+  name            = "app"
+  hashed_password = provider::cassandra::bcrypt_hash(var.app_password, 10, random_password.app_salt.result)
+}
@@ -0,0 +1,29 @@
+terraform {
+  required_providers {
+    cassandra = {
+      source = "konradotto/cassandra"
+    }
+  }
+}
+
+variable "fields" {
+  type = map(string)
+  default = {
+    street = "varchar"
+    phones = "set<text>"
+    owner  = "frozen<person>"
+  }
+}
+
+resource "cassandra_type" "address" {
+  keyspace = "app"
+  name     = "address"
+
+  dynamic "field" {
+    for_each = var.fields
+    content {
+      name = field.key
+      type = provider::cassandra::cql_type(field.value, "person")
+    }
+  }
+}
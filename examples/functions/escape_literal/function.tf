@@ -0,0 +1,12 @@
+terraform {
+  required_providers {
+    cassandra = {
+      source = "konradotto/cassandra"
+    }
+  }
+}
+
+resource "cassandra_cql_exec" "feature_flag" {
+  create_cql  = "INSERT INTO ops.flags (name, description) VALUES ('search', ${provider::cassandra::escape_literal(var.description)})"
+  destroy_cql = "DELETE FROM ops.flags WHERE name = 'search'"
+}
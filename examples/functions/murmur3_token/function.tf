@@ -0,0 +1,11 @@
+terraform {
+  required_providers {
+    cassandra = {
+      source = "konradotto/cassandra"
+    }
+  }
+}
+
+output "tenant_token" {
+  value = provider::cassandra::murmur3_token(["text", "int"], ["acme", 2024])
+}
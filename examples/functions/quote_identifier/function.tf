@@ -0,0 +1,11 @@
+terraform {
+  required_providers {
+    cassandra = {
+      source = "konradotto/cassandra"
+    }
+  }
+}
+
+resource "cassandra_cql_exec" "events" {
+  create_cql = "CREATE TABLE IF NOT EXISTS app.${provider::cassandra::quote_identifier(var.table_name)} (id uuid PRIMARY KEY)"
+}
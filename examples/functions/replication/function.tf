@@ -0,0 +1,22 @@
+terraform {
+  required_providers {
+    cassandra = {
+      source = "konradotto/cassandra"
+    }
+  }
+}
+
+locals {
+  replication = provider::cassandra::replication("NetworkTopologyStrategy", { eu-west = 3, us-east = 3 })
+}
+
+resource "cassandra_keyspace" "app" {
+  name                 = "app"
+  replication_strategy = local.replication.class
+  strategy_options     = local.replication.options
+}
+
+resource "cassandra_cql_exec" "audit_keyspace" {
+  create_cql  = "CREATE KEYSPACE IF NOT EXISTS audit WITH REPLICATION = ${local.replication.cql}"
+  destroy_cql = "DROP KEYSPACE IF EXISTS audit"
+}
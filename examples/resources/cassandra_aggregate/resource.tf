@@ -0,0 +1,8 @@
+resource "cassandra_aggregate" "total" {
+  keyspace          = "my-keyspace"
+  name              = "total"
+  argument_types    = ["int"]
+  state_function    = cassandra_function.state_sum.name
+  state_type        = "bigint"
+  initial_condition = "0"
+}
@@ -0,0 +1,5 @@
+resource "cassandra_cql_exec" "events_cdc" {
+  create_cql   = "ALTER TABLE \"my-keyspace\".events WITH cdc = true"
+  destroy_cql  = "ALTER TABLE \"my-keyspace\".events WITH cdc = false"
+  exists_query = "SELECT cdc FROM system_schema.tables WHERE keyspace_name = 'my-keyspace' AND table_name = 'events' AND cdc = true ALLOW FILTERING"
+}
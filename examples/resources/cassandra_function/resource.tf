@@ -0,0 +1,18 @@
+resource "cassandra_function" "state_sum" {
+  keyspace    = "my-keyspace"
+  name        = "state_sum"
+  return_type = "bigint"
+
+  argument {
+    name = "state"
+    type = "bigint"
+  }
+
+  argument {
+    name = "value"
+    type = "int"
+  }
+
+  called_on_null_input = true
+  body                 = "return value == null ? state : state + value;"
+}
@@ -0,0 +1,37 @@
+resource "cassandra_index" "users_by_email" {
+  keyspace = "my-keyspace"
+  table    = "users"
+  column   = "email"
+  name     = "users_by_email"
+}
+
+resource "cassandra_index" "products_by_embedding" {
+  keyspace   = "my-keyspace"
+  table      = "products"
+  column     = "embedding"
+  index_type = "sai"
+
+  sai {
+    similarity_function = "COSINE"
+  }
+}
+
+resource "cassandra_index" "users_by_name" {
+  keyspace   = "my-keyspace"
+  table      = "users"
+  column     = "name"
+  index_type = "sasi"
+
+  options = {
+    mode           = "CONTAINS"
+    analyzer_class = "org.apache.cassandra.index.sasi.analyzer.NonTokenizingAnalyzer"
+    case_sensitive = "false"
+  }
+}
+
+resource "cassandra_index" "users_by_attribute_key" {
+  keyspace    = "my-keyspace"
+  table       = "users"
+  column      = "attributes"
+  target_kind = "KEYS"
+}
@@ -9,3 +9,13 @@ resource "cassandra_keyspace" "keyspace" {
   replication_strategy = "SimpleStrategy"
   strategy_options     = local.strategy_options
 }
+
+resource "cassandra_keyspace" "multi_dc" {
+  name                 = "some_multi_dc_keyspace"
+  replication_strategy = "NetworkTopologyStrategy"
+
+  datacenters = {
+    dc1 = "3"
+    dc2 = "3"
+  }
+}
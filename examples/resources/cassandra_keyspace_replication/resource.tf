@@ -0,0 +1,9 @@
+resource "cassandra_keyspace_replication" "app" {
+  keyspace             = "app"
+  replication_strategy = "NetworkTopologyStrategy"
+
+  datacenters = {
+    dc1 = "3"
+    dc2 = "3"
+  }
+}
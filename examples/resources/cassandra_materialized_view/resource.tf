@@ -0,0 +1,12 @@
+resource "cassandra_materialized_view" "users_by_email" {
+  keyspace        = "my-keyspace"
+  name            = "users_by_email"
+  base_table      = "users"
+  columns         = ["name", "created_at"]
+  partition_keys  = ["email"]
+  clustering_keys = ["id"]
+
+  options = {
+    comment = "'Users looked up by email'"
+  }
+}
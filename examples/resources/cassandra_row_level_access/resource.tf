@@ -0,0 +1,17 @@
+resource "cassandra_row_level_access" "accounts" {
+  keyspace = "my-keyspace"
+  table    = "accounts"
+  column   = "region"
+
+  grant {
+    role           = "emea_support"
+    filtering_data = "EMEA"
+    permissions    = ["select", "modify"]
+  }
+
+  grant {
+    role           = "auditor"
+    filtering_data = "EMEA"
+    permissions    = ["select"]
+  }
+}
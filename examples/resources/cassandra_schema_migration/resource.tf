@@ -0,0 +1,4 @@
+resource "cassandra_schema_migration" "app" {
+  keyspace  = cassandra_keyspace.keyspace.name
+  directory = "${path.module}/migrations"
+}
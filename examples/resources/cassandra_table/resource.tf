@@ -1,7 +1,7 @@
 resource "cassandra_table" "table" {
   name     = "my_table"
   keyspace = "my-keyspace"
-  row_keys = ["Name"]
+  row_keys = ["name"]
 
   attribute {
     name = "name"
@@ -11,5 +11,10 @@ resource "cassandra_table" "table" {
   attribute {
     name = "email"
     type = "S"
+
+    mask {
+      function = "mask_inner"
+      args     = ["1", "null"]
+    }
   }
 }
@@ -0,0 +1,6 @@
+resource "cassandra_table_column" "nickname" {
+  keyspace = "my-keyspace"
+  table    = "my_table"
+  name     = "nickname"
+  type     = "text"
+}
@@ -0,0 +1,12 @@
+resource "cassandra_table_options" "events" {
+  keyspace         = "my-keyspace"
+  table            = "events"
+  gc_grace_seconds = 86400
+  comment          = "Tuning managed by Terraform"
+
+  compaction = {
+    class                  = "TimeWindowCompactionStrategy"
+    compaction_window_unit = "DAYS"
+    compaction_window_size = "1"
+  }
+}
@@ -0,0 +1,6 @@
+resource "cassandra_trigger" "audit" {
+  keyspace = "my-keyspace"
+  table    = "events"
+  name     = "audit"
+  class    = "com.example.triggers.AuditTrigger"
+}
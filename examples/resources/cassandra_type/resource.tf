@@ -0,0 +1,19 @@
+resource "cassandra_type" "address" {
+  keyspace = "my-keyspace"
+  name     = "address"
+
+  field {
+    name = "street"
+    type = "text"
+  }
+
+  field {
+    name = "zip_code"
+    type = "text"
+  }
+
+  field {
+    name = "phones"
+    type = "set<text>"
+  }
+}
@@ -0,0 +1,4 @@
+resource "cassandra_user" "user" {
+  name     = "app_user"
+  password = "sup3rS3cr3tPa$$w0rd"
+}
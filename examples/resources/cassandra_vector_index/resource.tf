@@ -0,0 +1,8 @@
+resource "cassandra_vector_index" "products_ann" {
+  keyspace            = "my-keyspace"
+  table               = "products"
+  column              = "embedding"
+  name                = "products_ann"
+  similarity_function = "DOT_PRODUCT"
+  source_model        = "OPENAI_V3_SMALL"
+}
@@ -5,9 +5,9 @@ import (
 	"flag"
 	"log"
 
-	"github.com/konradotto/terraform-provider-cassandra/cassandra"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"github.com/konradotto/terraform-provider-cassandra/cassandra"
 )
 
 func main() {
@@ -16,8 +16,8 @@ func main() {
 	flag.Parse()
 
 	opts := &plugin.ServeOpts{
-		ProviderFunc: func() *schema.Provider {
-			return cassandra.Provider()
+		GRPCProviderFunc: func() tfprotov5.ProviderServer {
+			return cassandra.NewProviderServer(cassandra.Provider())
 		},
 	}
 
@@ -30,6 +30,4 @@ func main() {
 	}
 
 	plugin.Serve(opts)
-
-	plugin.Serve(&plugin.ServeOpts{ProviderFunc: cassandra.Provider})
 }